@@ -0,0 +1,613 @@
+package dbclient
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+// CacheEntity identifies the kind of PHD entity a cache entry holds
+type CacheEntity string
+
+const (
+	CacheEntityChain            CacheEntity = "chain"
+	CacheEntityGigastakeApp     CacheEntity = "gigastake_app"
+	CacheEntityPortalApp        CacheEntity = "portal_app"
+	CacheEntityMiddleware       CacheEntity = "middleware"
+	CacheEntityAccount          CacheEntity = "account"
+	CacheEntityUser             CacheEntity = "user"
+	CacheEntityPlan             CacheEntity = "plan"
+	CacheEntityBlockedContracts CacheEntity = "blocked_contract"
+
+	defaultCacheTTL        = time.Minute
+	defaultCacheMaxEntries = 10_000
+)
+
+type (
+	// CacheConfig configures a CachedReader
+	CacheConfig struct {
+		// DefaultTTL applies to every entity kind without an entry in TTLs. Defaults to 1 minute.
+		DefaultTTL time.Duration
+		// TTLs overrides the TTL per entity kind. A negative TTL disables caching for that kind.
+		TTLs map[CacheEntity]time.Duration
+		// MaxEntries is the maximum number of cached responses before the least recently used is evicted. Defaults to 10,000.
+		MaxEntries int
+	}
+
+	// CacheStats contains the hit, miss and eviction counters of a CachedReader
+	CacheStats struct {
+		Hits, Misses, Evictions uint64
+		Entries                 int
+	}
+
+	// CachedReader is a read-through in-memory cache in front of any IDBReader.
+	// Cached values are shared between callers and must be treated as read-only.
+	CachedReader struct {
+		reader IDBReader
+		config CacheConfig
+
+		mu      sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+		stats   CacheStats
+
+		// generations counts the invalidations of each kind and purges every Purge, so a read that was
+		// in flight during either doesn't store its outdated value
+		generations map[CacheEntity]uint64
+		purges      uint64
+	}
+
+	// CachedClient is a CachedReader that also forwards writes to an IDBWriter and
+	// invalidates the cache entries affected by every successful write
+	CachedClient struct {
+		*CachedReader
+		writer IDBWriter
+	}
+
+	cacheEntry struct {
+		key        string
+		kind       CacheEntity
+		collection bool
+		value      any
+		expiresAt  time.Time
+	}
+)
+
+var (
+	_ IDBClient = &CachedClient{}
+	_ IDBLister = &CachedReader{}
+)
+
+// NewCachedReader returns a CachedReader wrapping the given reader
+func NewCachedReader(reader IDBReader, config CacheConfig) *CachedReader {
+	if config.DefaultTTL == 0 {
+		config.DefaultTTL = defaultCacheTTL
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultCacheMaxEntries
+	}
+
+	return &CachedReader{
+		reader:      reader,
+		config:      config,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+		generations: make(map[CacheEntity]uint64),
+	}
+}
+
+// NewCachedClient returns a CachedClient wrapping the given client
+func NewCachedClient(client IDBClient, config CacheConfig) *CachedClient {
+	return &CachedClient{CachedReader: NewCachedReader(client, config), writer: client}
+}
+
+// Stats returns the current cache counters
+func (c *CachedReader) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// Purge removes every entry from the cache
+func (c *CachedReader) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.purges++
+}
+
+func (c *CachedReader) ttl(kind CacheEntity) time.Duration {
+	if ttl, ok := c.config.TTLs[kind]; ok {
+		return ttl
+	}
+	return c.config.DefaultTTL
+}
+
+func (c *CachedReader) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.stats.Misses++
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	c.stats.Hits++
+	return entry.value, true
+}
+
+// generation returns the current generation of a kind. It changes whenever entries of the kind are invalidated.
+func (c *CachedReader) generation(kind CacheEntity) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[kind] + c.purges
+}
+
+// set stores the value unless entries of its kind were invalidated since the given generation was read
+func (c *CachedReader) set(kind CacheEntity, key string, collection bool, value any, generation uint64) {
+	ttl := c.ttl(kind)
+	if ttl < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[kind]+c.purges != generation {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:        key,
+		kind:       kind,
+		collection: collection,
+		value:      value,
+		expiresAt:  time.Now().Add(ttl),
+	})
+
+	for c.lru.Len() > c.config.MaxEntries {
+		c.removeElement(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *CachedReader) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// invalidate removes the entry for a single entity as well as every collection entry of the same kind
+func (c *CachedReader) invalidate(kind CacheEntity, ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[kind]++
+	for _, id := range ids {
+		if elem, ok := c.entries[cacheKey(kind, id)]; ok {
+			c.removeElement(elem)
+		}
+	}
+	c.removeMatching(func(entry *cacheEntry) bool {
+		return entry.kind == kind && entry.collection
+	})
+}
+
+// invalidateKinds removes every entry of the given kinds
+func (c *CachedReader) invalidateKinds(kinds ...CacheEntity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, kind := range kinds {
+		c.generations[kind]++
+	}
+	c.removeMatching(func(entry *cacheEntry) bool {
+		for _, kind := range kinds {
+			if entry.kind == kind {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (c *CachedReader) removeMatching(match func(entry *cacheEntry) bool) {
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		if match(elem.Value.(*cacheEntry)) {
+			c.removeElement(elem)
+		}
+		elem = next
+	}
+}
+
+func cacheKey(kind CacheEntity, parts ...any) string {
+	key := string(kind)
+	for _, part := range parts {
+		key = fmt.Sprintf("%s:%v", key, part)
+	}
+	return key
+}
+
+// optionsCacheKey encodes an options struct for use in a cache key, dereferencing any pointer fields
+func optionsCacheKey[T any](options []T) string {
+	optionsJSON, _ := json.Marshal(options)
+	return string(optionsJSON)
+}
+
+// readThrough returns the cached value for the key or fetches and caches it.
+// Single entity lookups are keyed by ID so that writes can invalidate them individually.
+func readThrough[T any](c *CachedReader, kind CacheEntity, key string, collection bool, fetch func() (T, error)) (T, error) {
+	if value, ok := c.get(key); ok {
+		return value.(T), nil
+	}
+
+	generation := c.generation(kind)
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.set(kind, key, collection, value, generation)
+
+	return value, nil
+}
+
+/* ------------ IDBReader Methods ------------ */
+
+// GetChainByID returns a single Chain by its relay chain ID - GET `/v2/chain/{id}`
+func (c *CachedReader) GetChainByID(ctx context.Context, chainID types.RelayChainID) (*types.Chain, error) {
+	return readThrough(c, CacheEntityChain, cacheKey(CacheEntityChain, chainID), false, func() (*types.Chain, error) {
+		return c.reader.GetChainByID(ctx, chainID)
+	})
+}
+
+// GetGigastakeAppByID returns a single GigastakeApp by its GigastakeAppID - GET `/v2/gigastake/{id}`
+func (c *CachedReader) GetGigastakeAppByID(ctx context.Context, gigastakeAppID types.GigastakeAppID) (*types.GigastakeApp, error) {
+	return readThrough(c, CacheEntityGigastakeApp, cacheKey(CacheEntityGigastakeApp, gigastakeAppID), false, func() (*types.GigastakeApp, error) {
+		return c.reader.GetGigastakeAppByID(ctx, gigastakeAppID)
+	})
+}
+
+// GetAllChains returns all chains - GET `/v2/chain`
+func (c *CachedReader) GetAllChains(ctx context.Context, options ...ChainOptions) ([]*types.Chain, error) {
+	return readThrough(c, CacheEntityChain, cacheKey(CacheEntityChain, "all", optionsCacheKey(options)), true, func() ([]*types.Chain, error) {
+		return c.reader.GetAllChains(ctx, options...)
+	})
+}
+
+// ListChains returns an Iterator over all chains, fetched a page at a time. Pages are not cached.
+func (c *CachedReader) ListChains(ctx context.Context, options ChainOptions) *Iterator[types.Chain] {
+	return listChains(ctx, c.reader, options)
+}
+
+// GetAllGigastakeApps returns all GigastakeApps - GET `/v2/gigastake`
+func (c *CachedReader) GetAllGigastakeApps(ctx context.Context, options ...GigastakeAppOptions) ([]*types.GigastakeApp, error) {
+	return readThrough(c, CacheEntityGigastakeApp, cacheKey(CacheEntityGigastakeApp, "all", optionsCacheKey(options)), true, func() ([]*types.GigastakeApp, error) {
+		return c.reader.GetAllGigastakeApps(ctx, options...)
+	})
+}
+
+// ListGigastakeApps returns an Iterator over all GigastakeApps, fetched a page at a time. Pages are not cached.
+func (c *CachedReader) ListGigastakeApps(ctx context.Context, options GigastakeAppOptions) *Iterator[types.GigastakeApp] {
+	return listGigastakeApps(ctx, c.reader, options)
+}
+
+// GetAllGigastakeAppsByChain returns all GigastakeApps for a single chain ID - GET `/v2/chain/{id}/gigastake`
+func (c *CachedReader) GetAllGigastakeAppsByChain(ctx context.Context, chainID types.RelayChainID) ([]*types.GigastakeApp, error) {
+	return readThrough(c, CacheEntityGigastakeApp, cacheKey(CacheEntityGigastakeApp, "chain", chainID), true, func() ([]*types.GigastakeApp, error) {
+		return c.reader.GetAllGigastakeAppsByChain(ctx, chainID)
+	})
+}
+
+// GetPortalAppByID returns a single Portal App by its ID - GET `/v2/portal_app/{id}`
+func (c *CachedReader) GetPortalAppByID(ctx context.Context, portalAppID types.PortalAppID) (*types.PortalApp, error) {
+	return readThrough(c, CacheEntityPortalApp, cacheKey(CacheEntityPortalApp, portalAppID), false, func() (*types.PortalApp, error) {
+		return c.reader.GetPortalAppByID(ctx, portalAppID)
+	})
+}
+
+// GetAllPortalApps returns all Portal Apps - GET `/v2/portal_app`
+func (c *CachedReader) GetAllPortalApps(ctx context.Context, options ...PortalAppOptions) ([]*types.PortalApp, error) {
+	return readThrough(c, CacheEntityPortalApp, cacheKey(CacheEntityPortalApp, "all", optionsCacheKey(options)), true, func() ([]*types.PortalApp, error) {
+		return c.reader.GetAllPortalApps(ctx, options...)
+	})
+}
+
+// ListPortalApps returns an Iterator over all Portal Apps, fetched a page at a time. Pages are not cached.
+func (c *CachedReader) ListPortalApps(ctx context.Context, options PortalAppOptions) *Iterator[types.PortalApp] {
+	return listPortalApps(ctx, c.reader, options)
+}
+
+// GetPortalAppsByUser fetches all portal applications - GET `/v2/user/{userID}/portal_app`
+func (c *CachedReader) GetPortalAppsByUser(ctx context.Context, userID types.UserID, options ...PortalAppOptions) ([]*types.PortalApp, error) {
+	return readThrough(c, CacheEntityPortalApp, cacheKey(CacheEntityPortalApp, "user", userID, optionsCacheKey(options)), true, func() ([]*types.PortalApp, error) {
+		return c.reader.GetPortalAppsByUser(ctx, userID, options...)
+	})
+}
+
+// GetPortalAppsForMiddleware returns all Portal Apps - GET `/v2/middleware/portal_app`
+func (c *CachedReader) GetPortalAppsForMiddleware(ctx context.Context) ([]*types.PortalAppLite, error) {
+	return readThrough(c, CacheEntityMiddleware, cacheKey(CacheEntityMiddleware, "all"), true, func() ([]*types.PortalAppLite, error) {
+		return c.reader.GetPortalAppsForMiddleware(ctx)
+	})
+}
+
+// GetAllAccounts returns all Accounts - GET `/v2/account`
+func (c *CachedReader) GetAllAccounts(ctx context.Context, options ...AccountOptions) ([]*types.Account, error) {
+	return readThrough(c, CacheEntityAccount, cacheKey(CacheEntityAccount, "all", optionsCacheKey(options)), true, func() ([]*types.Account, error) {
+		return c.reader.GetAllAccounts(ctx, options...)
+	})
+}
+
+// ListAccounts returns an Iterator over all Accounts, fetched a page at a time. Pages are not cached.
+func (c *CachedReader) ListAccounts(ctx context.Context, options AccountOptions) *Iterator[types.Account] {
+	return listAccounts(ctx, c.reader, options)
+}
+
+// GetUserAccounts returns all Accounts for a given user ID - GET `/v2/user/{userID}/account`
+func (c *CachedReader) GetUserAccounts(ctx context.Context, userID types.UserID, options ...AccountOptions) ([]*types.Account, error) {
+	return readThrough(c, CacheEntityAccount, cacheKey(CacheEntityAccount, "user", userID, optionsCacheKey(options)), true, func() ([]*types.Account, error) {
+		return c.reader.GetUserAccounts(ctx, userID, options...)
+	})
+}
+
+// GetUserAccount returns a single user Account by its account ID and user ID - GET `/v2/user/{userID}/account/{id}`
+func (c *CachedReader) GetUserAccount(ctx context.Context, accountID types.AccountID, userID types.UserID, options ...AccountOptions) (*types.Account, error) {
+	return readThrough(c, CacheEntityAccount, cacheKey(CacheEntityAccount, accountID, userID, optionsCacheKey(options)), true, func() (*types.Account, error) {
+		return c.reader.GetUserAccount(ctx, accountID, userID, options...)
+	})
+}
+
+// GetPortalUser returns the Portal User for a given user ID, either provider ID or portal ID - GET `/v2/user/{userID}?full_details=true`
+func (c *CachedReader) GetPortalUser(ctx context.Context, userID string) (*types.User, error) {
+	return readThrough(c, CacheEntityUser, cacheKey(CacheEntityUser, "full", userID), true, func() (*types.User, error) {
+		return c.reader.GetPortalUser(ctx, userID)
+	})
+}
+
+// GetPortalUserID returns the Portal User for a given user ID, either provider ID or portal ID - GET `/v2/user/{userID}`
+func (c *CachedReader) GetPortalUserID(ctx context.Context, userID string) (types.UserID, error) {
+	return readThrough(c, CacheEntityUser, cacheKey(CacheEntityUser, "id", userID), true, func() (types.UserID, error) {
+		return c.reader.GetPortalUserID(ctx, userID)
+	})
+}
+
+// GetAllPlans returns all plans - GET `/v2/plan`
+func (c *CachedReader) GetAllPlans(ctx context.Context) ([]types.Plan, error) {
+	return readThrough(c, CacheEntityPlan, cacheKey(CacheEntityPlan, "all"), true, func() ([]types.Plan, error) {
+		return c.reader.GetAllPlans(ctx)
+	})
+}
+
+// GetBlockedContracts returns all blocked contracts - GET `/v2/blocked_contract`
+func (c *CachedReader) GetBlockedContracts(ctx context.Context) (types.GlobalBlockedContracts, error) {
+	return readThrough(c, CacheEntityBlockedContracts, cacheKey(CacheEntityBlockedContracts, "all"), true, func() (types.GlobalBlockedContracts, error) {
+		return c.reader.GetBlockedContracts(ctx)
+	})
+}
+
+/* ------------ IDBWriter Methods ------------ */
+
+// invalidateOnSuccess runs the write and, if it succeeded, the given invalidation
+func invalidateOnSuccess[T any](write func() (T, error), invalidate func()) (T, error) {
+	result, err := write()
+	if err == nil {
+		invalidate()
+	}
+	return result, err
+}
+
+// CreateChainAndGigastakeApps creates a new blockchain and its Gigastake apps in the DB - POST `/v2/chain`
+func (c *CachedClient) CreateChainAndGigastakeApps(ctx context.Context, newChainInput types.NewChainInput) (*types.NewChainInput, error) {
+	return invalidateOnSuccess(func() (*types.NewChainInput, error) {
+		return c.writer.CreateChainAndGigastakeApps(ctx, newChainInput)
+	}, func() { c.invalidateKinds(CacheEntityChain, CacheEntityGigastakeApp) })
+}
+
+// CreateGigastakeApp creates a new Gigastake app in the DB - POST `/v2/chain/gigastake`
+func (c *CachedClient) CreateGigastakeApp(ctx context.Context, gigastakeAppInput types.GigastakeApp) (*types.GigastakeApp, error) {
+	return invalidateOnSuccess(func() (*types.GigastakeApp, error) {
+		return c.writer.CreateGigastakeApp(ctx, gigastakeAppInput)
+	}, func() { c.invalidateKinds(CacheEntityChain, CacheEntityGigastakeApp) })
+}
+
+// UpdateChain updates an existing blockchain in the DB - PUT `/v2/chain/{id}`
+func (c *CachedClient) UpdateChain(ctx context.Context, chainUpdate types.UpdateChain) (*types.Chain, error) {
+	return invalidateOnSuccess(func() (*types.Chain, error) {
+		return c.writer.UpdateChain(ctx, chainUpdate)
+	}, func() { c.invalidate(CacheEntityChain, string(chainUpdate.ID)) })
+}
+
+// UpdateGigastakeApp updates a Gigastake app in the DB - PUT `/v2/chain/gigastake/{id}`
+func (c *CachedClient) UpdateGigastakeApp(ctx context.Context, id types.GigastakeAppID, updateGigastakeApp types.UpdateGigastakeApp) (*types.UpdateGigastakeApp, error) {
+	return invalidateOnSuccess(func() (*types.UpdateGigastakeApp, error) {
+		return c.writer.UpdateGigastakeApp(ctx, id, updateGigastakeApp)
+	}, func() { c.invalidateKinds(CacheEntityChain, CacheEntityGigastakeApp) })
+}
+
+// ActivateChain activates or deactivates a blockchain by ID in the DB - PUT `/v2/chain/{id}/activate`
+func (c *CachedClient) ActivateChain(ctx context.Context, chainID types.RelayChainID, active bool) (bool, error) {
+	return invalidateOnSuccess(func() (bool, error) {
+		return c.writer.ActivateChain(ctx, chainID, active)
+	}, func() { c.invalidate(CacheEntityChain, string(chainID)) })
+}
+
+// CreatePortalApp creates a new Portal App - POST `/v2/portal_app`
+func (c *CachedClient) CreatePortalApp(ctx context.Context, portalAppInput types.PortalApp) (*types.PortalApp, error) {
+	return invalidateOnSuccess(func() (*types.PortalApp, error) {
+		return c.writer.CreatePortalApp(ctx, portalAppInput)
+	}, func() {
+		c.invalidate(CacheEntityPortalApp)
+		c.invalidateKinds(CacheEntityMiddleware, CacheEntityAccount, CacheEntityUser)
+	})
+}
+
+// UpdatePortalApp updates an existing Portal App - PUT `/v2/portal_app/{id}`
+func (c *CachedClient) UpdatePortalApp(ctx context.Context, portalAppUpdate types.UpdatePortalApp) (*types.UpdatePortalApp, error) {
+	return invalidateOnSuccess(func() (*types.UpdatePortalApp, error) {
+		return c.writer.UpdatePortalApp(ctx, portalAppUpdate)
+	}, func() {
+		c.invalidate(CacheEntityPortalApp, string(portalAppUpdate.AppID))
+		c.invalidateKinds(CacheEntityMiddleware, CacheEntityAccount)
+	})
+}
+
+// DeletePortalApp deletes a Portal App - DELETE `/v2/portal_app/{id}`
+func (c *CachedClient) DeletePortalApp(ctx context.Context, portalAppID types.PortalAppID) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.DeletePortalApp(ctx, portalAppID)
+	}, func() {
+		c.invalidate(CacheEntityPortalApp, string(portalAppID))
+		c.invalidateKinds(CacheEntityMiddleware, CacheEntityAccount, CacheEntityUser)
+	})
+}
+
+// UpdatePortalAppsFirstDateSurpassed updates the FirstDateSurpassed field of one or more Portal Apps - POST `/v2/portal_app/first_date_surpassed`
+func (c *CachedClient) UpdatePortalAppsFirstDateSurpassed(ctx context.Context, firstDateSurpassedUpdate types.UpdateFirstDateSurpassed) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.UpdatePortalAppsFirstDateSurpassed(ctx, firstDateSurpassedUpdate)
+	}, func() {
+		portalAppIDs := make([]string, len(firstDateSurpassedUpdate.PortalAppIDs))
+		for i, portalAppID := range firstDateSurpassedUpdate.PortalAppIDs {
+			portalAppIDs[i] = string(portalAppID)
+		}
+		c.invalidate(CacheEntityPortalApp, portalAppIDs...)
+		c.invalidateKinds(CacheEntityMiddleware, CacheEntityAccount)
+	})
+}
+
+// CreateAccount creates a new Account in the database for a single user - POST `/v2/user/{userID}/account`
+func (c *CachedClient) CreateAccount(ctx context.Context, userID types.UserID, account types.Account, timestamp time.Time) (*types.Account, error) {
+	return invalidateOnSuccess(func() (*types.Account, error) {
+		return c.writer.CreateAccount(ctx, userID, account, timestamp)
+	}, func() { c.invalidateKinds(CacheEntityAccount, CacheEntityUser) })
+}
+
+// UpdateAccount updates an existing account in the DB - PUT `/v2/account/{id}`
+func (c *CachedClient) UpdateAccount(ctx context.Context, account types.UpdateAccount) (*types.Account, error) {
+	return invalidateOnSuccess(func() (*types.Account, error) {
+		return c.writer.UpdateAccount(ctx, account)
+	}, func() { c.invalidateKinds(CacheEntityAccount, CacheEntityPortalApp, CacheEntityMiddleware) })
+}
+
+// CreateAccountIntegration creates a new integration for an account - POST `/v2/account/{id}/integration`
+func (c *CachedClient) CreateAccountIntegration(ctx context.Context, accountID types.AccountID, integration types.AccountIntegrations) (*types.AccountIntegrations, error) {
+	return invalidateOnSuccess(func() (*types.AccountIntegrations, error) {
+		return c.writer.CreateAccountIntegration(ctx, accountID, integration)
+	}, func() { c.invalidateKinds(CacheEntityAccount) })
+}
+
+// UpdateAccountIntegration updates an existing integration for an account - PUT `/v2/account/{id}/integration`
+func (c *CachedClient) UpdateAccountIntegration(ctx context.Context, accountID types.AccountID, integration types.AccountIntegrations) (*types.AccountIntegrations, error) {
+	return invalidateOnSuccess(func() (*types.AccountIntegrations, error) {
+		return c.writer.UpdateAccountIntegration(ctx, accountID, integration)
+	}, func() { c.invalidateKinds(CacheEntityAccount) })
+}
+
+// DeleteAccount deletes an account in the DB - DELETE `/v2/account/{id}`
+func (c *CachedClient) DeleteAccount(ctx context.Context, accountID types.AccountID) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.DeleteAccount(ctx, accountID)
+	}, func() {
+		c.invalidateKinds(CacheEntityAccount, CacheEntityPortalApp, CacheEntityMiddleware, CacheEntityUser)
+	})
+}
+
+// WriteAccountUser creates a single Account User - POST `/v2/account/user`
+func (c *CachedClient) WriteAccountUser(ctx context.Context, createUser types.CreateAccountUserAccess, time time.Time) (map[string]types.UserID, error) {
+	return invalidateOnSuccess(func() (map[string]types.UserID, error) {
+		return c.writer.WriteAccountUser(ctx, createUser, time)
+	}, c.invalidateAccountUsers)
+}
+
+// SetAccountUserRole updates the role for a single Account User - PUT `/v2/account/user/update_role`
+func (c *CachedClient) SetAccountUserRole(ctx context.Context, updateUser types.UpdateAccountUserRole, time time.Time) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.SetAccountUserRole(ctx, updateUser, time)
+	}, c.invalidateAccountUsers)
+}
+
+// UpdateAcceptAccountUser accepts or declines an Account User Access - PUT `/v2/account/user/accept`
+func (c *CachedClient) UpdateAcceptAccountUser(ctx context.Context, acceptUser types.UpdateAcceptAccountUser, time time.Time) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.UpdateAcceptAccountUser(ctx, acceptUser, time)
+	}, c.invalidateAccountUsers)
+}
+
+// RemoveAccountUser removes an Account User's Role - PUT `/v2/account/user/remove`
+func (c *CachedClient) RemoveAccountUser(ctx context.Context, removeUser types.UpdateRemoveAccountUser) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.RemoveAccountUser(ctx, removeUser)
+	}, c.invalidateAccountUsers)
+}
+
+// invalidateAccountUsers removes every entry that embeds account user access or permissions
+func (c *CachedClient) invalidateAccountUsers() {
+	c.invalidateKinds(CacheEntityAccount, CacheEntityPortalApp, CacheEntityUser)
+}
+
+// CreateUser creates a new User in the database - POST `/v2/user`
+func (c *CachedClient) CreateUser(ctx context.Context, user types.CreateUser) (*types.CreateUserResponse, error) {
+	return invalidateOnSuccess(func() (*types.CreateUserResponse, error) {
+		return c.writer.CreateUser(ctx, user)
+	}, func() { c.invalidateKinds(CacheEntityUser, CacheEntityAccount) })
+}
+
+// UpdateUser updates an existing User in the database - PUT `/v2/user`
+func (c *CachedClient) UpdateUser(ctx context.Context, user types.UpdateUser) (*types.User, error) {
+	return invalidateOnSuccess(func() (*types.User, error) {
+		return c.writer.UpdateUser(ctx, user)
+	}, c.invalidateAccountUsers)
+}
+
+// DeleteUser deletes a User - DELETE `/v2/user/{userID}`
+func (c *CachedClient) DeleteUser(ctx context.Context, userID types.UserID) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.DeleteUser(ctx, userID)
+	}, c.invalidateAccountUsers)
+}
+
+// WriteBlockedContract adds a new blocked address to the global blocked contracts - POST `/v2/blocked_contract`
+func (c *CachedClient) WriteBlockedContract(ctx context.Context, blockedContract types.BlockedContract) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.WriteBlockedContract(ctx, blockedContract)
+	}, func() { c.invalidateKinds(CacheEntityBlockedContracts) })
+}
+
+// UpdateBlockedContractActive updates the active status of a blocked contract - PUT `/v2/blocked_contract/{address}/active`
+func (c *CachedClient) UpdateBlockedContractActive(ctx context.Context, address types.BlockedAddress, isActive bool) (map[string]bool, error) {
+	return invalidateOnSuccess(func() (map[string]bool, error) {
+		return c.writer.UpdateBlockedContractActive(ctx, address, isActive)
+	}, func() { c.invalidateKinds(CacheEntityBlockedContracts) })
+}
+
+// RemoveBlockedContract deletes a blocked address from the global blocked contracts - DELETE `/v2/blocked_contract/{address}`
+func (c *CachedClient) RemoveBlockedContract(ctx context.Context, address types.BlockedAddress) (map[string]string, error) {
+	return invalidateOnSuccess(func() (map[string]string, error) {
+		return c.writer.RemoveBlockedContract(ctx, address)
+	}, func() { c.invalidateKinds(CacheEntityBlockedContracts) })
+}
@@ -0,0 +1,271 @@
+package dbclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_CachedReader(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          CacheConfig
+		calls           []types.PortalAppID
+		sleep           time.Duration
+		expectedFetches int
+		expectedStats   CacheStats
+		fetchErr        error
+		expectedErr     error
+		expectedApp     *types.PortalApp
+	}{
+		{
+			name:            "Should serve repeated lookups from the cache",
+			config:          CacheConfig{},
+			calls:           []types.PortalAppID{"test_app_1", "test_app_1", "test_app_1"},
+			expectedFetches: 1,
+			expectedStats:   CacheStats{Hits: 2, Misses: 1, Entries: 1},
+			expectedApp:     &types.PortalApp{ID: "test_app_1"},
+		},
+		{
+			name:            "Should evict the least recently used entry when full",
+			config:          CacheConfig{MaxEntries: 2},
+			calls:           []types.PortalAppID{"test_app_1", "test_app_2", "test_app_1", "test_app_3", "test_app_2"},
+			expectedFetches: 4,
+			expectedStats:   CacheStats{Hits: 1, Misses: 4, Evictions: 2, Entries: 2},
+			expectedApp:     &types.PortalApp{ID: "test_app_2"},
+		},
+		{
+			name:            "Should refetch entries once their TTL has expired",
+			config:          CacheConfig{TTLs: map[CacheEntity]time.Duration{CacheEntityPortalApp: 10 * time.Millisecond}},
+			calls:           []types.PortalAppID{"test_app_1", "test_app_1"},
+			sleep:           20 * time.Millisecond,
+			expectedFetches: 2,
+			expectedStats:   CacheStats{Misses: 2, Entries: 1},
+			expectedApp:     &types.PortalApp{ID: "test_app_1"},
+		},
+		{
+			name:            "Should not cache entity kinds with a negative TTL",
+			config:          CacheConfig{TTLs: map[CacheEntity]time.Duration{CacheEntityPortalApp: -1}},
+			calls:           []types.PortalAppID{"test_app_1", "test_app_1"},
+			expectedFetches: 2,
+			expectedStats:   CacheStats{Misses: 2},
+			expectedApp:     &types.PortalApp{ID: "test_app_1"},
+		},
+		{
+			name:            "Should not cache errors",
+			config:          CacheConfig{},
+			calls:           []types.PortalAppID{"test_app_1", "test_app_1"},
+			fetchErr:        ErrNotFound,
+			expectedFetches: 2,
+			expectedStats:   CacheStats{Misses: 2},
+			expectedErr:     ErrNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := assert.New(t)
+			ctx := context.Background()
+
+			reader := NewMockIDBReader(t)
+			reader.On("GetPortalAppByID", mock.Anything, mock.AnythingOfType("types.PortalAppID")).
+				Return(func(_ context.Context, portalAppID types.PortalAppID) (*types.PortalApp, error) {
+					if test.fetchErr != nil {
+						return nil, test.fetchErr
+					}
+					return &types.PortalApp{ID: portalAppID}, nil
+				})
+
+			cache := NewCachedReader(reader, test.config)
+
+			var (
+				app *types.PortalApp
+				err error
+			)
+			for _, portalAppID := range test.calls {
+				time.Sleep(test.sleep)
+				app, err = cache.GetPortalAppByID(ctx, portalAppID)
+			}
+
+			c.ErrorIs(err, test.expectedErr)
+			c.Equal(test.expectedApp, app)
+			reader.AssertNumberOfCalls(t, "GetPortalAppByID", test.expectedFetches)
+			c.Equal(test.expectedStats, cache.Stats())
+		})
+	}
+}
+
+func Test_CachedClient_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	portalApp := &types.PortalApp{ID: "test_app_1"}
+
+	tests := []struct {
+		name            string
+		write           func(client *CachedClient) error
+		writeErr        error
+		expectedFetches int
+	}{
+		{
+			name: "Should invalidate the updated portal app",
+			write: func(client *CachedClient) error {
+				_, err := client.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1"})
+				return err
+			},
+			expectedFetches: 2,
+		},
+		{
+			name: "Should invalidate the deleted portal app",
+			write: func(client *CachedClient) error {
+				_, err := client.DeletePortalApp(ctx, "test_app_1")
+				return err
+			},
+			expectedFetches: 2,
+		},
+		{
+			name: "Should keep unrelated portal apps cached",
+			write: func(client *CachedClient) error {
+				_, err := client.DeletePortalApp(ctx, "test_app_2")
+				return err
+			},
+			expectedFetches: 1,
+		},
+		{
+			name: "Should keep the cache when the write fails",
+			write: func(client *CachedClient) error {
+				_, err := client.DeletePortalApp(ctx, "test_app_1")
+				return err
+			},
+			writeErr:        ErrServer,
+			expectedFetches: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := assert.New(t)
+
+			client := NewMockIDBClient(t)
+			client.On("GetPortalAppByID", mock.Anything, types.PortalAppID("test_app_1")).Return(portalApp, nil)
+			client.On("UpdatePortalApp", mock.Anything, mock.Anything).Return(nil, test.writeErr).Maybe()
+			client.On("DeletePortalApp", mock.Anything, mock.Anything).Return(nil, test.writeErr).Maybe()
+
+			cache := NewCachedClient(client, CacheConfig{})
+
+			_, err := cache.GetPortalAppByID(ctx, "test_app_1")
+			c.NoError(err)
+
+			c.ErrorIs(test.write(cache), test.writeErr)
+
+			app, err := cache.GetPortalAppByID(ctx, "test_app_1")
+			c.NoError(err)
+			c.Equal(portalApp, app)
+			client.AssertNumberOfCalls(t, "GetPortalAppByID", test.expectedFetches)
+		})
+	}
+
+	t.Run("Should invalidate the blocked contracts after removing one", func(t *testing.T) {
+		c := assert.New(t)
+
+		client := NewMockIDBClient(t)
+		client.On("GetBlockedContracts", mock.Anything).Return(types.GlobalBlockedContracts{}, nil)
+		client.On("RemoveBlockedContract", mock.Anything, types.BlockedAddress("0xbad")).Return(map[string]string{}, nil)
+
+		cache := NewCachedClient(client, CacheConfig{})
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.GetBlockedContracts(ctx)
+			c.NoError(err)
+		}
+		_, err := cache.RemoveBlockedContract(ctx, "0xbad")
+		c.NoError(err)
+		_, err = cache.GetBlockedContracts(ctx)
+		c.NoError(err)
+
+		client.AssertNumberOfCalls(t, "GetBlockedContracts", 2)
+	})
+
+	t.Run("Should invalidate chain lookups after updating a chain", func(t *testing.T) {
+		c := assert.New(t)
+
+		client := NewMockIDBClient(t)
+		client.On("GetAllChains", mock.Anything).Return([]*types.Chain{}, nil)
+		client.On("UpdateChain", mock.Anything, mock.Anything).Return(&types.Chain{}, nil)
+
+		cache := NewCachedClient(client, CacheConfig{})
+
+		_, err := cache.GetAllChains(ctx)
+		c.NoError(err)
+		_, err = cache.UpdateChain(ctx, types.UpdateChain{ID: "0001"})
+		c.NoError(err)
+		_, err = cache.GetAllChains(ctx)
+		c.NoError(err)
+
+		client.AssertNumberOfCalls(t, "GetAllChains", 2)
+	})
+}
+
+func Test_CachedClient_InvalidationDuringRead(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		write           func(client *CachedClient) error
+		expectedFetches int
+	}{
+		{
+			name: "Should not cache a read that was in flight during an update",
+			write: func(client *CachedClient) error {
+				_, err := client.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1"})
+				return err
+			},
+			expectedFetches: 2,
+		},
+		{
+			name: "Should not cache a read that was in flight during a purge",
+			write: func(client *CachedClient) error {
+				client.Purge()
+				return nil
+			},
+			expectedFetches: 2,
+		},
+		{
+			name: "Should cache a read that was in flight during an unrelated write",
+			write: func(client *CachedClient) error {
+				_, err := client.RemoveBlockedContract(ctx, "0xbad")
+				return err
+			},
+			expectedFetches: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := assert.New(t)
+
+			client := NewMockIDBClient(t)
+			cache := NewCachedClient(client, CacheConfig{})
+
+			var written bool
+			client.On("GetPortalAppByID", mock.Anything, types.PortalAppID("test_app_1")).Return(&types.PortalApp{ID: "test_app_1"}, nil).Run(func(mock.Arguments) {
+				// The write completes while the first read is waiting for its response
+				if !written {
+					written = true
+					c.NoError(test.write(cache))
+				}
+			})
+			client.On("UpdatePortalApp", mock.Anything, mock.Anything).Return(&types.UpdatePortalApp{}, nil).Maybe()
+			client.On("RemoveBlockedContract", mock.Anything, mock.Anything).Return(map[string]string{}, nil).Maybe()
+
+			for i := 0; i < 2; i++ {
+				_, err := cache.GetPortalAppByID(ctx, "test_app_1")
+				c.NoError(err)
+			}
+
+			client.AssertNumberOfCalls(t, "GetPortalAppByID", test.expectedFetches)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package dbclient
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of a single endpoint host
+type CircuitState string
+
+const (
+	// CircuitClosed lets every request through
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails every request with a CircuitOpenError without sending it
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a limited number of trial requests through to decide whether to close or reopen
+	CircuitHalfOpen CircuitState = "half_open"
+
+	defaultCircuitConsecutiveFailures = 5
+	defaultCircuitMinRequests         = 20
+	defaultCircuitWindow              = time.Minute
+	defaultCircuitOpenTimeout         = 30 * time.Second
+	defaultCircuitHalfOpenRequests    = 1
+)
+
+type (
+	// CircuitBreakerConfig configures the circuit breakers kept per endpoint host
+	CircuitBreakerConfig struct {
+		// ConsecutiveFailures opens the circuit after this many failed requests in a row. Defaults to 5.
+		ConsecutiveFailures int
+		// FailureRate opens the circuit once this fraction of the requests in the current window failed, eg. 0.5.
+		// Zero disables the failure rate check.
+		FailureRate float64
+		// MinRequests is the number of requests a window needs before FailureRate applies. Defaults to 20.
+		MinRequests int
+		// Window is the period the failure rate is computed over. Defaults to 1 minute.
+		Window time.Duration
+		// OpenTimeout is how long the circuit stays open before letting trial requests through. Defaults to 30s.
+		OpenTimeout time.Duration
+		// HalfOpenRequests is the number of trial requests that must all succeed to close the circuit. Defaults to 1.
+		HalfOpenRequests int
+		// OnStateChange is called whenever the circuit of a host changes state, eg. to alert or fall back to cached data
+		OnStateChange func(host string, from, to CircuitState)
+	}
+
+	// circuitBreakerTransport is a RoundTripper failing fast with a CircuitOpenError while the circuit of the
+	// request's host is open. Transport errors and 5xx responses count as failures.
+	circuitBreakerTransport struct {
+		underlying http.RoundTripper
+		config     CircuitBreakerConfig
+		now        func() time.Time
+
+		mu       sync.Mutex
+		circuits map[string]*circuit
+	}
+
+	circuit struct {
+		state               CircuitState
+		consecutiveFailures int
+		windowStart         time.Time
+		requests, failures  int
+		openedAt            time.Time
+		halfOpenInFlight    int
+		halfOpenSuccesses   int
+	}
+)
+
+func newCircuitBreakerTransport(config CircuitBreakerConfig, underlying http.RoundTripper) *circuitBreakerTransport {
+	if config.ConsecutiveFailures <= 0 {
+		config.ConsecutiveFailures = defaultCircuitConsecutiveFailures
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = defaultCircuitMinRequests
+	}
+	if config.Window <= 0 {
+		config.Window = defaultCircuitWindow
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultCircuitOpenTimeout
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = defaultCircuitHalfOpenRequests
+	}
+
+	return &circuitBreakerTransport{
+		underlying: underlying,
+		config:     config,
+		now:        time.Now,
+		circuits:   make(map[string]*circuit),
+	}
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if err := t.allow(host); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.underlying.RoundTrip(req)
+
+	// A request cancelled by the caller says nothing about the health of the host
+	if req.Context().Err() != nil {
+		t.release(host)
+		return resp, err
+	}
+
+	t.record(host, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	return resp, err
+}
+
+// allow returns a CircuitOpenError if the request may not be sent, moving an open circuit to half-open once
+// its timeout has passed
+func (t *circuitBreakerTransport) allow(host string) error {
+	t.mu.Lock()
+	c := t.circuit(host)
+	now := t.now()
+
+	var from CircuitState
+	if c.state == CircuitOpen && !now.Before(c.openedAt.Add(t.config.OpenTimeout)) {
+		from = t.transition(c, CircuitHalfOpen, now)
+	}
+
+	var err error
+	switch {
+	case c.state == CircuitOpen:
+		err = &CircuitOpenError{Host: host, RetryAt: c.openedAt.Add(t.config.OpenTimeout)}
+	case c.state == CircuitHalfOpen && c.halfOpenInFlight+c.halfOpenSuccesses >= t.config.HalfOpenRequests:
+		err = &CircuitOpenError{Host: host, RetryAt: now}
+	case c.state == CircuitHalfOpen:
+		c.halfOpenInFlight++
+	}
+	t.mu.Unlock()
+
+	t.notify(host, from, CircuitHalfOpen)
+	return err
+}
+
+// record counts the outcome of a sent request and opens or closes the circuit accordingly
+func (t *circuitBreakerTransport) record(host string, failed bool) {
+	t.mu.Lock()
+	c := t.circuit(host)
+	now := t.now()
+
+	if now.Sub(c.windowStart) >= t.config.Window {
+		c.windowStart, c.requests, c.failures = now, 0, 0
+	}
+	c.requests++
+	if failed {
+		c.failures++
+		c.consecutiveFailures++
+	} else {
+		c.consecutiveFailures = 0
+	}
+
+	var from, to CircuitState
+	switch c.state {
+	case CircuitHalfOpen:
+		c.halfOpenInFlight = max(c.halfOpenInFlight-1, 0)
+		if failed {
+			from, to = t.transition(c, CircuitOpen, now), CircuitOpen
+		} else if c.halfOpenSuccesses++; c.halfOpenSuccesses >= t.config.HalfOpenRequests {
+			from, to = t.transition(c, CircuitClosed, now), CircuitClosed
+		}
+	case CircuitClosed:
+		if failed && t.shouldOpen(c) {
+			from, to = t.transition(c, CircuitOpen, now), CircuitOpen
+		}
+	}
+	t.mu.Unlock()
+
+	t.notify(host, from, to)
+}
+
+// release frees the half-open slot of a request that was cancelled by the caller
+func (t *circuitBreakerTransport) release(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if c := t.circuit(host); c.state == CircuitHalfOpen && c.halfOpenInFlight > 0 {
+		c.halfOpenInFlight--
+	}
+}
+
+func (t *circuitBreakerTransport) shouldOpen(c *circuit) bool {
+	if c.consecutiveFailures >= t.config.ConsecutiveFailures {
+		return true
+	}
+	return t.config.FailureRate > 0 && c.requests >= t.config.MinRequests &&
+		float64(c.failures)/float64(c.requests) >= t.config.FailureRate
+}
+
+// transition moves the circuit to the given state and returns the previous one
+func (t *circuitBreakerTransport) transition(c *circuit, to CircuitState, now time.Time) CircuitState {
+	from := c.state
+	c.state = to
+	c.halfOpenInFlight, c.halfOpenSuccesses = 0, 0
+
+	switch to {
+	case CircuitOpen:
+		c.openedAt = now
+	case CircuitClosed:
+		c.consecutiveFailures = 0
+		c.windowStart, c.requests, c.failures = now, 0, 0
+	}
+
+	return from
+}
+
+// notify calls OnStateChange if a transition happened, outside of the lock so the callback may use the client
+func (t *circuitBreakerTransport) notify(host string, from, to CircuitState) {
+	if from != "" && t.config.OnStateChange != nil {
+		t.config.OnStateChange(host, from, to)
+	}
+}
+
+func (t *circuitBreakerTransport) circuit(host string) *circuit {
+	c, ok := t.circuits[host]
+	if !ok {
+		c = &circuit{state: CircuitClosed, windowStart: t.now()}
+		t.circuits[host] = c
+	}
+	return c
+}
@@ -0,0 +1,197 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type stateChange struct {
+	from, to CircuitState
+}
+
+func Test_CircuitBreaker(t *testing.T) {
+	errConnectionRefused := errors.New("connection refused")
+
+	tests := []struct {
+		name   string
+		config CircuitBreakerConfig
+		// steps are the outcomes of consecutive requests: a status code, 0 for a transport error,
+		// -1 to advance the clock past OpenTimeout without sending a request
+		steps           []int
+		expectedOpen    []bool
+		expectedChanges []stateChange
+	}{
+		{
+			name:            "Should open after consecutive failures",
+			config:          CircuitBreakerConfig{ConsecutiveFailures: 3},
+			steps:           []int{500, 0, 502, 200},
+			expectedOpen:    []bool{false, false, false, true},
+			expectedChanges: []stateChange{{CircuitClosed, CircuitOpen}},
+		},
+		{
+			name:         "Should reset the consecutive failures on success",
+			config:       CircuitBreakerConfig{ConsecutiveFailures: 2},
+			steps:        []int{500, 200, 500, 200},
+			expectedOpen: []bool{false, false, false, false},
+		},
+		{
+			name:            "Should open once the failure rate is reached",
+			config:          CircuitBreakerConfig{ConsecutiveFailures: 100, FailureRate: 0.5, MinRequests: 4},
+			steps:           []int{200, 500, 200, 500, 200},
+			expectedOpen:    []bool{false, false, false, false, true},
+			expectedChanges: []stateChange{{CircuitClosed, CircuitOpen}},
+		},
+		{
+			name:         "Should not count 4xx responses as failures",
+			config:       CircuitBreakerConfig{ConsecutiveFailures: 2},
+			steps:        []int{404, 409, 400},
+			expectedOpen: []bool{false, false, false},
+		},
+		{
+			name:            "Should close after a successful trial request",
+			config:          CircuitBreakerConfig{ConsecutiveFailures: 1},
+			steps:           []int{500, 200, -1, 200, 200},
+			expectedOpen:    []bool{false, true, false, false},
+			expectedChanges: []stateChange{{CircuitClosed, CircuitOpen}, {CircuitOpen, CircuitHalfOpen}, {CircuitHalfOpen, CircuitClosed}},
+		},
+		{
+			name:            "Should reopen after a failed trial request",
+			config:          CircuitBreakerConfig{ConsecutiveFailures: 1},
+			steps:           []int{500, -1, 503, 200},
+			expectedOpen:    []bool{false, false, true},
+			expectedChanges: []stateChange{{CircuitClosed, CircuitOpen}, {CircuitOpen, CircuitHalfOpen}, {CircuitHalfOpen, CircuitOpen}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+			var status int
+
+			var changes []stateChange
+			test.config.OnStateChange = func(host string, from, to CircuitState) {
+				assert.Equal(t, "phd.test", host)
+				changes = append(changes, stateChange{from, to})
+			}
+			breaker := newCircuitBreakerTransport(test.config, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if status == 0 {
+					return nil, errConnectionRefused
+				}
+				return &http.Response{StatusCode: status, Body: http.NoBody}, nil
+			}))
+			breaker.now = func() time.Time { return now }
+
+			open := make([]bool, 0)
+			for _, step := range test.steps {
+				if step == -1 {
+					now = now.Add(defaultCircuitOpenTimeout)
+					continue
+				}
+
+				status = step
+				req, _ := http.NewRequest(http.MethodGet, "http://phd.test/v2/chain", nil)
+				_, err := breaker.RoundTrip(req)
+				open = append(open, errors.Is(err, ErrCircuitOpen))
+			}
+
+			assert.Equal(t, test.expectedOpen, open)
+			assert.Equal(t, test.expectedChanges, changes)
+		})
+	}
+}
+
+func Test_CircuitBreaker_HalfOpenLimitsTrials(t *testing.T) {
+	release := make(chan struct{})
+	breaker := newCircuitBreakerTransport(CircuitBreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Millisecond}, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/slow" {
+			<-release
+		}
+		return &http.Response{StatusCode: http.StatusInternalServerError, Body: http.NoBody}, nil
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://phd.test/v2/chain", nil)
+	_, err := breaker.RoundTrip(req)
+	assert.NoError(t, err)
+	time.Sleep(2 * time.Millisecond)
+
+	trialDone := make(chan struct{})
+	go func() {
+		slowReq, _ := http.NewRequest(http.MethodGet, "http://phd.test/slow", nil)
+		_, _ = breaker.RoundTrip(slowReq)
+		close(trialDone)
+	}()
+
+	assert.Eventually(t, func() bool {
+		breaker.mu.Lock()
+		defer breaker.mu.Unlock()
+		return breaker.circuits["phd.test"].halfOpenInFlight == 1
+	}, time.Second, time.Millisecond)
+
+	_, err = breaker.RoundTrip(req)
+	assert.ErrorIs(t, err, ErrCircuitOpen, "only one trial request is let through")
+
+	close(release)
+	<-trialDone
+}
+
+func Test_CircuitBreaker_FailsFastWithoutRetrying(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	var changes atomic.Int32
+	client, err := NewDBClient(Config{
+		BaseURL:     server.URL,
+		APIKey:      "test_api_key_6789",
+		Timeout:     5 * time.Second,
+		RetryPolicy: &ExponentialBackoff{MaxRetries: 5, BaseDelay: time.Millisecond},
+		CircuitBreaker: &CircuitBreakerConfig{
+			ConsecutiveFailures: 2,
+			OnStateChange:       func(host string, from, to CircuitState) { changes.Add(1) },
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = client.GetChainByID(context.Background(), "0001")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), attempts.Load(), "retries stop as soon as the circuit opens")
+
+	_, err = client.GetChainByID(context.Background(), "0001")
+	var circuitErr *CircuitOpenError
+	assert.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, serverURL.Host, circuitErr.Host)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Equal(t, int32(1), changes.Load())
+}
+
+func Test_CircuitBreaker_IgnoresCancelledRequests(t *testing.T) {
+	breaker := newCircuitBreakerTransport(CircuitBreakerConfig{ConsecutiveFailures: 1}, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://phd.test/v2/chain", nil)
+		_, err := breaker.RoundTrip(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
@@ -7,8 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"net/url"
 	"time"
 
 	"github.com/pokt-foundation/portal-db/v2/types"
@@ -27,10 +28,57 @@ type (
 		BaseURL, APIKey string
 		Retries         int
 		Timeout         time.Duration
-	}
-	retryTransport struct {
-		underlying http.RoundTripper
-		retries    int
+		// RetryPolicy decides which failed requests are retried and how long to wait between attempts.
+		// Defaults to an ExponentialBackoff policy allowing up to Retries retries.
+		RetryPolicy RetryPolicy
+
+		// BaseURLs lists additional PHD instances to fail over between. If BaseURL is set it is used as the primary,
+		// otherwise the first entry is. Failed idempotent requests move on to the next endpoint straight away.
+		BaseURLs []string
+		// EndpointStrategy selects how requests are spread across the endpoints. Defaults to EndpointPrimarySecondary.
+		EndpointStrategy EndpointStrategy
+		// PinWritesToPrimary sends all write requests to the primary endpoint, without failover
+		PinWritesToPrimary bool
+		// MaxEndpointFailures is the number of consecutive failures after which an endpoint is ejected. Defaults to 3.
+		MaxEndpointFailures int
+		// EndpointProbeInterval is how long an ejected endpoint waits before being re-probed via `/healthz`. Defaults to 10s.
+		EndpointProbeInterval time.Duration
+		// Transport sends every attempt, eg. to route through a proxy or record requests. Defaults to http.DefaultTransport.
+		Transport http.RoundTripper
+		// CircuitBreaker keeps a circuit breaker per endpoint host. While a circuit is open requests to that host
+		// fail straight away with a CircuitOpenError and are not retried. Defaults to no circuit breaker.
+		CircuitBreaker *CircuitBreakerConfig
+		// ReadLimit throttles GET requests and WriteLimit all other requests, waiting until the caller's context
+		// is done at most. The time spent waiting is reported as RequestMetrics.Throttled. Defaults to no limits.
+		ReadLimit, WriteLimit *LimitConfig
+		// CoalesceReads shares one request between concurrent GETs of the same endpoint and query. Every caller
+		// decodes its own copy of the response, and the request is only cancelled once every caller has gone.
+		CoalesceReads bool
+		// ConditionalGets stores GET responses carrying an ETag or Last-Modified header and revalidates them with
+		// If-None-Match and If-Modified-Since. A 304 response returns the stored value, see WithFreshness.
+		ConditionalGets bool
+		// ConditionalGetEntries is the number of responses stored for ConditionalGets before the least recently
+		// used is evicted. Defaults to 1,000.
+		ConditionalGetEntries int
+		// WriteDedupeWindow returns the response of an identical create, a POST with the same endpoint, body and
+		// idempotency key, that succeeded less than this long ago instead of sending it again. Updates and deletes
+		// are never deduped. Defaults to no deduplication. Writes are always sent with an Idempotency-Key header,
+		// see WithIdempotencyKey.
+		WriteDedupeWindow time.Duration
+
+		// Logger receives a record for every request attempt with its method, path, status, attempt number,
+		// latency and error. Headers and bodies are never logged unless LogBodies is set. Defaults to no logging.
+		Logger *slog.Logger
+		// LogBodies additionally logs request headers and request and response bodies at debug level,
+		// with the Authorization header and sensitive fields such as emails and keys masked
+		LogBodies bool
+
+		// Metrics is called once per client call with its operation name, status class, attempts, duration
+		// and bytes, eg. to feed Prometheus or OpenTelemetry. Defaults to a NoopMetricsRecorder.
+		Metrics MetricsRecorder
+		// Tracer starts a span per client call with a child span per attempt, and injects the `traceparent`
+		// and `tracestate` headers of the attempt span into the request. Defaults to no tracing.
+		Tracer Tracer
 	}
 
 	// IDBClient interface contains all read & write methods to interact with the Portal HTTP DB
@@ -83,6 +131,19 @@ type (
 		GetBlockedContracts(ctx context.Context) (types.GlobalBlockedContracts, error)
 	}
 
+	// IDBLister interface contains the paginated List methods of the Portal HTTP DB, implemented by the DBClient,
+	// FakeDBClient and CachedReader. It is kept apart from IDBReader so existing IDBReader implementations keep compiling.
+	IDBLister interface {
+		// ListChains returns an Iterator over all chains, fetched a page at a time - GET `/v2/chain?limit={limit}&cursor={cursor}`
+		ListChains(ctx context.Context, options ChainOptions) *Iterator[types.Chain]
+		// ListGigastakeApps returns an Iterator over all GigastakeApps, fetched a page at a time - GET `/v2/gigastake?limit={limit}&cursor={cursor}`
+		ListGigastakeApps(ctx context.Context, options GigastakeAppOptions) *Iterator[types.GigastakeApp]
+		// ListPortalApps returns an Iterator over all Portal Apps, fetched a page at a time - GET `/v2/portal_app?limit={limit}&cursor={cursor}`
+		ListPortalApps(ctx context.Context, options PortalAppOptions) *Iterator[types.PortalApp]
+		// ListAccounts returns an Iterator over all Accounts, fetched a page at a time - GET `/v2/account?limit={limit}&cursor={cursor}`
+		ListAccounts(ctx context.Context, options AccountOptions) *Iterator[types.Account]
+	}
+
 	// IDBWriter interface contains write methods for interacting with the Portal HTTP DB
 	IDBWriter interface {
 		// CreateChainAndGigastakeApps creates a new blockchain and its Gigastake apps in the DB - POST `/v2/chain`
@@ -147,6 +208,8 @@ type (
 	QueryParam        string
 	commonQueryParams struct {
 		includeDeleted QueryParam
+		limit          QueryParam
+		cursor         QueryParam
 	}
 	chainQueryParams struct {
 		includeInactive      QueryParam
@@ -161,23 +224,33 @@ type (
 		Accepted        QueryParam
 	}
 
+	// Limit and Cursor are only accepted by the List* iterators: Limit sets the page size and Cursor the item
+	// the first page follows. The GetAll* methods return an UnsupportedOptionError for them.
 	ChainOptions struct {
 		ExcludeGigastakeApps *bool
 		IncludeInactive      *bool
 		IncludeDeleted       *bool
+		Limit                int
+		Cursor               string
 	}
 	GigastakeAppOptions struct {
 		IncludeDeleted *bool
+		Limit          int
+		Cursor         string
 	}
 	PortalAppOptions struct {
 		RoleNameFilters []types.RoleName
 		IncludeDeleted  *bool
 		Accepted        *bool
+		Limit           int
+		Cursor          string
 	}
 	AccountOptions struct {
 		RoleNameFilters []types.RoleName
 		IncludeDeleted  *bool
 		Accepted        *bool
+		Limit           int
+		Cursor          string
 	}
 )
 
@@ -207,6 +280,8 @@ const (
 var (
 	commonParams = commonQueryParams{
 		includeDeleted: "include_deleted",
+		limit:          "limit",
+		cursor:         "cursor",
 	}
 	ChainParams = chainQueryParams{
 		includeInactive:      "include_inactive",
@@ -223,6 +298,7 @@ var (
 
 	errBaseURLNotProvided error = errors.New("base URL not provided")
 	errAPIKeyNotProvided  error = errors.New("API key not provided")
+	errInvalidBaseURL     error = errors.New("invalid base URL")
 
 	errNoUserID           error = errors.New("no user ID")
 	errNoChainID          error = errors.New("no chain ID")
@@ -253,37 +329,46 @@ var (
 )
 
 // NewDBClient returns a read-write HTTP client to use the Portal HTTP DB - https://github.com/pokt-foundation/portal-http-db
+// The returned client also implements IDBLister.
 func NewDBClient(config Config) (IDBClient, error) {
-	if err := config.validateConfig(); err != nil {
-		return nil, err
-	}
-
-	return &DBClient{httpClient: newHTTPClient(config), config: config}, nil
+	return newDBClient(config)
 }
 
 // NewReadOnlyDBClient returns a read-only HTTP client to use the Portal HTTP DB - https://github.com/pokt-foundation/portal-http-db
 func NewReadOnlyDBClient(config Config) (IDBReader, error) {
+	return newDBClient(config)
+}
+
+func newDBClient(config Config) (*DBClient, error) {
 	if err := config.validateConfig(); err != nil {
 		return nil, err
 	}
 
+	// Requests are always built against the primary endpoint
+	config.BaseURL = config.baseURLs()[0]
+
 	return &DBClient{httpClient: newHTTPClient(config), config: config}, nil
 }
 
 // validateConfig ensures that a valid configuration is provided to the DB client
 func (c Config) validateConfig() error {
-	if c.BaseURL == "" {
+	if len(c.baseURLs()) == 0 {
 		return errBaseURLNotProvided
 	}
+	for _, baseURL := range c.baseURLs() {
+		if err := validateBaseURL(baseURL); err != nil {
+			return err
+		}
+	}
 	if c.APIKey == "" {
 		return errAPIKeyNotProvided
 	}
 	return nil
 }
 
-// v2BasePath returns the /v2/ base path for a given data type eg. `https://portal.http-db-url.com/v2/chain`
-func (db *DBClient) v2BasePath(dataTypePath basePath) string {
-	return fmt.Sprintf("%s/v2/%s", db.config.BaseURL, dataTypePath)
+// v2Endpoint returns the URL of a /v2/ route with every path segment escaped eg. `https://portal.http-db-url.com/v2/user/auth0%7Cuser_1`
+func (db *DBClient) v2Endpoint(segments ...string) string {
+	return buildEndpoint(db.config.BaseURL+"/v2", segments...)
 }
 
 // getAuthHeaderForRead returns the auth header for read operations to PHD
@@ -306,9 +391,9 @@ func (db *DBClient) GetChainByID(ctx context.Context, chainID types.RelayChainID
 		return nil, errNoChainID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(chainPath), chainID)
+	endpoint := db.v2Endpoint(string(chainPath), string(chainID))
 
-	return getReq[*types.Chain](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[*types.Chain](ctx, "GetChainByID", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetGigastakeAppByID returns a single GigastakeApp by its GigastakeAppID - GET `/v2/gigastake/{id}`
@@ -317,57 +402,53 @@ func (db *DBClient) GetGigastakeAppByID(ctx context.Context, gigastakeAppID type
 		return nil, errNoGigastakeAppID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(basePath(gigastakePath)), gigastakeAppID)
+	endpoint := db.v2Endpoint(string(gigastakePath), string(gigastakeAppID))
 
-	return getReq[*types.GigastakeApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[*types.GigastakeApp](ctx, "GetGigastakeAppByID", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetAllChains returns all chains - GET `/v2/chain`
 func (db *DBClient) GetAllChains(ctx context.Context, optionParams ...ChainOptions) ([]*types.Chain, error) {
-	endpoint := db.v2BasePath(chainPath)
-
-	options := ChainOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
+	query, err := encodeOptions("GetAllChains", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	queryParams := make([]string, 0)
-	if options.IncludeInactive != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", ChainParams.includeInactive, *options.IncludeInactive))
-	}
-	if options.ExcludeGigastakeApps != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", ChainParams.excludeGigastakeApps, *options.ExcludeGigastakeApps))
-	}
-	if options.IncludeDeleted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", commonParams.includeDeleted, *options.IncludeDeleted))
-	}
+	endpoint := withQuery(db.v2Endpoint(string(chainPath)), query)
+
+	return getReq[[]*types.Chain](ctx, "GetAllChains", endpoint, db.getAuthHeaderForRead(), db.httpClient)
+}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
+// ListChains returns an Iterator over all chains, fetched a page at a time - GET `/v2/chain?limit={limit}&cursor={cursor}`
+func (db *DBClient) ListChains(ctx context.Context, options ChainOptions) *Iterator[types.Chain] {
+	query, err := encodeOptions("ListChains", []ChainOptions{options})
+	if err != nil {
+		return errIterator[types.Chain](err)
 	}
 
-	return getReq[[]*types.Chain](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return listReq[types.Chain](ctx, "ListChains", db.v2Endpoint(string(chainPath)), query, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetAllGigastakeApps returns all GigastakeApps - GET `/v2/gigastake`
 func (db *DBClient) GetAllGigastakeApps(ctx context.Context, optionParams ...GigastakeAppOptions) ([]*types.GigastakeApp, error) {
-	endpoint := db.v2BasePath(basePath(gigastakePath))
-
-	options := GigastakeAppOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
+	query, err := encodeOptions("GetAllGigastakeApps", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	queryParams := make([]string, 0)
-	if options.IncludeDeleted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", commonParams.includeDeleted, *options.IncludeDeleted))
-	}
+	endpoint := withQuery(db.v2Endpoint(string(gigastakePath)), query)
+
+	return getReq[[]*types.GigastakeApp](ctx, "GetAllGigastakeApps", endpoint, db.getAuthHeaderForRead(), db.httpClient)
+}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
+// ListGigastakeApps returns an Iterator over all GigastakeApps, fetched a page at a time - GET `/v2/gigastake?limit={limit}&cursor={cursor}`
+func (db *DBClient) ListGigastakeApps(ctx context.Context, options GigastakeAppOptions) *Iterator[types.GigastakeApp] {
+	query, err := encodeOptions("ListGigastakeApps", []GigastakeAppOptions{options})
+	if err != nil {
+		return errIterator[types.GigastakeApp](err)
 	}
 
-	return getReq[[]*types.GigastakeApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return listReq[types.GigastakeApp](ctx, "ListGigastakeApps", db.v2Endpoint(string(gigastakePath)), query, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetAllGigastakeAppsByChain returns all GigastakeApps for a single chain ID - GET `/v2/chain/{id}/gigastake`
@@ -376,9 +457,9 @@ func (db *DBClient) GetAllGigastakeAppsByChain(ctx context.Context, chainID type
 		return nil, errNoChainID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/gigastake", db.v2BasePath(chainPath), chainID)
+	endpoint := db.v2Endpoint(string(chainPath), string(chainID), string(gigastakePath))
 
-	return getReq[[]*types.GigastakeApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[[]*types.GigastakeApp](ctx, "GetAllGigastakeAppsByChain", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* -- Portal App Read Methods -- */
@@ -389,35 +470,31 @@ func (db *DBClient) GetPortalAppByID(ctx context.Context, portalAppID types.Port
 		return nil, errNoPortalAppID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(portalAppPath), portalAppID)
+	endpoint := db.v2Endpoint(string(portalAppPath), string(portalAppID))
 
-	return getReq[*types.PortalApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[*types.PortalApp](ctx, "GetPortalAppByID", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetAllPortalApps returns all Portal Apps - GET `/v2/portal_app`
 func (db *DBClient) GetAllPortalApps(ctx context.Context, optionParams ...PortalAppOptions) ([]*types.PortalApp, error) {
-	if len(optionParams) > 1 {
-		return nil, errMoreThanOneOption
-	}
-
-	endpoint := db.v2BasePath(portalAppPath)
-
-	options := PortalAppOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
+	query, err := encodeOptions("GetAllPortalApps", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	queryParams := make([]string, 0)
+	endpoint := withQuery(db.v2Endpoint(string(portalAppPath)), query)
 
-	if options.IncludeDeleted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", commonParams.includeDeleted, *options.IncludeDeleted))
-	}
+	return getReq[[]*types.PortalApp](ctx, "GetAllPortalApps", endpoint, db.getAuthHeaderForRead(), db.httpClient)
+}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
+// ListPortalApps returns an Iterator over all Portal Apps, fetched a page at a time - GET `/v2/portal_app?limit={limit}&cursor={cursor}`
+func (db *DBClient) ListPortalApps(ctx context.Context, options PortalAppOptions) *Iterator[types.PortalApp] {
+	query, err := encodeOptions("ListPortalApps", []PortalAppOptions{options})
+	if err != nil {
+		return errIterator[types.PortalApp](err)
 	}
 
-	return getReq[[]*types.PortalApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return listReq[types.PortalApp](ctx, "ListPortalApps", db.v2Endpoint(string(portalAppPath)), query, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetPortalAppsByUser fetches all portal applications - GET `/v2/user/{userID}/portal_app`
@@ -425,78 +502,46 @@ func (db *DBClient) GetPortalAppsByUser(ctx context.Context, userID types.UserID
 	if userID == "" {
 		return nil, errNoUserID
 	}
-	if len(optionParams) > 1 {
-		return nil, errMoreThanOneOption
-	}
-
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(userPath), userID, portalAppPath)
-
-	options := PortalAppOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
-	}
-
-	queryParams := make([]string, 0)
-
-	if len(options.RoleNameFilters) > 0 {
-		roleNameStrs := make([]string, len(options.RoleNameFilters))
-		for i, roleName := range options.RoleNameFilters {
-			if !roleName.IsValid() {
-				return nil, fmt.Errorf("%w: %s", errInvalidRoleName, roleName)
-			}
-			roleNameStrs[i] = string(roleName)
-		}
-		queryParams = append(queryParams, fmt.Sprintf("%s=%s", PortalAppParams.RoleNameFilters, strings.Join(roleNameStrs, ",")))
-	}
 
-	if options.IncludeDeleted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", commonParams.includeDeleted, *options.IncludeDeleted))
-	}
-
-	if options.Accepted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", PortalAppParams.Accepted, *options.Accepted))
+	query, err := encodeOptions("GetPortalAppsByUser", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
-	}
+	endpoint := withQuery(db.v2Endpoint(string(userPath), string(userID), string(portalAppPath)), query)
 
-	return getReq[[]*types.PortalApp](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[[]*types.PortalApp](ctx, "GetPortalAppsByUser", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetPortalAppsForMiddleware returns all Portal Apps - GET `/v2/middleware/portal_app`
 func (db *DBClient) GetPortalAppsForMiddleware(ctx context.Context) ([]*types.PortalAppLite, error) {
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(middlewarePath), portalAppPath)
+	endpoint := db.v2Endpoint(string(middlewarePath), string(portalAppPath))
 
-	return getReq[[]*types.PortalAppLite](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[[]*types.PortalAppLite](ctx, "GetPortalAppsForMiddleware", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* -- Account Read Methods -- */
 
 // GetAllAccounts returns all Accounts - GET `/v2/account`
 func (db *DBClient) GetAllAccounts(ctx context.Context, optionParams ...AccountOptions) ([]*types.Account, error) {
-	if len(optionParams) > 1 {
-		return nil, errMoreThanOneOption
-	}
-
-	endpoint := db.v2BasePath(accountPath)
-
-	options := AccountOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
+	query, err := encodeOptions("GetAllAccounts", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	queryParams := make([]string, 0)
+	endpoint := withQuery(db.v2Endpoint(string(accountPath)), query)
 
-	if options.IncludeDeleted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", commonParams.includeDeleted, *options.IncludeDeleted))
-	}
+	return getReq[[]*types.Account](ctx, "GetAllAccounts", endpoint, db.getAuthHeaderForRead(), db.httpClient)
+}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
+// ListAccounts returns an Iterator over all Accounts, fetched a page at a time - GET `/v2/account?limit={limit}&cursor={cursor}`
+func (db *DBClient) ListAccounts(ctx context.Context, options AccountOptions) *Iterator[types.Account] {
+	query, err := encodeOptions("ListAccounts", []AccountOptions{options})
+	if err != nil {
+		return errIterator[types.Account](err)
 	}
 
-	return getReq[[]*types.Account](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return listReq[types.Account](ctx, "ListAccounts", db.v2Endpoint(string(accountPath)), query, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetUserAccounts returns all Accounts for a given user ID - GET `/v2/user/{userID}/account`
@@ -504,39 +549,15 @@ func (db *DBClient) GetUserAccounts(ctx context.Context, userID types.UserID, op
 	if userID == "" {
 		return nil, errNoUserID
 	}
-	if len(optionParams) > 1 {
-		return nil, errMoreThanOneOption
-	}
-
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(userPath), userID, accountPath)
-
-	options := AccountOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
-	}
-
-	queryParams := make([]string, 0)
-
-	if len(options.RoleNameFilters) > 0 {
-		roleNameStrs := make([]string, len(options.RoleNameFilters))
-		for i, roleName := range options.RoleNameFilters {
-			if !roleName.IsValid() {
-				return nil, fmt.Errorf("%w: %s", errInvalidRoleName, roleName)
-			}
-			roleNameStrs[i] = string(roleName)
-		}
-		queryParams = append(queryParams, fmt.Sprintf("%s=%s", AccountParams.RoleNameFilters, strings.Join(roleNameStrs, ",")))
-	}
 
-	if options.Accepted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", AccountParams.Accepted, *options.Accepted))
+	query, err := encodeOptions("GetUserAccounts", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
-	}
+	endpoint := withQuery(db.v2Endpoint(string(userPath), string(userID), string(accountPath)), query)
 
-	return getReq[[]*types.Account](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[[]*types.Account](ctx, "GetUserAccounts", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetUserAccount returns a single user Account by its account ID and user ID - GET `/v2/user/{userID}/account/{id}`
@@ -547,39 +568,15 @@ func (db *DBClient) GetUserAccount(ctx context.Context, accountID types.AccountI
 	if userID == "" {
 		return nil, errNoUserID
 	}
-	if len(optionParams) > 1 {
-		return nil, errMoreThanOneOption
-	}
-
-	endpoint := fmt.Sprintf("%s/%s/%s/%s", db.v2BasePath(userPath), userID, accountPath, accountID)
-
-	options := AccountOptions{}
-	if len(optionParams) > 0 {
-		options = optionParams[0]
-	}
-
-	queryParams := make([]string, 0)
-
-	if len(options.RoleNameFilters) > 0 {
-		roleNameStrs := make([]string, len(options.RoleNameFilters))
-		for i, roleName := range options.RoleNameFilters {
-			if !roleName.IsValid() {
-				return nil, fmt.Errorf("%w: %s", errInvalidRoleName, roleName)
-			}
-			roleNameStrs[i] = string(roleName)
-		}
-		queryParams = append(queryParams, fmt.Sprintf("%s=%s", AccountParams.RoleNameFilters, strings.Join(roleNameStrs, ",")))
-	}
 
-	if options.Accepted != nil {
-		queryParams = append(queryParams, fmt.Sprintf("%s=%t", AccountParams.Accepted, *options.Accepted))
+	query, err := encodeOptions("GetUserAccount", optionParams)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(queryParams) > 0 {
-		endpoint = fmt.Sprintf("%s?%s", endpoint, strings.Join(queryParams, "&"))
-	}
+	endpoint := withQuery(db.v2Endpoint(string(userPath), string(userID), string(accountPath), string(accountID)), query)
 
-	return getReq[*types.Account](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[*types.Account](ctx, "GetUserAccount", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* -- User Read Methods -- */
@@ -591,9 +588,9 @@ func (db *DBClient) GetPortalUser(ctx context.Context, userID string) (*types.Us
 		return &types.User{}, errNoUserID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s?full_details=true", db.v2BasePath(userPath), userID)
+	endpoint := withQuery(db.v2Endpoint(string(userPath), userID), url.Values{"full_details": {"true"}})
 
-	return getReq[*types.User](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[*types.User](ctx, "GetPortalUser", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 // GetPortalUserID returns the Portal User for a given user ID, either provider ID or portal ID - GET `/v2/user/{userID}`
@@ -603,27 +600,27 @@ func (db *DBClient) GetPortalUserID(ctx context.Context, userID string) (types.U
 		return types.UserID(""), errNoUserID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(userPath), userID)
+	endpoint := db.v2Endpoint(string(userPath), string(userID))
 
-	return getReq[types.UserID](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[types.UserID](ctx, "GetPortalUserID", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* -- Plans Read Methods -- */
 
 // GetAllPlans returns all plans - GET `/v2/plan`
 func (db *DBClient) GetAllPlans(ctx context.Context) ([]types.Plan, error) {
-	endpoint := db.v2BasePath(planPath)
+	endpoint := db.v2Endpoint(string(planPath))
 
-	return getReq[[]types.Plan](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[[]types.Plan](ctx, "GetAllPlans", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* -- Blocked Contracts Read Methods -- */
 
 // GetBlockedContracts returns all blocked contracts - GET `/v2/blocked_contract`
 func (db *DBClient) GetBlockedContracts(ctx context.Context) (types.GlobalBlockedContracts, error) {
-	endpoint := db.v2BasePath(blockedContractPath)
+	endpoint := db.v2Endpoint(string(blockedContractPath))
 
-	return getReq[types.GlobalBlockedContracts](endpoint, db.getAuthHeaderForRead(), db.httpClient)
+	return getReq[types.GlobalBlockedContracts](ctx, "GetBlockedContracts", endpoint, db.getAuthHeaderForRead(), db.httpClient)
 }
 
 /* ------------ IDBWriter Methods ------------ */
@@ -637,9 +634,9 @@ func (db *DBClient) CreateChainAndGigastakeApps(ctx context.Context, newChainInp
 		return nil, fmt.Errorf("%w: %s", errInvalidChainJSON, err)
 	}
 
-	endpoint := db.v2BasePath(chainPath)
+	endpoint := db.v2Endpoint(string(chainPath))
 
-	return postReq[*types.NewChainInput](endpoint, db.getAuthHeaderForWrite(), newChainInputJSON, db.httpClient)
+	return postReq[*types.NewChainInput](ctx, "CreateChainAndGigastakeApps", endpoint, db.getAuthHeaderForWrite(), newChainInputJSON, db.httpClient)
 }
 
 // CreateGigastakeApp creates a new Gigastake app in the DB - POST `/v2/chain/gigastake`
@@ -649,9 +646,9 @@ func (db *DBClient) CreateGigastakeApp(ctx context.Context, gigastakeAppInput ty
 		return nil, fmt.Errorf("%w: %s", errInvalidGigastakeAppJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(chainPath), gigastakePath)
+	endpoint := db.v2Endpoint(string(chainPath), string(gigastakePath))
 
-	return postReq[*types.GigastakeApp](endpoint, db.getAuthHeaderForWrite(), gigastakeAppInputJSON, db.httpClient)
+	return postReq[*types.GigastakeApp](ctx, "CreateGigastakeApp", endpoint, db.getAuthHeaderForWrite(), gigastakeAppInputJSON, db.httpClient)
 }
 
 // UpdateChain updates an existing blockchain in the DB - PUT `/v2/chain/{id}`
@@ -665,9 +662,12 @@ func (db *DBClient) UpdateChain(ctx context.Context, chainUpdate types.UpdateCha
 		return nil, fmt.Errorf("%w: %s", errInvalidChainJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(chainPath), chainUpdate.ID)
+	endpoint := db.v2Endpoint(string(chainPath), string(chainUpdate.ID))
 
-	return putReq[*types.Chain](endpoint, db.getAuthHeaderForWrite(), chainUpdateJSON, db.httpClient)
+	chain, err := putReq[*types.Chain](ctx, "UpdateChain", endpoint, db.getAuthHeaderForWrite(), chainUpdateJSON, db.httpClient)
+	return chain, withCurrentCopy(ctx, err, func(ctx context.Context) (*types.Chain, error) {
+		return db.GetChainByID(ctx, chainUpdate.ID)
+	})
 }
 
 // UpdateGigastakeApp updates a Gigastake app in the DB - PUT `/v2/chain/gigastake/{id}`
@@ -681,9 +681,9 @@ func (db *DBClient) UpdateGigastakeApp(ctx context.Context, id types.GigastakeAp
 		return nil, fmt.Errorf("%w: %s", errInvalidGigastakeAppJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(chainPath), gigastakePath, id)
+	endpoint := db.v2Endpoint(string(chainPath), string(gigastakePath), string(id))
 
-	return putReq[*types.UpdateGigastakeApp](endpoint, db.getAuthHeaderForWrite(), updateGigastakeAppJSON, db.httpClient)
+	return putReq[*types.UpdateGigastakeApp](ctx, "UpdateGigastakeApp", endpoint, db.getAuthHeaderForWrite(), updateGigastakeAppJSON, db.httpClient)
 }
 
 // ActivateChain activates or deactivates a blockchain by ID in the DB - PUT `/v2/chain/{id}/activate`
@@ -693,9 +693,9 @@ func (db *DBClient) ActivateChain(ctx context.Context, chainID types.RelayChainI
 		return false, fmt.Errorf("%w: %s", errInvalidActiveStatusJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(chainPath), chainID, activatePath)
+	endpoint := db.v2Endpoint(string(chainPath), string(chainID), string(activatePath))
 
-	return putReq[bool](endpoint, db.getAuthHeaderForWrite(), activeJSON, db.httpClient)
+	return putReq[bool](ctx, "ActivateChain", endpoint, db.getAuthHeaderForWrite(), activeJSON, db.httpClient)
 }
 
 /* -- Portal App Write Methods -- */
@@ -707,9 +707,9 @@ func (db *DBClient) CreatePortalApp(ctx context.Context, portalAppInput types.Po
 		return nil, fmt.Errorf("%w: %s", errInvalidPortalAppJSON, err)
 	}
 
-	endpoint := db.v2BasePath(portalAppPath)
+	endpoint := db.v2Endpoint(string(portalAppPath))
 
-	return postReq[*types.PortalApp](endpoint, db.getAuthHeaderForWrite(), portalAppInputJSON, db.httpClient)
+	return postReq[*types.PortalApp](ctx, "CreatePortalApp", endpoint, db.getAuthHeaderForWrite(), portalAppInputJSON, db.httpClient)
 }
 
 // UpdatePortalApp updates an existing Portal App - PUT `/v2/portal_app/{id}`
@@ -719,9 +719,12 @@ func (db *DBClient) UpdatePortalApp(ctx context.Context, portalAppUpdate types.U
 		return nil, fmt.Errorf("%w: %s", errInvalidPortalAppJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(portalAppPath), portalAppUpdate.AppID)
+	endpoint := db.v2Endpoint(string(portalAppPath), string(portalAppUpdate.AppID))
 
-	return putReq[*types.UpdatePortalApp](endpoint, db.getAuthHeaderForWrite(), portalAppUpdateJSON, db.httpClient)
+	updated, err := putReq[*types.UpdatePortalApp](ctx, "UpdatePortalApp", endpoint, db.getAuthHeaderForWrite(), portalAppUpdateJSON, db.httpClient)
+	return updated, withCurrentCopy(ctx, err, func(ctx context.Context) (*types.PortalApp, error) {
+		return db.GetPortalAppByID(ctx, portalAppUpdate.AppID)
+	})
 }
 
 // DeletePortalApp deletes a Portal App - DELETE `/v2/portal_app/{id}`
@@ -730,9 +733,9 @@ func (db *DBClient) DeletePortalApp(ctx context.Context, portalAppID types.Porta
 		return nil, errNoPortalAppID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(portalAppPath), portalAppID)
+	endpoint := db.v2Endpoint(string(portalAppPath), string(portalAppID))
 
-	return deleteReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), db.httpClient)
+	return deleteReq[map[string]string](ctx, "DeletePortalApp", endpoint, db.getAuthHeaderForWrite(), db.httpClient)
 }
 
 // UpdatePortalAppsFirstDateSurpassed updates the FirstDateSurpassed field of one or more Portal Apps - POST `/v2/portal_app/first_date_surpassed`
@@ -742,9 +745,9 @@ func (db *DBClient) UpdatePortalAppsFirstDateSurpassed(ctx context.Context, firs
 		return nil, fmt.Errorf("%w: %s", errInvalidFirstDateSurpassedUpdateJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(portalAppPath), "first_date_surpassed")
+	endpoint := db.v2Endpoint(string(portalAppPath), "first_date_surpassed")
 
-	return postReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), firstDateSurpassedUpdateJSON, db.httpClient)
+	return postReq[map[string]string](ctx, "UpdatePortalAppsFirstDateSurpassed", endpoint, db.getAuthHeaderForWrite(), firstDateSurpassedUpdateJSON, db.httpClient)
 }
 
 /* -- Account Write Methods -- */
@@ -763,9 +766,9 @@ func (db *DBClient) CreateAccount(ctx context.Context, userID types.UserID, acco
 		return nil, fmt.Errorf("%w: %s", errInvalidAccountJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(userPath), userID, accountPath)
+	endpoint := db.v2Endpoint(string(userPath), string(userID), string(accountPath))
 
-	return postReq[*types.Account](endpoint, db.getAuthHeaderForWrite(), accountJSON, db.httpClient)
+	return postReq[*types.Account](ctx, "CreateAccount", endpoint, db.getAuthHeaderForWrite(), accountJSON, db.httpClient)
 }
 
 // UpdateAccount updates an Account in the DB - PUT `/v2/account/{id}`
@@ -775,9 +778,11 @@ func (db *DBClient) UpdateAccount(ctx context.Context, account types.UpdateAccou
 		return nil, fmt.Errorf("%w: %s", errInvalidAccountJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(accountPath), account.AccountID)
+	endpoint := db.v2Endpoint(string(accountPath), string(account.AccountID))
 
-	return putReq[*types.Account](endpoint, db.getAuthHeaderForWrite(), accountJSON, db.httpClient)
+	// PHD has no single account lookup, so the ConflictError of an account carries no current copy
+	updated, err := putReq[*types.Account](ctx, "UpdateAccount", endpoint, db.getAuthHeaderForWrite(), accountJSON, db.httpClient)
+	return updated, withCurrentCopy[types.Account](ctx, err, nil)
 }
 
 // CreateAccountIntegration creates an AccountIntegration in the DB - POST `/v2/account/{id}/integration`
@@ -787,9 +792,9 @@ func (db *DBClient) CreateAccountIntegration(ctx context.Context, accountID type
 		return nil, fmt.Errorf("%w: %s", errInvalidAccountIntegrationJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(accountPath), accountID, integrationSubPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(accountID), string(integrationSubPath))
 
-	return postReq[*types.AccountIntegrations](endpoint, db.getAuthHeaderForWrite(), integrationJSON, db.httpClient)
+	return postReq[*types.AccountIntegrations](ctx, "CreateAccountIntegration", endpoint, db.getAuthHeaderForWrite(), integrationJSON, db.httpClient)
 }
 
 // UpdateAccountIntegration updates an AccountIntegration in the DB - PUT `/v2/account/{id}/integration`
@@ -799,9 +804,9 @@ func (db *DBClient) UpdateAccountIntegration(ctx context.Context, accountID type
 		return nil, fmt.Errorf("%w: %s", errInvalidAccountIntegrationJSON, err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(accountPath), accountID, integrationSubPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(accountID), string(integrationSubPath))
 
-	return putReq[*types.AccountIntegrations](endpoint, db.getAuthHeaderForWrite(), integrationJSON, db.httpClient)
+	return putReq[*types.AccountIntegrations](ctx, "UpdateAccountIntegration", endpoint, db.getAuthHeaderForWrite(), integrationJSON, db.httpClient)
 }
 
 // DeleteAccount deletes an Account in the DB - DELETE `/v2/account/{id}`
@@ -810,9 +815,9 @@ func (db *DBClient) DeleteAccount(ctx context.Context, accountID types.AccountID
 		return nil, errNoAccountID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(accountPath), accountID)
+	endpoint := db.v2Endpoint(string(accountPath), string(accountID))
 
-	return deleteReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), db.httpClient)
+	return deleteReq[map[string]string](ctx, "DeleteAccount", endpoint, db.getAuthHeaderForWrite(), db.httpClient)
 }
 
 /* -- Account User Write Methods -- */
@@ -837,9 +842,9 @@ func (db *DBClient) WriteAccountUser(ctx context.Context, createUser types.Creat
 		return nil, fmt.Errorf("invalid createUser JSON: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(accountPath), userPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(userPath))
 
-	return postReq[map[string]types.UserID](endpoint, db.getAuthHeaderForWrite(), createUserJSON, db.httpClient)
+	return postReq[map[string]types.UserID](ctx, "WriteAccountUser", endpoint, db.getAuthHeaderForWrite(), createUserJSON, db.httpClient)
 }
 
 // SetAccountUserRole updates the role for a single Account User - PUT `/v2/account/user/update_role`
@@ -862,9 +867,9 @@ func (db *DBClient) SetAccountUserRole(ctx context.Context, updateUser types.Upd
 		return nil, fmt.Errorf("invalid updateUser JSON: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(accountPath), userPath, updateRoleSubPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(userPath), string(updateRoleSubPath))
 
-	return putReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), updateUserJSON, db.httpClient)
+	return putReq[map[string]string](ctx, "SetAccountUserRole", endpoint, db.getAuthHeaderForWrite(), updateUserJSON, db.httpClient)
 }
 
 // UpdateAcceptAccountUser accepts or declines an Account User Access - PUT `/v2/account/user/accept`
@@ -887,9 +892,9 @@ func (db *DBClient) UpdateAcceptAccountUser(ctx context.Context, acceptUser type
 		return nil, fmt.Errorf("invalid acceptUser JSON: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(accountPath), userPath, acceptSubPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(userPath), string(acceptSubPath))
 
-	return putReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), acceptUserJSON, db.httpClient)
+	return putReq[map[string]string](ctx, "UpdateAcceptAccountUser", endpoint, db.getAuthHeaderForWrite(), acceptUserJSON, db.httpClient)
 }
 
 // RemoveAccountUser removes an Account User's Role - PUT `/v2/account/user/remove`
@@ -909,9 +914,9 @@ func (db *DBClient) RemoveAccountUser(ctx context.Context, removeUser types.Upda
 		return nil, fmt.Errorf("invalid removeUser JSON: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(accountPath), userPath, removeSubPath)
+	endpoint := db.v2Endpoint(string(accountPath), string(userPath), string(removeSubPath))
 
-	return putReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), removeUserJSON, db.httpClient)
+	return putReq[map[string]string](ctx, "RemoveAccountUser", endpoint, db.getAuthHeaderForWrite(), removeUserJSON, db.httpClient)
 }
 
 /* -- User Write Methods -- */
@@ -927,9 +932,9 @@ func (db *DBClient) CreateUser(ctx context.Context, user types.CreateUser) (*typ
 		return nil, fmt.Errorf("invalid user JSON: %w", err)
 	}
 
-	endpoint := db.v2BasePath(userPath)
+	endpoint := db.v2Endpoint(string(userPath))
 
-	return postReq[*types.CreateUserResponse](endpoint, db.getAuthHeaderForWrite(), userJSON, db.httpClient)
+	return postReq[*types.CreateUserResponse](ctx, "CreateUser", endpoint, db.getAuthHeaderForWrite(), userJSON, db.httpClient)
 }
 
 // UpdateUser updates an existing User in the database - PUT `/v2/user`
@@ -943,9 +948,12 @@ func (db *DBClient) UpdateUser(ctx context.Context, user types.UpdateUser) (*typ
 		return nil, fmt.Errorf("invalid update user JSON: %w", err)
 	}
 
-	endpoint := db.v2BasePath(userPath)
+	endpoint := db.v2Endpoint(string(userPath))
 
-	return putReq[*types.User](endpoint, db.getAuthHeaderForWrite(), userJSON, db.httpClient)
+	updated, err := putReq[*types.User](ctx, "UpdateUser", endpoint, db.getAuthHeaderForWrite(), userJSON, db.httpClient)
+	return updated, withCurrentCopy(ctx, err, func(ctx context.Context) (*types.User, error) {
+		return db.GetPortalUser(ctx, string(user.ID))
+	})
 }
 
 // DeleteUser deletes a User - DELETE `/v2/user/{userID}`
@@ -954,9 +962,9 @@ func (db *DBClient) DeleteUser(ctx context.Context, userID types.UserID) (map[st
 		return nil, errNoUserID
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(userPath), userID)
+	endpoint := db.v2Endpoint(string(userPath), string(userID))
 
-	return deleteReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), db.httpClient)
+	return deleteReq[map[string]string](ctx, "DeleteUser", endpoint, db.getAuthHeaderForWrite(), db.httpClient)
 }
 
 /* -- Blocked Contracts Write Methods -- */
@@ -968,9 +976,9 @@ func (db *DBClient) WriteBlockedContract(ctx context.Context, blockedContract ty
 		return nil, fmt.Errorf("%w: %s", errInvalidBlockedContractJSON, err)
 	}
 
-	endpoint := db.v2BasePath(blockedContractPath)
+	endpoint := db.v2Endpoint(string(blockedContractPath))
 
-	return postReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), blockedContractJSON, db.httpClient)
+	return postReq[map[string]string](ctx, "WriteBlockedContract", endpoint, db.getAuthHeaderForWrite(), blockedContractJSON, db.httpClient)
 }
 
 // UpdateBlockedContractActive updates the active status of a blocked contract - PUT `/v2/blocked_contract/{address}/active`
@@ -990,9 +998,9 @@ func (db *DBClient) UpdateBlockedContractActive(ctx context.Context, address typ
 		return nil, errInvalidAppJSON
 	}
 
-	endpoint := fmt.Sprintf("%s/%s/%s", db.v2BasePath(blockedContractPath), address, activePath)
+	endpoint := db.v2Endpoint(string(blockedContractPath), string(address), string(activePath))
 
-	return putReq[map[string]bool](endpoint, db.getAuthHeaderForWrite(), activeStatusJSON, db.httpClient)
+	return putReq[map[string]bool](ctx, "UpdateBlockedContractActive", endpoint, db.getAuthHeaderForWrite(), activeStatusJSON, db.httpClient)
 }
 
 // RemoveBlockedContract deletes a blocked address from the global blocked contracts - DELETE `/v2/blocked_contract/{address}`
@@ -1001,157 +1009,77 @@ func (db *DBClient) RemoveBlockedContract(ctx context.Context, address types.Blo
 		return nil, errNoBlockedAddress
 	}
 
-	endpoint := fmt.Sprintf("%s/%s", db.v2BasePath(blockedContractPath), address)
+	endpoint := db.v2Endpoint(string(blockedContractPath), string(address))
 
-	return deleteReq[map[string]string](endpoint, db.getAuthHeaderForWrite(), db.httpClient)
+	return deleteReq[map[string]string](ctx, "RemoveBlockedContract", endpoint, db.getAuthHeaderForWrite(), db.httpClient)
 }
 
 /* ------------ PHD Client HTTP Funcs ------------ */
 
 func newHTTPClient(config Config) *http.Client {
-	return &http.Client{
-		Timeout: config.Timeout,
-		Transport: &retryTransport{
-			underlying: http.DefaultTransport,
-			retries:    config.Retries,
-		},
+	var underlying http.RoundTripper = http.DefaultTransport
+	if config.Transport != nil {
+		underlying = config.Transport
 	}
-}
-
-func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	rt := t.underlying
-	if rt == nil {
-		rt = http.DefaultTransport
+	if config.CircuitBreaker != nil {
+		underlying = newCircuitBreakerTransport(*config.CircuitBreaker, underlying)
 	}
-
-	var resp *http.Response
-	var err error
-
-	// Cache request body
-	var bodyBytes []byte
-	if req.Body != nil {
-		bodyBytes, err = io.ReadAll(req.Body)
-		if err != nil {
-			return nil, err
-		}
+	if len(config.baseURLs()) > 1 {
+		underlying = newEndpointPool(config, underlying)
 	}
 
-	for i := 0; i <= t.retries; i++ {
-		// Recreate body reader
-		if bodyBytes != nil {
-			req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		}
-
-		resp, err = rt.RoundTrip(req)
-		if err == nil && resp.StatusCode < 500 {
-			break
-		}
-
-		if i < t.retries {
-			time.Sleep(time.Duration(i*i) * 100 * time.Millisecond)
-		}
+	var transport http.RoundTripper = newIdempotencyTransport(config, &retryTransport{
+		underlying: underlying,
+		policy:     config.retryPolicy(),
+		logger:     newRequestLogger(config),
+		metrics:    config.metricsRecorder(),
+		tracer:     config.Tracer,
+		limiter:    newRequestLimiter(config),
+	})
+	if config.CoalesceReads {
+		transport = newCoalescingTransport(transport)
 	}
-
-	if err != nil {
-		return nil, err
+	if config.ConditionalGets {
+		transport = newConditionalTransport(config, transport)
 	}
 
-	return resp, nil
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: transport,
+	}
 }
 
 // Generic HTTP GET request
-func getReq[T any](endpoint string, header http.Header, httpClient *http.Client) (T, error) {
-	var data T
-
-	// Create a new request
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
-	if err != nil {
-		return data, err
-	}
-
-	// Set headers
-	req.Header = header
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return data, err
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return data, parseErrorResponse(resp)
-	}
-
-	// Decode response body
-	err = json.NewDecoder(resp.Body).Decode(&data)
-	if err != nil {
-		return data, err
-	}
-
-	return data, nil
+func getReq[T any](ctx context.Context, operation, endpoint string, header http.Header, httpClient *http.Client) (T, error) {
+	return sendReq[T](ctx, operation, http.MethodGet, endpoint, header, nil, httpClient)
 }
 
 // Generic HTTP POST request
-func postReq[T any](endpoint string, header http.Header, postData []byte, httpClient *http.Client) (T, error) {
-	var data T
-
-	// Create a new request
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(postData))
-	if err != nil {
-		return data, err
-	}
-
-	// Set headers
-	req.Header = header
-
-	// Send the request
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return data, err
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return data, parseErrorResponse(resp)
-	}
-
-	// Decode response body
-	err = json.NewDecoder(resp.Body).Decode(&data)
-	if err != nil {
-		return data, err
-	}
-
-	return data, nil
+func postReq[T any](ctx context.Context, operation, endpoint string, header http.Header, postData []byte, httpClient *http.Client) (T, error) {
+	return sendReq[T](ctx, operation, http.MethodPost, endpoint, header, postData, httpClient)
 }
 
 // Generic HTTP PUT request
-func putReq[T any](endpoint string, header http.Header, putData []byte, httpClient *http.Client) (T, error) {
-	var data T
+func putReq[T any](ctx context.Context, operation, endpoint string, header http.Header, putData []byte, httpClient *http.Client) (T, error) {
+	return sendReq[T](ctx, operation, http.MethodPut, endpoint, header, putData, httpClient)
+}
 
-	// Create a new request
-	req, err := http.NewRequest(http.MethodPut, endpoint, bytes.NewBuffer(putData))
-	if err != nil {
-		return data, err
-	}
+// Generic HTTP DELETE request
+func deleteReq[T any](ctx context.Context, operation, endpoint string, header http.Header, httpClient *http.Client) (T, error) {
+	return sendReq[T](ctx, operation, http.MethodDelete, endpoint, header, nil, httpClient)
+}
 
-	// Set headers
-	req.Header = header
+// sendReq builds a request bound to the caller's context, sends it and decodes the JSON response into T.
+// operation is the name of the calling client method, reported to the MetricsRecorder.
+func sendReq[T any](ctx context.Context, operation, method, endpoint string, header http.Header, body []byte, httpClient *http.Client) (T, error) {
+	var data T
 
-	// Send the request
-	resp, err := httpClient.Do(req)
+	resp, err := doReq(ctx, operation, method, endpoint, header, body, httpClient)
 	if err != nil {
 		return data, err
 	}
 	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return data, parseErrorResponse(resp)
-	}
-
 	// Decode response body
 	err = json.NewDecoder(resp.Body).Decode(&data)
 	if err != nil {
@@ -1161,61 +1089,68 @@ func putReq[T any](endpoint string, header http.Header, putData []byte, httpClie
 	return data, nil
 }
 
-// Generic HTTP DELETE request
-func deleteReq[T any](endpoint string, header http.Header, httpClient *http.Client) (T, error) {
-	var data T
+// doReq sends the request and returns the response if its status is OK, leaving the caller to close the body
+func doReq(ctx context.Context, operation, method, endpoint string, header http.Header, body []byte, httpClient *http.Client) (*http.Response, error) {
+	ctx = withOperation(ctx, operation)
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewBuffer(body)
+	}
 
 	// Create a new request
-	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
 	if err != nil {
-		return data, err
+		return nil, err
 	}
 
 	// Set headers
 	req.Header = header
+	if updatedAt, ok := ifMatchFromContext(ctx); ok && !isReadMethod(method) {
+		req.Header = header.Clone()
+		req.Header.Set("If-Match", IfMatchUpdatedAt(updatedAt))
+	}
 
 	// Send the request
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return data, err
+		// Surface cancellation and deadlines as the plain context errors
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		return data, parseErrorResponse(resp)
-	}
-
-	// Decode response body
-	err = json.NewDecoder(resp.Body).Decode(&data)
-	if err != nil {
-		return data, err
+		defer resp.Body.Close()
+		return nil, parseErrorResponse(method, endpoint, resp)
 	}
 
-	return data, nil
+	return resp, nil
 }
 
-// Parses the error reponse and returns the status code and error message
-func parseErrorResponse(errResponse *http.Response) error {
-	code := errResponse.StatusCode
-	text := http.StatusText(code)
-
-	errString := fmt.Errorf("%s. %d %s", errResponseNotOK, code, text)
+// Parses the error reponse into an APIError carrying the status code and error message
+func parseErrorResponse(method, endpoint string, errResponse *http.Response) error {
+	apiErr := &APIError{
+		StatusCode: errResponse.StatusCode,
+		Method:     method,
+		Endpoint:   endpoint,
+	}
 
 	body, err := io.ReadAll(errResponse.Body)
 	if err != nil {
-		return errString
+		return apiErr
 	}
+	apiErr.Body = body
 
 	var errorMap map[string]string
 	err = json.Unmarshal(body, &errorMap)
 	if err != nil {
-		return errString
+		return apiErr
 	}
 
-	if errorMessage, ok := errorMap["error"]; ok {
-		errString = fmt.Errorf("%s: %s", errString, errorMessage)
-	}
+	apiErr.Message = errorMap["error"]
 
-	return errString
+	return apiErr
 }
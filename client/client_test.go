@@ -2,10 +2,14 @@ package dbclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"sort"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -32,6 +36,10 @@ func Test_DBClientImplementsInterfaces(t *testing.T) {
 			name:   "Should verify that DBClient implements the IDBWriter interface",
 			client: &DBClient{},
 		},
+		{
+			name:   "Should verify that DBClient implements the IDBLister interface",
+			client: &DBClient{},
+		},
 	}
 
 	for _, test := range tests {
@@ -53,11 +61,82 @@ func Test_DBClientImplementsInterfaces(t *testing.T) {
 				assert.Implements(t, (*IDBReader)(nil), test.client)
 			case "Should verify that DBClient implements the IDBWriter interface":
 				assert.Implements(t, (*IDBWriter)(nil), test.client)
+			case "Should verify that DBClient implements the IDBLister interface":
+				assert.Implements(t, (*IDBLister)(nil), test.client)
 			}
 		})
 	}
 }
 
+func Test_ContextPropagation(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         func() (context.Context, context.CancelFunc)
+		retries     int
+		expectedErr error
+		maxAttempts int32
+	}{
+		{
+			name: "Should stop retrying and return context.DeadlineExceeded when the deadline passes during backoff",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			retries:     10,
+			expectedErr: context.DeadlineExceeded,
+			maxAttempts: 2,
+		},
+		{
+			name: "Should not send any request and return context.Canceled when the context is already cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			retries:     10,
+			expectedErr: context.Canceled,
+			maxAttempts: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			client, err := NewDBClient(Config{
+				BaseURL:     server.URL,
+				APIKey:      "test_api_key_6789",
+				Timeout:     10 * time.Second,
+				RetryPolicy: fixedRetryPolicy{retries: test.retries, delay: 40 * time.Millisecond},
+			})
+			assert.NoError(t, err)
+
+			ctx, cancel := test.ctx()
+			defer cancel()
+
+			start := time.Now()
+			_, err = client.GetChainByID(ctx, "0001")
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Less(t, time.Since(start), 2*time.Second)
+			assert.LessOrEqual(t, attempts.Load(), test.maxAttempts)
+		})
+	}
+}
+
+// fixedRetryPolicy retries every failed attempt after a constant delay
+type fixedRetryPolicy struct {
+	retries int
+	delay   time.Duration
+}
+
+func (p fixedRetryPolicy) NextRetry(req *http.Request, resp *http.Response, err error, attempt int, elapsed time.Duration) (time.Duration, bool) {
+	return p.delay, attempt <= p.retries
+}
+
 func Test_V1_E2E_PortalHTTPDBTestSuite(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping end to end test")
@@ -95,13 +174,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		}{
 			{
 				name:         "Should return status 200 and correct body on port 1",
-				url:          fmt.Sprintf("http://localhost:%s/healthz", phdPortOne),
+				url:          phdBaseURL(phdBaseURLOneEnv, phdPortOne) + "/healthz",
 				expectedBody: "DB Check Done. Portal HTTP DB is up and running!\nImage Tag: development",
 				expectedCode: http.StatusOK,
 			},
 			{
 				name:         "Should return status 200 and correct body on port 2",
-				url:          fmt.Sprintf("http://localhost:%s/healthz", phdPortTwo),
+				url:          phdBaseURL(phdBaseURLTwoEnv, phdPortTwo) + "/healthz",
 				expectedBody: "DB Check Done. Portal HTTP DB is up and running!\nImage Tag: development",
 				expectedCode: http.StatusOK,
 			},
@@ -148,14 +227,14 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:    "Should return error if chain does not exist",
 				chainID: "9999",
-				err:     fmt.Errorf("Response not OK. 404 Not Found: error in getChainByID: chain not found"),
+				err:     &APIError{StatusCode: http.StatusNotFound, Message: "error in getChainByID: chain not found"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				chain, err := ts.client1.GetChainByID(context.Background(), test.chainID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					test.expectedChain.GigastakeApps = make(map[types.GigastakeAppID]*types.GigastakeApp)
@@ -163,7 +242,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					ts.Equal(test.expectedChain, chain)
 
 					chain, err = ts.client2.GetChainByID(context.Background(), test.chainID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					test.expectedChain.GigastakeApps = make(map[types.GigastakeAppID]*types.GigastakeApp)
 					test.expectedChain.GigastakeApps[test.gigastakeApp.ID] = test.gigastakeApp
 					ts.Equal(test.expectedChain, chain)
@@ -192,19 +271,19 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:           "Should return error if GigastakeApp does not exist",
 				gigastakeAppID: "9999",
-				err:            fmt.Errorf("Response not OK. 404 Not Found: error in getGigastakeAppByID: gigastake app not found"),
+				err:            &APIError{StatusCode: http.StatusNotFound, Message: "error in getGigastakeAppByID: gigastake app not found"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				app, err := ts.client1.GetGigastakeAppByID(context.Background(), test.gigastakeAppID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedApp, app)
 					app, err = ts.client2.GetGigastakeAppByID(context.Background(), test.gigastakeAppID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApp, app)
 				}
 			})
@@ -256,7 +335,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 				} else {
 					chains, err = ts.client1.GetAllChains(context.Background())
 				}
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					ts.Equal(test.expectedChains, chainsToMap(chains))
@@ -266,7 +345,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					} else {
 						chains, err = ts.client2.GetAllChains(context.Background())
 					}
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedChains, chainsToMap(chains))
 				}
 			})
@@ -288,13 +367,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				gigastakeApps, err := ts.client1.GetAllGigastakeApps(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					ts.Equal(test.expectedApps, gigastakeAppsToMap(gigastakeApps))
 
 					gigastakeApps, err = ts.client2.GetAllGigastakeApps(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApps, gigastakeAppsToMap(gigastakeApps))
 				}
 			})
@@ -323,20 +402,20 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:    "Should return error if chain does not exist",
 				chainID: "9999",
-				err:     fmt.Errorf("Response not OK. 400 Bad Request: error in getAllGigastakeAppsByChain: chain not found"),
+				err:     &APIError{StatusCode: http.StatusBadRequest, Message: "error in getAllGigastakeAppsByChain: chain not found"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				apps, err := ts.client1.GetAllGigastakeAppsByChain(context.Background(), test.chainID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedApps, apps)
 
 					apps, err = ts.client2.GetAllGigastakeAppsByChain(context.Background(), test.chainID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApps, apps)
 				}
 			})
@@ -367,14 +446,14 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:        "Should return error if app does not exist",
 				portalAppID: "9999",
-				err:         fmt.Errorf("Response not OK. 404 Not Found: error in getPortalAppByID: portal app not found"),
+				err:         &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalAppByID: portal app not found"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				portalApp, err := ts.client1.GetPortalAppByID(context.Background(), test.portalAppID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					test.expectedApp.Users = test.portalAppUsers
@@ -382,7 +461,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					ts.Equal(test.expectedApp, portalApp)
 
 					portalApp, err = ts.client2.GetPortalAppByID(context.Background(), test.portalAppID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApp, portalApp)
 				}
 			})
@@ -406,7 +485,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				portalApps, err := ts.client1.GetAllPortalApps(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					for _, portalApp := range test.expectedApps {
@@ -415,7 +494,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					ts.Equal(test.expectedApps, portalAppsToMap(portalApps))
 
 					portalApps, err = ts.client2.GetAllPortalApps(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApps, portalAppsToMap(portalApps))
 				}
 			})
@@ -542,7 +621,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 				} else {
 					portalApps, err = ts.client1.GetPortalAppsByUser(context.Background(), test.userID)
 				}
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					for _, portalApp := range test.expectedApps {
@@ -555,7 +634,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					} else {
 						portalApps, err = ts.client2.GetPortalAppsByUser(context.Background(), test.userID)
 					}
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApps, portalAppsToMap(portalApps))
 				}
 			})
@@ -577,13 +656,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				portalAppLites, err := ts.client1.GetPortalAppsForMiddleware(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedApps, portalAppLitesToMap(portalAppLites))
 
 					portalAppLites, err = ts.client2.GetPortalAppsForMiddleware(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedApps, portalAppLitesToMap(portalAppLites))
 				}
 			})
@@ -607,13 +686,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				accounts, err := ts.client1.GetAllAccounts(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Len(accounts, test.expectedAccNum)
 
 					accounts, err = ts.client2.GetAllAccounts(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Len(accounts, test.expectedAccNum)
 				}
 			})
@@ -776,7 +855,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 				plans:          map[types.AccountID]*types.Plan{},
 				portalApps:     map[types.AccountID]map[types.PortalAppID]*types.PortalApp{},
 				portalAppUsers: testdata.PortalAppUsers,
-				err:            fmt.Errorf("Response not OK. 404 Not Found: error in getUserAccounts: no accounts were found for user ID"),
+				err:            &APIError{StatusCode: http.StatusNotFound, Message: "error in getUserAccounts: no accounts were found for user ID"},
 			},
 			{
 				name:   "Should fail to get accounts for user_2 where user_2 has not signed up yet (should not return invited accounts)",
@@ -788,14 +867,14 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 				plans:          map[types.AccountID]*types.Plan{},
 				portalApps:     nil,
 				portalAppUsers: testdata.PortalAppUsers,
-				err:            fmt.Errorf("Response not OK. 404 Not Found: error in getUserAccounts: no accounts were found for user ID"),
+				err:            &APIError{StatusCode: http.StatusNotFound, Message: "error in getUserAccounts: no accounts were found for user ID"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				accounts, err := ts.client1.GetUserAccounts(context.Background(), test.userID, test.options)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					accountMap := convertAccountsToMap(accounts)
@@ -810,7 +889,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 					ts.Equal(test.expectedAccs, accountMap)
 
 					accounts, err = ts.client2.GetUserAccounts(context.Background(), test.userID, test.options)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					accountMap = convertAccountsToMap(accounts)
 					for id, account := range test.expectedAccs {
 						account.Plan = test.plans[id]
@@ -867,13 +946,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 				test.expectedAcc.PortalApps[test.assignApp.ID] = test.assignApp
 
 				account, err := ts.client1.GetUserAccount(context.Background(), test.accountID, test.userID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedAcc, account)
 
 					account, err = ts.client2.GetUserAccount(context.Background(), test.accountID, test.userID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedAcc, account)
 				}
 			})
@@ -962,7 +1041,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:   "Should error when user does not exist",
 				userID: "facebook|ron_swanson",
-				err:    fmt.Errorf("Response not OK. 404 Not Found: error in getPortalUser: user not found for ID: facebook|ron_swanson"),
+				err:    &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalUser: user not found for ID: facebook|ron_swanson"},
 			},
 			{
 				name:   "Should error when no user ID",
@@ -974,13 +1053,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				portalUser, err := ts.client1.GetPortalUser(context.Background(), test.userID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedPortalUser, portalUser)
 
 					portalUser, err = ts.client2.GetPortalUser(context.Background(), test.userID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedPortalUser, portalUser)
 				}
 			})
@@ -1037,7 +1116,7 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:   "Should error when user does not exist",
 				userID: "facebook|ron_swanson",
-				err:    fmt.Errorf("Response not OK. 404 Not Found: error in getPortalUser: user not found for ID: facebook|ron_swanson"),
+				err:    &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalUser: user not found for ID: facebook|ron_swanson"},
 			},
 			{
 				name:   "Should error when no user ID",
@@ -1049,13 +1128,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				portalUserID, err := ts.client1.GetPortalUserID(context.Background(), test.userID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedPortalUserID, portalUserID)
 
 					portalUserID, err = ts.client2.GetPortalUserID(context.Background(), test.userID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedPortalUserID, portalUserID)
 				}
 			})
@@ -1080,13 +1159,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				plans, err := ts.client1.GetAllPlans(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(derefPlansMap(test.expected), convertPlansToMap(plans))
 
 					plans, err = ts.client2.GetAllPlans(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(derefPlansMap(test.expected), convertPlansToMap(plans))
 				}
 			})
@@ -1110,13 +1189,13 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				blockedContracts, err := ts.client1.GetBlockedContracts(context.Background())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					ts.Equal(test.expectedBlockedCon, blockedContracts)
 
 					blockedContracts, err = ts.client2.GetBlockedContracts(context.Background())
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Equal(test.expectedBlockedCon, blockedContracts)
 				}
 			})
@@ -1150,14 +1229,14 @@ func (ts *phdE2EReadTestSuite) Test_ReadTests() {
 			{
 				name:           "Should fail if passed an invalid user ID",
 				providerUserID: "auth0|george_carlin",
-				err:            fmt.Errorf("Response not OK. 404 Not Found: error in getPortalUser: user not found for ID: auth0|george_carlin"),
+				err:            &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalUser: user not found for ID: auth0|george_carlin"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				userID, err := ts.client1.GetPortalUserID(context.Background(), test.providerUserID)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 				if test.err == nil {
 					ts.Equal(test.expectedUserID, userID)
 				}
@@ -1186,14 +1265,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 			{
 				name:          "Should fail if Chain is missing",
 				newChainInput: types.NewChainInput{},
-				err:           fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewChainAndGigastakeApps: error chain cannot be nil"),
+				err:           &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewChainAndGigastakeApps: error chain cannot be nil"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				createdChainResp, err := ts.client1.CreateChainAndGigastakeApps(context.Background(), test.newChainInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1254,7 +1333,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				gigastakeAppInput: types.GigastakeApp{
 					Name: "",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewGigastakeApp: gigastake app name cannot be empty"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewGigastakeApp: gigastake app name cannot be empty"},
 			},
 			{
 				name: "Should return an error for non-existent chain ID",
@@ -1262,14 +1341,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Name:     "whatever",
 					ChainIDs: map[types.RelayChainID]struct{}{"0666": {}},
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewGigastakeApp: error chain does not exist for chain ID '0666'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewGigastakeApp: error chain does not exist for chain ID '0666'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				createdGigastakeApp, err := ts.client1.CreateGigastakeApp(context.Background(), test.gigastakeAppInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1332,7 +1411,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				ts.NoError(err)
 
 				chainUpdateResponse, err := ts.client1.UpdateChain(context.Background(), test.chainUpdate)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1497,7 +1576,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				gigastakeAppUpdate: types.UpdateGigastakeApp{
 					Name: "",
 				},
-				err:      fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateGigastakeApp: gigastake app name cannot be empty"),
+				err:      &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateGigastakeApp: gigastake app name cannot be empty"},
 				expected: nil,
 			},
 			{
@@ -1507,7 +1586,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Name:     "whatever",
 					ChainIDs: []types.RelayChainID{},
 				},
-				err:      fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateGigastakeApp: chainIDs cannot be empty for gigastake app update"),
+				err:      &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateGigastakeApp: chainIDs cannot be empty for gigastake app update"},
 				expected: nil,
 			},
 			{
@@ -1517,7 +1596,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Name:     "whatever",
 					ChainIDs: []types.RelayChainID{"0666"},
 				},
-				err:      fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateGigastakeApp: error chain does not exist for chain ID '0666'"),
+				err:      &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateGigastakeApp: error chain does not exist for chain ID '0666'"},
 				expected: nil,
 			},
 		}
@@ -1525,7 +1604,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				updatedGigastakeApp, err := ts.client1.UpdateGigastakeApp(context.Background(), test.gigastakeAppID, test.gigastakeAppUpdate)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1572,7 +1651,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				chainActive, err := ts.client1.ActivateChain(context.Background(), test.chainID, test.active)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1652,7 +1731,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 						Environment: "production",
 					},
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewPortalApp: portal app name cannot be empty"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewPortalApp: portal app name cannot be empty"},
 			},
 			{
 				name: "Should return an error if invalid environment provided",
@@ -1667,7 +1746,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 						Environment: "cascadia",
 					},
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewPortalApp: invalid portal app environment provided: cascadia"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewPortalApp: invalid portal app environment provided: cascadia"},
 			},
 			{
 				name: "Should return an error for non-existent account ID",
@@ -1682,7 +1761,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 						Environment: "production",
 					},
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewPortalApp: error account does not exist for account ID 'non_existing_account_id'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewPortalApp: error account does not exist for account ID 'non_existing_account_id'"},
 			},
 			{
 				name: "Should return an error for non-existent plan",
@@ -1697,7 +1776,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 						Environment: "production",
 					},
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createNewPortalApp: error pay plan 'non_existing_plan' does not exist"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createNewPortalApp: error pay plan 'non_existing_plan' does not exist"},
 			},
 		}
 
@@ -1707,7 +1786,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					test.aatInput.ID: test.aatInput,
 				}
 				createdPortalApp, err := ts.client1.CreatePortalApp(context.Background(), *test.portalAppInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -1950,7 +2029,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				updatePortalApp: types.UpdatePortalApp{
 					PlanType: types.PayPlanType("what_am_i_doing"),
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in updatePortalApp: error pay plan 'what_am_i_doing' does not exist"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updatePortalApp: error pay plan 'what_am_i_doing' does not exist"},
 			},
 		}
 
@@ -2003,7 +2082,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				updateApp := test.updatePortalApp
 				updateApp.AppID = createdPortalApp.ID
 				_, err = ts.client1.UpdatePortalApp(context.Background(), updateApp)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2029,7 +2108,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 			{
 				name:     "Should delete the Portal App in the DB",
 				expected: map[string]string{"status": "deleted"},
-				err:      fmt.Errorf("Response not OK. 404 Not Found: error in getPortalAppByID: portal app not found"),
+				err:      &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalAppByID: portal app not found"},
 			},
 		}
 
@@ -2061,11 +2140,11 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 
 					// Ensure the Portal App is deleted for both clients
 					portalApp, err := ts.client1.GetPortalAppByID(context.Background(), createdPortalApp.ID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Nil(portalApp)
 
 					portalApp, err = ts.client2.GetPortalAppByID(context.Background(), createdPortalApp.ID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Nil(portalApp)
 				}
 			})
@@ -2093,14 +2172,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					PortalAppIDs:       []types.PortalAppID{},
 					FirstDateSurpassed: testdata.MockTimestamp,
 				},
-				err: fmt.Errorf("Response not OK. 400 Bad Request"),
+				err: &APIError{StatusCode: http.StatusBadRequest},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				result, err := ts.client1.UpdatePortalAppsFirstDateSurpassed(context.Background(), test.firstDateSurpassedUpdate)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					ts.Equal(test.expected, result)
@@ -2193,20 +2272,20 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				name:         "Should fail if input Account has an invalid plan type",
 				ownerID:      "user_1",
 				accountInput: &types.Account{PlanType: types.PayPlanType("turbo_ultra_mega_plan")},
-				err:          fmt.Errorf("Response not OK. 500 Internal Server Error: error in createAccount: error pay plan 'turbo_ultra_mega_plan' does not exist"),
+				err:          &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createAccount: error pay plan 'turbo_ultra_mega_plan' does not exist"},
 			},
 			{
 				name:         "Should fail if input User does not exist in the db",
 				ownerID:      "user_451",
 				accountInput: testdata.Accounts[types.AccountID("account_5")],
-				err:          fmt.Errorf("Response not OK. 500 Internal Server Error: error in createAccount: error user does not exist for portal ID 'user_451'"),
+				err:          &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createAccount: error user does not exist for portal ID 'user_451'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				createdAccount, err := ts.client1.CreateAccount(context.Background(), test.ownerID, *test.accountInput, time.Now())
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2268,7 +2347,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					PlanType:  types.Enterprise,
 				},
 				userID: "user_1",
-				err:    fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccount: error account does not exist for account ID 'account_8823'"),
+				err:    &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccount: error account does not exist for account ID 'account_8823'"},
 			},
 		}
 
@@ -2281,7 +2360,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				}
 
 				updatedAccount, err := ts.client1.UpdateAccount(context.Background(), test.update)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2322,7 +2401,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				createdAccountIntegration, err := ts.client1.CreateAccountIntegration(context.Background(), test.accountIntegrationInput.AccountID, *test.accountIntegrationInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2372,7 +2451,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				updatedAccountIntegration, err := ts.client1.UpdateAccountIntegration(context.Background(), test.accountIntegrationInput.AccountID, test.accountIntegrationInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2404,7 +2483,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				name:     "Should delete the Account in the DB",
 				ownerID:  "user_7",
 				expected: map[string]string{"status": "deleted"},
-				err:      fmt.Errorf("Response not OK. 404 Not Found: error in getUserAccount: account not found"),
+				err:      &APIError{StatusCode: http.StatusNotFound, Message: "error in getUserAccount: account not found"},
 			},
 		}
 
@@ -2441,11 +2520,11 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 
 					// Ensure the Account is deleted for both clients
 					account, err := ts.client1.GetUserAccount(context.Background(), createdAccount.ID, test.ownerID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Nil(account)
 
 					account, err = ts.client2.GetUserAccount(context.Background(), createdAccount.ID, test.ownerID)
-					ts.Equal(test.err, err)
+					ts.Equal(test.err, comparableErr(err))
 					ts.Nil(account)
 				}
 			})
@@ -2507,7 +2586,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:       "winston.smith",
 					RoleName:    types.RoleAdmin,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeAccountUser: error email input is not a valid email address 'winston.smith'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeAccountUser: error email input is not a valid email address 'winston.smith'"},
 			},
 			{
 				name: "Should fail if account does not exist",
@@ -2517,7 +2596,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:       "winston.smith@test.com",
 					RoleName:    types.RoleAdmin,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeAccountUser: error account does not exist for account ID 'account_674'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeAccountUser: error account does not exist for account ID 'account_674'"},
 			},
 			{
 				name: "Should fail if an empty email string is provided",
@@ -2557,7 +2636,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:       "valid.email@test.com",
 					RoleName:    types.RoleAdmin,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeAccountUser: error account does not exist for account ID 'non_existent_account'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeAccountUser: error account does not exist for account ID 'non_existent_account'"},
 			},
 			{
 				name: "Should fail if the PortalAppID provided does not exist",
@@ -2567,14 +2646,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:       "valid.email@test.com",
 					RoleName:    types.RoleAdmin,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeAccountUser: error portal app does not exist for ID 'non_existent_app'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeAccountUser: error portal app does not exist for ID 'non_existent_app'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				userIDResp, err := ts.client1.WriteAccountUser(context.Background(), test.createAccountUserInput, testdata.MockTimestamp)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2748,7 +2827,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					RoleName:    types.RoleOwner,
 				},
 				testCreatedTime: testdata.MockTimestamp,
-				err:             fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccountUserRole: error cannot transfer ownership to user ID 'user_10' for account ID 'account_3' because the user has not accepted their invite"),
+				err:             &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccountUserRole: error cannot transfer ownership to user ID 'user_10' for account ID 'account_3' because the user has not accepted their invite"},
 			},
 			{
 				name: "Should fail if User is not a member of an Account",
@@ -2759,7 +2838,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					RoleName:    types.RoleMember,
 				},
 				testCreatedTime: testdata.MockTimestamp,
-				err:             fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccountUserRole: error user ID 'user_512' does not exist for portal app ID 'account_2'"),
+				err:             &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccountUserRole: error user ID 'user_512' does not exist for portal app ID 'account_2'"},
 			},
 			{
 				name: "Should fail if RoleName is empty",
@@ -2781,7 +2860,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					RoleName:    "INVALID_ROLE_NAME",
 				},
 				testCreatedTime: testdata.MockTimestamp,
-				err:             fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccountUserRole: error invalid role name set"),
+				err:             &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccountUserRole: error invalid role name set"},
 			},
 			{
 				name: "Should fail if PortalAppID is empty",
@@ -2814,7 +2893,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					RoleName:    types.RoleAdmin,
 				},
 				testCreatedTime: testdata.MockTimestamp,
-				err:             fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccountUserRole: error portal app does not exist for ID 'non_existent_app'"),
+				err:             &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccountUserRole: error portal app does not exist for ID 'non_existent_app'"},
 			},
 			{
 				name: "Should fail if User is not a member of an Account",
@@ -2825,14 +2904,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					RoleName:    types.RoleMember,
 				},
 				testCreatedTime: testdata.MockTimestamp,
-				err:             fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateAccountUserRole: error user ID 'non_member_user' does not exist for portal app ID 'account_3'"),
+				err:             &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateAccountUserRole: error user ID 'non_member_user' does not exist for portal app ID 'account_3'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.SetAccountUserRole(context.Background(), test.updateAccountUserRole, test.testCreatedTime)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2899,7 +2978,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					AuthProviderType: types.AuthType("ask_jeeves"),
 					ProviderUserID:   "auth0|daenerys_targaryen",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in acceptAccountUser: error invalid auth provider type 'ask_jeeves'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in acceptAccountUser: error invalid auth provider type 'ask_jeeves'"},
 			},
 			{
 				name: "Should fail if AuthProviderType is not provided",
@@ -2927,14 +3006,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					AuthProviderType: types.AuthTypeAuth0Username,
 					ProviderUserID:   "auth0|who_dis",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in acceptAccountUser: error user ID 'user_123' does not exist for portal app ID 'account_3'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in acceptAccountUser: error user ID 'user_123' does not exist for portal app ID 'account_3'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.UpdateAcceptAccountUser(context.Background(), test.acceptAccountUserInput, testdata.MockTimestamp)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -2996,7 +3075,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					PortalAppID: "test_app_3",
 					UserID:      "user_789",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in removeAccountUser: error user ID 'user_789' does not exist for portal app ID 'test_app_3'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in removeAccountUser: error user ID 'user_789' does not exist for portal app ID 'test_app_3'"},
 			},
 			{
 				name: "Should fail if attempting to delete the current Account OWNER",
@@ -3011,7 +3090,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					PortalAppID: "test_app_1",
 					UserID:      "user_1",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in removeAccountUser: error cannot delete user ID 'user_1' for account ID 'account_1' because this user is the current account owner"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in removeAccountUser: error cannot delete user ID 'user_1' for account ID 'account_1' because this user is the current account owner"},
 			},
 			{
 				name: "Should fail if provided a UserID that doesn't exist for the Account",
@@ -3026,7 +3105,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					PortalAppID: "test_app_1",
 					UserID:      "user_nonexistent",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in removeAccountUser: error user ID 'user_nonexistent' does not exist for portal app ID 'test_app_1'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in removeAccountUser: error user ID 'user_nonexistent' does not exist for portal app ID 'test_app_1'"},
 			},
 		}
 
@@ -3050,7 +3129,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				}
 
 				_, err := ts.client1.RemoveAccountUser(context.Background(), test.updateRemoveAccountUser)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3112,7 +3191,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:          "jiminy.cricket",
 					ProviderUserID: "auth0|test",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createUser: error email input is not a valid email address 'jiminy.cricket'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createUser: error email input is not a valid email address 'jiminy.cricket'"},
 			},
 			{
 				name: "Should fail if there's no provider type",
@@ -3120,14 +3199,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					Email:          "email@test.com",
 					ProviderUserID: "wtf|test",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in createUser: error invalid auth provider type 'wtf'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in createUser: error invalid auth provider type 'wtf'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				createdUser, err := ts.client1.CreateUser(context.Background(), test.userInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3237,14 +3316,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				userInput: types.UpdateUser{
 					ID: "invalid_id",
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateUser: error user does not exist for portal ID 'invalid_id'"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateUser: error user does not exist for portal ID 'invalid_id'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				updatedUser, err := ts.client1.UpdateUser(context.Background(), test.userInput)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3283,20 +3362,20 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 				name:           "Should fail to delete a User if they are on the team of any accounts",
 				userID:         "user_1",
 				providerUserID: "auth0|james_holden",
-				expectedErr:    fmt.Errorf("Response not OK. 500 Internal Server Error: error in deleteUser: error cannot delete user because they are still on an account team"),
+				expectedErr:    &APIError{StatusCode: http.StatusInternalServerError, Message: "error in deleteUser: error cannot delete user because they are still on an account team"},
 			},
 			{
 				name:           "Should fail if the user does not exist in the database",
 				userID:         "user_42",
 				providerUserID: "auth0|gengelspiel",
-				expectedErr:    fmt.Errorf("Response not OK. 500 Internal Server Error: error in deleteUser: error user does not exist for portal ID 'user_42'"),
+				expectedErr:    &APIError{StatusCode: http.StatusInternalServerError, Message: "error in deleteUser: error user does not exist for portal ID 'user_42'"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.DeleteUser(context.Background(), test.userID)
-				ts.Equal(test.expectedErr, err)
+				ts.Equal(test.expectedErr, comparableErr(err))
 
 				if test.expectedErr == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3354,7 +3433,7 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					BlockedAddress: "",
 					Active:         true,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeBlockedContract: error blockchain address must be provided"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeBlockedContract: error blockchain address must be provided"},
 			},
 			{
 				name: "Should return an error if the address is a duplicate",
@@ -3362,14 +3441,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 					BlockedAddress: "0xtest_cdef0123456789abcdef0123456789abcdef",
 					Active:         true,
 				},
-				err: fmt.Errorf("Response not OK. 500 Internal Server Error: error in writeBlockedContract: error blockchain address 0xtest_cdef0123456789abcdef0123456789abcdef is already blocked"),
+				err: &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeBlockedContract: error blockchain address 0xtest_cdef0123456789abcdef0123456789abcdef is already blocked"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.WriteBlockedContract(context.Background(), test.blockedContract)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3433,14 +3512,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 			{
 				name:           "Should return an error if the address doesn't exist in the database",
 				blockedAddress: "0xtest_34095u439fh49fh30fj239ru923kf3f09823fk",
-				err:            fmt.Errorf("Response not OK. 500 Internal Server Error: error in updateBlockedContractActive: error blockchain address 0xtest_34095u439fh49fh30fj239ru923kf3f09823fk does not exist"),
+				err:            &APIError{StatusCode: http.StatusInternalServerError, Message: "error in updateBlockedContractActive: error blockchain address 0xtest_34095u439fh49fh30fj239ru923kf3f09823fk does not exist"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.UpdateBlockedContractActive(context.Background(), test.blockedAddress, test.active)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 				if test.err == nil {
 					<-time.After(50 * time.Millisecond)
 
@@ -3485,14 +3564,14 @@ func (ts *phdE2EWriteTestSuite) Test_WriteTests() {
 			{
 				name:           "Should return an error if the address doesn't exist in the database",
 				blockedAddress: "0xtest_34095u439fh49fh30fj239ru923kf3f09823fk",
-				err:            fmt.Errorf("Response not OK. 500 Internal Server Error: error in removeBlockedContract: error blockchain address 0xtest_34095u439fh49fh30fj239ru923kf3f09823fk does not exist"),
+				err:            &APIError{StatusCode: http.StatusInternalServerError, Message: "error in removeBlockedContract: error blockchain address 0xtest_34095u439fh49fh30fj239ru923kf3f09823fk does not exist"},
 			},
 		}
 
 		for _, test := range tests {
 			ts.Run(test.name, func() {
 				_, err := ts.client1.RemoveBlockedContract(context.Background(), test.blockedAddress)
-				ts.Equal(test.err, err)
+				ts.Equal(test.err, comparableErr(err))
 
 				if err == nil {
 					<-time.After(50 * time.Millisecond)
@@ -3590,6 +3669,16 @@ func filterActiveChains(chains map[types.RelayChainID]*types.Chain) map[types.Re
 	return activeChains
 }
 
+// comparableErr strips the request details from an APIError so that E2E tests
+// can compare only the status code and server message of the expected error
+func comparableErr(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return &APIError{StatusCode: apiErr.StatusCode, Message: apiErr.Message}
+	}
+	return err
+}
+
 /* ---------- Test Suite Util Interfaces ---------- */
 
 type phdE2EReadTestSuite struct {
@@ -3605,8 +3694,20 @@ type phdE2EWriteTestSuite struct {
 const (
 	phdPortOne = "8080"
 	phdPortTwo = "8081"
+
+	// phdBaseURLOneEnv and phdBaseURLTwoEnv override the E2E PHD instances, eg. with the phdtest servers started by TestMain
+	phdBaseURLOneEnv = "PHD_BASE_URL_1"
+	phdBaseURLTwoEnv = "PHD_BASE_URL_2"
 )
 
+// phdBaseURL returns the base URL of an E2E PHD instance, defaulting to the Docker test environment port
+func phdBaseURL(env, port string) string {
+	if baseURL := os.Getenv(env); baseURL != "" {
+		return baseURL
+	}
+	return fmt.Sprintf("http://localhost:%s", port)
+}
+
 func initDBClient(ts DBClientInitializer) error {
 	baseConfig := Config{
 		APIKey:  "test_api_key_6789",
@@ -3615,7 +3716,7 @@ func initDBClient(ts DBClientInitializer) error {
 	}
 
 	config1 := baseConfig
-	config1.BaseURL = "http://localhost:8080"
+	config1.BaseURL = phdBaseURL(phdBaseURLOneEnv, phdPortOne)
 	client1, err := NewDBClient(config1)
 	if err != nil {
 		return err
@@ -3623,7 +3724,7 @@ func initDBClient(ts DBClientInitializer) error {
 	ts.SetClient1(client1)
 
 	config2 := baseConfig
-	config2.BaseURL = "http://localhost:8081"
+	config2.BaseURL = phdBaseURL(phdBaseURLTwoEnv, phdPortTwo)
 	client2, err := NewDBClient(config2)
 	if err != nil {
 		return err
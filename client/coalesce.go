@@ -0,0 +1,112 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type (
+	// coalescingTransport is a RoundTripper sharing one upstream request between concurrent identical GETs.
+	// Every caller receives its own copy of the response, so decoding it yields independent values.
+	coalescingTransport struct {
+		underlying http.RoundTripper
+
+		mu    sync.Mutex
+		calls map[string]*coalescedCall
+	}
+
+	// coalescedCall is an upstream request shared by one or more waiting callers
+	coalescedCall struct {
+		done    chan struct{}
+		cancel  context.CancelFunc
+		waiters int
+
+		resp *http.Response
+		body []byte
+		err  error
+	}
+)
+
+func newCoalescingTransport(underlying http.RoundTripper) *coalescingTransport {
+	return &coalescingTransport{underlying: underlying, calls: make(map[string]*coalescedCall)}
+}
+
+func (t *coalescingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.underlying.RoundTrip(req)
+	}
+
+	// Requests revalidating different stored responses can't share a response
+	key := fmt.Sprintf("%s\n%s\n%s", req.URL, req.Header.Get("If-None-Match"), req.Header.Get("If-Modified-Since"))
+	t.mu.Lock()
+	call, ok := t.calls[key]
+	if !ok {
+		// The shared request outlives the caller starting it and is only cancelled once every caller has gone
+		ctx, cancel := context.WithCancel(context.WithoutCancel(req.Context()))
+		call = &coalescedCall{done: make(chan struct{}), cancel: cancel}
+		t.calls[key] = call
+		go t.do(key, call, req.Clone(ctx))
+	}
+	call.waiters++
+	t.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return bufferedResponse(call.resp, call.body, req), nil
+	case <-req.Context().Done():
+		t.leave(key, call)
+		return nil, req.Context().Err()
+	}
+}
+
+// do sends the shared request and buffers its response for the waiting callers
+func (t *coalescingTransport) do(key string, call *coalescedCall, req *http.Request) {
+	defer call.cancel()
+
+	resp, err := t.underlying.RoundTrip(req)
+	if err == nil {
+		call.body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	call.resp, call.err = resp, err
+
+	t.mu.Lock()
+	if t.calls[key] == call {
+		delete(t.calls, key)
+	}
+	t.mu.Unlock()
+
+	close(call.done)
+}
+
+// leave removes a caller that stopped waiting, cancelling the shared request once no caller is left
+func (t *coalescingTransport) leave(key string, call *coalescedCall) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+
+	call.cancel()
+	if t.calls[key] == call {
+		delete(t.calls, key)
+	}
+}
+
+// bufferedResponse returns a copy of a response whose body was read into memory, with its own body
+func bufferedResponse(resp *http.Response, body []byte, req *http.Request) *http.Response {
+	copied := *resp
+	copied.Header = resp.Header.Clone()
+	copied.Body = io.NopCloser(bytes.NewReader(body))
+	copied.Request = req
+	return &copied
+}
@@ -0,0 +1,194 @@
+package dbclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCoalescingTestClient returns a client coalescing reads against a server that holds every request
+// until release is closed
+func newCoalescingTestClient(t *testing.T, requests *atomic.Int32, cancelled chan<- struct{}) (*DBClient, chan struct{}) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			if cancelled != nil {
+				cancelled <- struct{}{}
+			}
+			return
+		}
+		if r.URL.Path == "/v2/portal_app" {
+			_, _ = w.Write([]byte(`[{"id": "test_app_1", "name": "pokt_app_1"}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "test_app_1", "name": "pokt_app_1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := newDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", Timeout: 5 * time.Second, CoalesceReads: true})
+	assert.NoError(t, err)
+
+	return client, release
+}
+
+// waitForWaiters blocks until the given number of callers wait on a shared request
+func waitForWaiters(t *testing.T, client *DBClient, waiters int) {
+	coalescer := client.httpClient.Transport.(*coalescingTransport)
+	assert.Eventually(t, func() bool {
+		coalescer.mu.Lock()
+		defer coalescer.mu.Unlock()
+
+		total := 0
+		for _, call := range coalescer.calls {
+			total += call.waiters
+		}
+		return total == waiters
+	}, time.Second, time.Millisecond)
+}
+
+func Test_CoalesceReads(t *testing.T) {
+	const callers = 10
+
+	var requests atomic.Int32
+	client, release := newCoalescingTestClient(t, &requests, nil)
+
+	var wg sync.WaitGroup
+	portalApps := make([]*types.PortalApp, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			portalApp, err := client.GetPortalAppByID(context.Background(), "test_app_1")
+			assert.NoError(t, err)
+			portalApps[i] = portalApp
+		}(i)
+	}
+
+	waitForWaiters(t, client, callers)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+	portalApps[0].Name = "modified"
+	for _, portalApp := range portalApps[1:] {
+		assert.Equal(t, "pokt_app_1", portalApp.Name, "every caller decodes its own copy")
+	}
+}
+
+func Test_CoalesceReads_Cancellation(t *testing.T) {
+	tests := []struct {
+		name      string
+		cancelAll bool
+	}{
+		{
+			name: "Should keep the shared request going for the remaining callers",
+		},
+		{
+			name:      "Should cancel the shared request once every caller has gone",
+			cancelAll: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests atomic.Int32
+			cancelled := make(chan struct{}, 1)
+			client, release := newCoalescingTestClient(t, &requests, cancelled)
+			defer close(release)
+
+			cancelledCtx, cancel := context.WithCancel(context.Background())
+			cancelledErr := make(chan error)
+			go func() {
+				_, err := client.GetPortalAppByID(cancelledCtx, "test_app_1")
+				cancelledErr <- err
+			}()
+			waitForWaiters(t, client, 1)
+
+			if test.cancelAll {
+				cancel()
+				assert.ErrorIs(t, <-cancelledErr, context.Canceled)
+				<-cancelled
+				assert.Equal(t, int32(1), requests.Load())
+				return
+			}
+
+			remainingErr := make(chan error)
+			go func() {
+				_, err := client.GetPortalAppByID(context.Background(), "test_app_1")
+				remainingErr <- err
+			}()
+			waitForWaiters(t, client, 2)
+
+			cancel()
+			assert.ErrorIs(t, <-cancelledErr, context.Canceled)
+			release <- struct{}{}
+			assert.NoError(t, <-remainingErr)
+			assert.Equal(t, int32(1), requests.Load(), "the cancelled caller doesn't fail the shared request")
+		})
+	}
+}
+
+func Test_CoalesceReads_DistinctRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(client *DBClient) error
+	}{
+		{
+			name: "Should not share requests for different endpoints",
+			call: func(client *DBClient) error {
+				_, err := client.GetPortalAppByID(context.Background(), "test_app_2")
+				return err
+			},
+		},
+		{
+			name: "Should not share requests for different queries",
+			call: func(client *DBClient) error {
+				portalApps, err := client.GetAllPortalApps(context.Background(), PortalAppOptions{IncludeDeleted: BoolPtr(true)})
+				if err == nil && len(portalApps) != 1 {
+					return fmt.Errorf("expected 1 portal app, got %d", len(portalApps))
+				}
+				return err
+			},
+		},
+		{
+			name: "Should not share writes",
+			call: func(client *DBClient) error {
+				_, err := client.WriteBlockedContract(context.Background(), types.BlockedContract{BlockedAddress: "0xtest_blocked"})
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests atomic.Int32
+			client, release := newCoalescingTestClient(t, &requests, nil)
+
+			sharedErr := make(chan error, 1)
+			go func() {
+				_, err := client.GetAllPortalApps(context.Background())
+				sharedErr <- err
+			}()
+			waitForWaiters(t, client, 1)
+
+			callErr := make(chan error, 1)
+			go func() { callErr <- test.call(client) }()
+			assert.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+
+			close(release)
+			assert.NoError(t, <-sharedErr)
+			assert.NoError(t, <-callErr)
+		})
+	}
+}
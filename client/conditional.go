@@ -0,0 +1,190 @@
+package dbclient
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultConditionalGetEntries = 1_000
+
+type (
+	// Freshness reports whether the value returned by a read was served from a stored copy, see WithFreshness
+	Freshness struct {
+		// NotModified is set if PHD answered 304 Not Modified to a request sent with Config.ConditionalGets
+		// and the stored response was returned
+		NotModified bool
+		// ETag and LastModified are the validators of the returned response, empty if PHD sent none
+		ETag, LastModified string
+		// Stale is set if PHD couldn't be reached and a DiskCachedReader returned the collection stored at StoredAt
+		Stale    bool
+		StoredAt time.Time
+	}
+
+	// freshnessContextKey is the context key holding the *Freshness to fill in
+	freshnessContextKey struct{}
+
+	// conditionalTransport is a RoundTripper storing GET responses carrying an ETag or Last-Modified header
+	// and revalidating them with If-None-Match and If-Modified-Since. A 304 response is replaced by the
+	// stored one, so callers decode it as usual.
+	conditionalTransport struct {
+		underlying http.RoundTripper
+		maxEntries int
+
+		mu      sync.Mutex
+		entries map[string]*list.Element
+		lru     *list.List
+	}
+
+	validatedResponse struct {
+		key                string
+		etag, lastModified string
+		statusCode         int
+		header             http.Header
+		body               []byte
+	}
+)
+
+// WithFreshness returns a context whose reads fill in the given Freshness, eg. to skip reprocessing a payload
+// that didn't change since the last poll
+func WithFreshness(ctx context.Context, freshness *Freshness) context.Context {
+	return context.WithValue(ctx, freshnessContextKey{}, freshness)
+}
+
+func newConditionalTransport(config Config, underlying http.RoundTripper) *conditionalTransport {
+	maxEntries := config.ConditionalGetEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultConditionalGetEntries
+	}
+
+	return &conditionalTransport{
+		underlying: underlying,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.underlying.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	stored := t.get(key)
+	if stored != nil {
+		req = req.Clone(req.Context())
+		if stored.etag != "" {
+			req.Header.Set("If-None-Match", stored.etag)
+		}
+		if stored.lastModified != "" {
+			req.Header.Set("If-Modified-Since", stored.lastModified)
+		}
+	}
+
+	resp, err := t.underlying.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	freshness, _ := req.Context().Value(freshnessContextKey{}).(*Freshness)
+	if freshness == nil {
+		freshness = &Freshness{}
+	}
+	*freshness = Freshness{}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && stored != nil:
+		drainBody(resp)
+		*freshness = Freshness{NotModified: true, ETag: stored.etag, LastModified: stored.lastModified}
+		return stored.response(req), nil
+
+	case resp.StatusCode == http.StatusOK:
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		*freshness = Freshness{ETag: etag, LastModified: lastModified}
+
+		// Responses without validators can't be revalidated and are passed through unbuffered
+		if etag == "" && lastModified == "" {
+			t.remove(key)
+			return resp, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		t.set(&validatedResponse{
+			key:          key,
+			etag:         etag,
+			lastModified: lastModified,
+			statusCode:   resp.StatusCode,
+			header:       resp.Header.Clone(),
+			body:         body,
+		})
+	}
+
+	return resp, nil
+}
+
+func (t *conditionalTransport) get(key string) *validatedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elem, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(elem)
+	return elem.Value.(*validatedResponse)
+}
+
+func (t *conditionalTransport) set(entry *validatedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.entries[entry.key]; ok {
+		elem.Value = entry
+		t.lru.MoveToFront(elem)
+		return
+	}
+
+	t.entries[entry.key] = t.lru.PushFront(entry)
+	for t.lru.Len() > t.maxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		delete(t.entries, oldest.Value.(*validatedResponse).key)
+	}
+}
+
+func (t *conditionalTransport) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if elem, ok := t.entries[key]; ok {
+		t.lru.Remove(elem)
+		delete(t.entries, key)
+	}
+}
+
+// response returns the stored response with its own body
+func (r *validatedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.statusCode, http.StatusText(r.statusCode)),
+		StatusCode:    r.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
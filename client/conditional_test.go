@@ -0,0 +1,126 @@
+package dbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConditionalGets(t *testing.T) {
+	const lastModified = "Mon, 01 May 2023 12:00:00 GMT"
+
+	tests := []struct {
+		name              string
+		header            http.Header
+		changed           bool
+		expectedCondition http.Header
+		expectedFreshness Freshness
+		expectedID        types.PortalAppID
+	}{
+		{
+			name:              "Should revalidate with the ETag and return the stored value",
+			header:            http.Header{"Etag": {`"v1"`}},
+			expectedCondition: http.Header{"If-None-Match": {`"v1"`}},
+			expectedFreshness: Freshness{NotModified: true, ETag: `"v1"`},
+			expectedID:        "test_app_1",
+		},
+		{
+			name:              "Should revalidate with Last-Modified and return the stored value",
+			header:            http.Header{"Last-Modified": {lastModified}},
+			expectedCondition: http.Header{"If-Modified-Since": {lastModified}},
+			expectedFreshness: Freshness{NotModified: true, LastModified: lastModified},
+			expectedID:        "test_app_1",
+		},
+		{
+			name:              "Should return the new value once it changed",
+			header:            http.Header{"Etag": {`"v1"`}},
+			changed:           true,
+			expectedCondition: http.Header{"If-None-Match": {`"v1"`}},
+			expectedFreshness: Freshness{ETag: `"v2"`},
+			expectedID:        "test_app_2",
+		},
+		{
+			name:              "Should fetch the full response when the server sends no validators",
+			header:            http.Header{},
+			expectedCondition: http.Header{},
+			expectedFreshness: Freshness{},
+			expectedID:        "test_app_1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := 0
+			condition := http.Header{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					for key, values := range test.header {
+						w.Header()[key] = values
+					}
+					_, _ = w.Write([]byte(`[{"id": "test_app_1"}]`))
+					return
+				}
+
+				for _, key := range []string{"If-None-Match", "If-Modified-Since"} {
+					if value := r.Header.Get(key); value != "" {
+						condition.Set(key, value)
+					}
+				}
+				if test.changed {
+					w.Header().Set("ETag", `"v2"`)
+					_, _ = w.Write([]byte(`[{"id": "test_app_2"}]`))
+					return
+				}
+				if len(condition) > 0 {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				_, _ = w.Write([]byte(`[{"id": "test_app_1"}]`))
+			}))
+			defer server.Close()
+
+			client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", Timeout: 5 * time.Second, ConditionalGets: true})
+			assert.NoError(t, err)
+
+			first, err := client.GetPortalAppsForMiddleware(context.Background())
+			assert.NoError(t, err)
+			first[0].ID = "modified"
+
+			var freshness Freshness
+			second, err := client.GetPortalAppsForMiddleware(WithFreshness(context.Background(), &freshness))
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expectedCondition, condition)
+			assert.Equal(t, test.expectedFreshness, freshness)
+			assert.Len(t, second, 1)
+			assert.Equal(t, test.expectedID, second[0].ID, "callers can't modify the stored value")
+		})
+	}
+}
+
+func Test_ConditionalGets_EvictsLeastRecentlyUsed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client, err := newDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", ConditionalGets: true, ConditionalGetEntries: 2})
+	assert.NoError(t, err)
+
+	for _, portalAppID := range []types.PortalAppID{"test_app_1", "test_app_2", "test_app_1", "test_app_3"} {
+		_, err := client.GetPortalAppByID(context.Background(), portalAppID)
+		assert.NoError(t, err)
+	}
+
+	conditional := client.httpClient.Transport.(*conditionalTransport)
+	assert.Len(t, conditional.entries, 2)
+	assert.NotNil(t, conditional.get(server.URL+"/v2/portal_app/test_app_1"))
+	assert.Nil(t, conditional.get(server.URL+"/v2/portal_app/test_app_2"))
+}
@@ -0,0 +1,468 @@
+package dbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	defaultConsistencyInterval           = time.Minute
+	defaultConsistencyTimestampPrecision = time.Second
+)
+
+type (
+	// ConsistencyConfig configures a ConsistencyChecker
+	ConsistencyConfig struct {
+		// Names labels the readers in reports, in order, and must have one name per reader if set.
+		// Defaults to `reader_0`, `reader_1`, ...
+		Names []string
+		// TimestampPrecision is the precision timestamps are compared at, after converting them to UTC. Defaults to 1 second.
+		TimestampPrecision time.Duration
+		// IgnoreFields are field paths left out of the comparison, eg. `updatedAt` or `settings.secretKey`
+		IgnoreFields []string
+		// Interval between checks in continuous mode. Defaults to 1 minute.
+		Interval time.Duration
+		// OnReport is called with the report of every check in continuous mode
+		OnReport func(report *ConsistencyReport)
+		// OnError is called for every failed check in continuous mode
+		OnError func(err error)
+	}
+
+	// ConsistencyChecker compares the entity collections of several PHD instances, eg. the replicas behind a load balancer
+	ConsistencyChecker struct {
+		readers []IDBReader
+		config  ConsistencyConfig
+
+		mu        sync.Mutex
+		divergent map[string]EntityDiff
+	}
+
+	// ConsistencyReport is the result of a consistency check
+	ConsistencyReport struct {
+		CheckedAt time.Time
+		// Diffs holds an entry for every entity that differs between readers, sorted by kind and ID
+		Diffs []EntityDiff
+		// Resolved holds the entities that differed on the previous check of a continuous run and no longer do
+		Resolved []EntityDiff
+	}
+
+	// EntityDiff describes how a single entity differs between readers
+	EntityDiff struct {
+		Kind SnapshotKind
+		ID   string
+		// Missing lists the readers that don't have the entity
+		Missing []string
+		// Fields lists the fields whose values differ between the readers that have the entity
+		Fields []FieldDiff
+		// FirstSeen is when the entity was first seen diverging during a continuous run, or the check time otherwise
+		FirstSeen time.Time
+	}
+
+	// FieldDiff holds the values of a single field by reader name. Readers without the field are left out.
+	FieldDiff struct {
+		Path   string
+		Values map[string]any
+	}
+
+	// consistencyCollection fetches one entity collection from a reader, keyed by ID
+	consistencyCollection struct {
+		kind  SnapshotKind
+		fetch func(ctx context.Context, reader IDBReader) (map[string]any, error)
+	}
+
+	// entityFields are the flattened fields of every entity of a collection, by entity ID
+	entityFields map[string]map[string]any
+)
+
+var (
+	errTooFewReaders       error = errors.New("consistency checker needs at least two readers")
+	errReaderNamesMismatch error = errors.New("consistency checker needs one name per reader")
+)
+
+// consistencyCollections are the collections compared by a ConsistencyChecker. Deleted and inactive entities are
+// included, with deleted ones marked by a `deleted` field.
+var consistencyCollections = []consistencyCollection{
+	{kind: SnapshotKindChain, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		options := ChainOptions{IncludeInactive: BoolPtr(true), ExcludeGigastakeApps: BoolPtr(true)}
+		all := options
+		all.IncludeDeleted = BoolPtr(true)
+		return fetchCollection(
+			func() *Iterator[types.Chain] { return listChains(ctx, reader, all) },
+			func() *Iterator[types.Chain] { return listChains(ctx, reader, options) },
+			func(chain *types.Chain) string { return string(chain.ID) })
+	}},
+	{kind: SnapshotKindGigastakeApp, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		return fetchCollection(
+			func() *Iterator[types.GigastakeApp] {
+				return listGigastakeApps(ctx, reader, GigastakeAppOptions{IncludeDeleted: BoolPtr(true)})
+			},
+			func() *Iterator[types.GigastakeApp] { return listGigastakeApps(ctx, reader, GigastakeAppOptions{}) },
+			func(gigastakeApp *types.GigastakeApp) string { return string(gigastakeApp.ID) })
+	}},
+	{kind: SnapshotKindPortalApp, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		return fetchCollection(
+			func() *Iterator[types.PortalApp] {
+				return listPortalApps(ctx, reader, PortalAppOptions{IncludeDeleted: BoolPtr(true)})
+			},
+			func() *Iterator[types.PortalApp] { return listPortalApps(ctx, reader, PortalAppOptions{}) },
+			func(portalApp *types.PortalApp) string { return string(portalApp.ID) })
+	}},
+	{kind: SnapshotKindAccount, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		return fetchCollection(
+			func() *Iterator[types.Account] {
+				return listAccounts(ctx, reader, AccountOptions{IncludeDeleted: BoolPtr(true)})
+			},
+			func() *Iterator[types.Account] { return listAccounts(ctx, reader, AccountOptions{}) },
+			func(account *types.Account) string { return string(account.ID) })
+	}},
+	{kind: SnapshotKindPlan, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		plans, err := reader.GetAllPlans(ctx)
+		if err != nil {
+			return nil, err
+		}
+		collection := make(map[string]any, len(plans))
+		for _, plan := range plans {
+			collection[string(plan.Type)] = plan
+		}
+		return collection, nil
+	}},
+	{kind: SnapshotKindBlockedContract, fetch: func(ctx context.Context, reader IDBReader) (map[string]any, error) {
+		blockedContracts, err := reader.GetBlockedContracts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Only active addresses are listed, so just their presence is compared
+		collection := make(map[string]any, len(blockedContracts.BlockedAddresses))
+		for address := range blockedContracts.BlockedAddresses {
+			collection[string(address)] = map[string]types.BlockedAddress{"blockedAddress": address}
+		}
+		return collection, nil
+	}},
+}
+
+// NewConsistencyChecker returns a ConsistencyChecker comparing the given readers
+func NewConsistencyChecker(readers []IDBReader, config ConsistencyConfig) (*ConsistencyChecker, error) {
+	if len(readers) < 2 {
+		return nil, errTooFewReaders
+	}
+	if len(config.Names) > 0 && len(config.Names) != len(readers) {
+		return nil, errReaderNamesMismatch
+	}
+	if len(config.Names) == 0 {
+		config.Names = make([]string, len(readers))
+		for i := range readers {
+			config.Names[i] = fmt.Sprintf("reader_%d", i)
+		}
+	}
+	if config.TimestampPrecision <= 0 {
+		config.TimestampPrecision = defaultConsistencyTimestampPrecision
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultConsistencyInterval
+	}
+
+	return &ConsistencyChecker{readers: readers, config: config}, nil
+}
+
+// Check fetches every collection from every reader concurrently and returns the entities that differ
+func (c *ConsistencyChecker) Check(ctx context.Context) (*ConsistencyReport, error) {
+	checkedAt := time.Now()
+
+	// fields[collection][reader] holds the flattened entities
+	fields := make([][]entityFields, len(consistencyCollections))
+	errs := make([]error, len(consistencyCollections)*len(c.readers))
+
+	var wg sync.WaitGroup
+	for collectionIndex, collection := range consistencyCollections {
+		fields[collectionIndex] = make([]entityFields, len(c.readers))
+		for readerIndex, reader := range c.readers {
+			wg.Add(1)
+			go func(collectionIndex, readerIndex int, collection consistencyCollection, reader IDBReader) {
+				defer wg.Done()
+
+				entities, err := collection.fetch(ctx, reader)
+				if err != nil {
+					errs[collectionIndex*len(c.readers)+readerIndex] = fmt.Errorf("%s: fetching %s: %w", c.config.Names[readerIndex], collection.kind, err)
+					return
+				}
+				fields[collectionIndex][readerIndex] = c.flattenEntities(entities)
+			}(collectionIndex, readerIndex, collection, reader)
+		}
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	report := &ConsistencyReport{CheckedAt: checkedAt}
+	for collectionIndex, collection := range consistencyCollections {
+		report.Diffs = append(report.Diffs, c.diffCollection(collection.kind, fields[collectionIndex], checkedAt)...)
+	}
+	sortEntityDiffs(report.Diffs)
+
+	return report, nil
+}
+
+// Run checks the readers immediately and then on every interval until the context is cancelled, reporting how
+// long each entity has been diverging and which entities have converged since the previous check
+func (c *ConsistencyChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.runCheck(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ConsistencyChecker) runCheck(ctx context.Context) {
+	report, err := c.Check(ctx)
+	if err != nil {
+		if c.config.OnError != nil && ctx.Err() == nil {
+			c.config.OnError(err)
+		}
+		return
+	}
+
+	c.trackDivergence(report)
+	if c.config.OnReport != nil {
+		c.config.OnReport(report)
+	}
+}
+
+// trackDivergence carries FirstSeen over from the previous check and fills in the resolved entities
+func (c *ConsistencyChecker) trackDivergence(report *ConsistencyReport) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	divergent := make(map[string]EntityDiff, len(report.Diffs))
+	for i, diff := range report.Diffs {
+		key := progressKey(diff.Kind, diff.ID)
+		if previous, ok := c.divergent[key]; ok {
+			report.Diffs[i].FirstSeen = previous.FirstSeen
+		}
+		divergent[key] = report.Diffs[i]
+	}
+
+	for key, previous := range c.divergent {
+		if _, ok := divergent[key]; !ok {
+			report.Resolved = append(report.Resolved, previous)
+		}
+	}
+	sortEntityDiffs(report.Resolved)
+
+	c.divergent = divergent
+}
+
+// diffCollection compares the entities of one collection across readers
+func (c *ConsistencyChecker) diffCollection(kind SnapshotKind, byReader []entityFields, checkedAt time.Time) []EntityDiff {
+	ids := make(map[string]bool)
+	for _, entities := range byReader {
+		for id := range entities {
+			ids[id] = true
+		}
+	}
+
+	diffs := make([]EntityDiff, 0)
+	for _, id := range sortedKeys(ids) {
+		diff := EntityDiff{Kind: kind, ID: id, FirstSeen: checkedAt}
+
+		present := make(map[string]map[string]any)
+		for readerIndex, entities := range byReader {
+			if entity, ok := entities[id]; ok {
+				present[c.config.Names[readerIndex]] = entity
+			} else {
+				diff.Missing = append(diff.Missing, c.config.Names[readerIndex])
+			}
+		}
+		diff.Fields = diffFields(present)
+
+		if len(diff.Missing) > 0 || len(diff.Fields) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return diffs
+}
+
+// diffFields returns the fields that are missing from some of the entities or have different values
+func diffFields(entities map[string]map[string]any) []FieldDiff {
+	paths := make(map[string]bool)
+	for _, fields := range entities {
+		for path := range fields {
+			paths[path] = true
+		}
+	}
+
+	diffs := make([]FieldDiff, 0)
+	for _, path := range sortedKeys(paths) {
+		values := make(map[string]any)
+		for name, fields := range entities {
+			if value, ok := fields[path]; ok {
+				values[name] = value
+			}
+		}
+
+		if len(values) != len(entities) || !allEqual(values) {
+			diffs = append(diffs, FieldDiff{Path: path, Values: values})
+		}
+	}
+
+	return diffs
+}
+
+func allEqual(values map[string]any) bool {
+	var first any
+	seen := false
+	for _, value := range values {
+		if !seen {
+			first, seen = value, true
+			continue
+		}
+		if !reflect.DeepEqual(first, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenEntities converts every entity into its JSON fields keyed by dotted path, with timestamps normalized
+func (c *ConsistencyChecker) flattenEntities(entities map[string]any) entityFields {
+	ignored := make(map[string]bool, len(c.config.IgnoreFields))
+	for _, path := range c.config.IgnoreFields {
+		ignored[path] = true
+	}
+
+	flattened := make(entityFields, len(entities))
+	for id, entity := range entities {
+		var generic any
+		entityJSON, err := json.Marshal(entity)
+		if err == nil {
+			err = json.Unmarshal(entityJSON, &generic)
+		}
+		if err != nil {
+			generic = fmt.Sprint(entity)
+		}
+
+		fields := make(map[string]any)
+		c.flatten("", generic, ignored, fields)
+		flattened[id] = fields
+	}
+
+	return flattened
+}
+
+func (c *ConsistencyChecker) flatten(path string, value any, ignored map[string]bool, fields map[string]any) {
+	if ignored[path] {
+		return
+	}
+
+	switch value := value.(type) {
+	case nil:
+		return
+	case []any:
+		// Empty and missing lists are the same to PHD
+		if len(value) > 0 {
+			fields[path] = value
+		}
+	case map[string]any:
+		for key, child := range value {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			c.flatten(childPath, child, ignored, fields)
+		}
+	case string:
+		fields[path] = c.normalizeTimestamp(value)
+	default:
+		fields[path] = value
+	}
+}
+
+// normalizeTimestamp converts RFC 3339 timestamps to UTC at the configured precision, so replicas that
+// serialize the same instant differently compare equal. Other strings are returned as is.
+func (c *ConsistencyChecker) normalizeTimestamp(value string) string {
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+	return timestamp.UTC().Truncate(c.config.TimestampPrecision).Format(time.RFC3339Nano)
+}
+
+// fetchCollection reads every item of the collection, marking the items missing from live as deleted
+func fetchCollection[T any](all, live func() *Iterator[T], id func(*T) string) (map[string]any, error) {
+	collection := make(map[string]any)
+	err := listWithDeleted(all, live, id, func(item *T, deleted bool) error {
+		collection[id(item)] = deletableEntity[T]{Entity: item, Deleted: deleted}
+		return nil
+	})
+	return collection, err
+}
+
+// deletableEntity adds a `deleted` field to the JSON of an entity
+type deletableEntity[T any] struct {
+	Entity  *T
+	Deleted bool
+}
+
+func (e deletableEntity[T]) MarshalJSON() ([]byte, error) {
+	entityJSON, err := json.Marshal(e.Entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(entityJSON, &fields); err != nil {
+		return nil, err
+	}
+	fields["deleted"], _ = json.Marshal(e.Deleted)
+
+	return json.Marshal(fields)
+}
+
+// Consistent reports whether the readers returned the same data
+func (r *ConsistencyReport) Consistent() bool {
+	return len(r.Diffs) == 0
+}
+
+// String formats the diff as one line per missing entry and differing field
+func (d EntityDiff) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s:", d.Kind, d.ID)
+	if len(d.Missing) > 0 {
+		fmt.Fprintf(&b, " missing from %s;", strings.Join(d.Missing, ", "))
+	}
+	for _, field := range d.Fields {
+		fmt.Fprintf(&b, " %s", field.Path)
+		for _, name := range sortedKeys(field.Values) {
+			fmt.Fprintf(&b, " %s=%v", name, field.Values[name])
+		}
+		b.WriteString(";")
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+func sortEntityDiffs(diffs []EntityDiff) {
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Kind != diffs[j].Kind {
+			return diffs[i].Kind < diffs[j].Kind
+		}
+		return diffs[i].ID < diffs[j].ID
+	})
+}
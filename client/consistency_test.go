@@ -0,0 +1,204 @@
+package dbclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConsistencyChecker_Check(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		diverge       func(t *testing.T, replica *FakeDBClient)
+		expectedDiffs []EntityDiff
+	}{
+		{
+			name: "Should find no differences between identical readers",
+		},
+		{
+			name: "Should find a changed field",
+			diverge: func(t *testing.T, replica *FakeDBClient) {
+				_, err := replica.ActivateChain(ctx, "0001", false)
+				assert.NoError(t, err)
+			},
+			expectedDiffs: []EntityDiff{
+				{Kind: SnapshotKindChain, ID: "0001", Fields: []FieldDiff{{Path: "active", Values: map[string]any{"primary": true, "replica": false}}}},
+			},
+		},
+		{
+			name: "Should find a missing entity",
+			diverge: func(t *testing.T, replica *FakeDBClient) {
+				_, err := replica.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xnew_blocked", Active: true})
+				assert.NoError(t, err)
+			},
+			expectedDiffs: []EntityDiff{
+				{Kind: SnapshotKindBlockedContract, ID: "0xnew_blocked", Missing: []string{"primary"}, Fields: []FieldDiff{}},
+			},
+		},
+		{
+			name: "Should find an entity deleted on only one reader",
+			diverge: func(t *testing.T, replica *FakeDBClient) {
+				_, err := replica.DeleteAccount(ctx, "account_1")
+				assert.NoError(t, err)
+			},
+			expectedDiffs: []EntityDiff{
+				{Kind: SnapshotKindAccount, ID: "account_1", Fields: []FieldDiff{{Path: "deleted", Values: map[string]any{"primary": false, "replica": true}}}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary, replica := newTestFakeDBClient(), newTestFakeDBClient()
+			if test.diverge != nil {
+				test.diverge(t, replica)
+			}
+
+			checker, err := NewConsistencyChecker([]IDBReader{primary, replica}, ConsistencyConfig{Names: []string{"primary", "replica"}, IgnoreFields: []string{"updatedAt"}})
+			assert.NoError(t, err)
+
+			report, err := checker.Check(ctx)
+			assert.NoError(t, err)
+			for i := range report.Diffs {
+				assert.Equal(t, report.CheckedAt, report.Diffs[i].FirstSeen)
+				report.Diffs[i].FirstSeen = time.Time{}
+			}
+			assert.Equal(t, len(test.expectedDiffs) == 0, report.Consistent())
+			if len(test.expectedDiffs) > 0 {
+				assert.Equal(t, test.expectedDiffs, report.Diffs)
+			}
+		})
+	}
+}
+
+func Test_ConsistencyChecker_CreatedWhileListing(t *testing.T) {
+	ctx := context.Background()
+
+	primary, replica := &insertingReader{FakeDBClient: newTestFakeDBClient()}, newTestFakeDBClient()
+	_, err := replica.CreatePortalApp(ctx, types.PortalApp{AccountID: "account_1", Name: "pokt_app_new"})
+	assert.NoError(t, err)
+
+	checker, err := NewConsistencyChecker([]IDBReader{primary, replica}, ConsistencyConfig{IgnoreFields: []string{"createdAt", "updatedAt"}})
+	assert.NoError(t, err)
+
+	report, err := checker.Check(ctx)
+	assert.NoError(t, err)
+	assert.NotNil(t, primary.created)
+	assert.Empty(t, report.Diffs, "a Portal App created between the listings is not reported as deleted")
+}
+
+func Test_ConsistencyChecker_CreatedAtPrecision(t *testing.T) {
+	createdAt := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	primary, replica := newTestFakeDBClient(), newTestFakeDBClient()
+	primary.chains["0001"].CreatedAt = createdAt
+	replica.chains["0001"].CreatedAt = createdAt.In(time.FixedZone("UTC-3", -3*60*60)).Add(300 * time.Millisecond)
+
+	checker, err := NewConsistencyChecker([]IDBReader{primary, replica}, ConsistencyConfig{})
+	assert.NoError(t, err)
+	report, err := checker.Check(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, report.Consistent(), report.Diffs)
+
+	checker, err = NewConsistencyChecker([]IDBReader{primary, replica}, ConsistencyConfig{TimestampPrecision: time.Millisecond})
+	assert.NoError(t, err)
+	report, err = checker.Check(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, report.Diffs, 1)
+	assert.Equal(t, "0001", report.Diffs[0].ID)
+	assert.Equal(t, "createdAt", report.Diffs[0].Fields[0].Path)
+}
+
+func Test_ConsistencyChecker_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	primary, replica := newTestFakeDBClient(), newTestFakeDBClient()
+	_, err := replica.ActivateChain(ctx, "0001", false)
+	assert.NoError(t, err)
+
+	reports := make(chan *ConsistencyReport)
+	checker, err := NewConsistencyChecker([]IDBReader{primary, replica}, ConsistencyConfig{
+		IgnoreFields: []string{"updatedAt"},
+		Interval:     5 * time.Millisecond,
+		OnReport: func(report *ConsistencyReport) {
+			select {
+			case reports <- report:
+			case <-ctx.Done():
+			}
+		},
+	})
+	assert.NoError(t, err)
+	go checker.Run(ctx)
+
+	first := <-reports
+	assert.Len(t, first.Diffs, 1)
+
+	second := <-reports
+	assert.Len(t, second.Diffs, 1)
+	assert.Equal(t, first.Diffs[0].FirstSeen, second.Diffs[0].FirstSeen, "divergence is tracked across checks")
+	assert.True(t, second.CheckedAt.After(second.Diffs[0].FirstSeen))
+
+	_, err = replica.ActivateChain(ctx, "0001", true)
+	assert.NoError(t, err)
+
+	var resolved *ConsistencyReport
+	for resolved == nil || len(resolved.Resolved) == 0 {
+		resolved = <-reports
+	}
+	assert.True(t, resolved.Consistent())
+	assert.Equal(t, "0001", resolved.Resolved[0].ID)
+	cancel()
+}
+
+func Test_NewConsistencyChecker(t *testing.T) {
+	tests := []struct {
+		name          string
+		readers       int
+		names         []string
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name:        "Should fail with fewer than two readers",
+			readers:     1,
+			expectedErr: errTooFewReaders,
+		},
+		{
+			name:        "Should fail when the names don't match the readers",
+			readers:     3,
+			names:       []string{"primary", "replica"},
+			expectedErr: errReaderNamesMismatch,
+		},
+		{
+			name:          "Should keep the given names",
+			readers:       2,
+			names:         []string{"primary", "replica"},
+			expectedNames: []string{"primary", "replica"},
+		},
+		{
+			name:          "Should default the names",
+			readers:       2,
+			expectedNames: []string{"reader_0", "reader_1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			readers := make([]IDBReader, test.readers)
+			for i := range readers {
+				readers[i] = newTestFakeDBClient()
+			}
+
+			checker, err := NewConsistencyChecker(readers, ConsistencyConfig{Names: test.names})
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expectedNames, checker.config.Names)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package dbclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	diskCacheVersion       = 1
+	defaultDiskCacheMaxAge = 24 * time.Hour
+)
+
+type (
+	// DiskCacheConfig configures a DiskCachedReader
+	DiskCacheConfig struct {
+		// Dir is the directory the collections are written to. It is created if it doesn't exist.
+		Dir string
+		// MaxAge is the age after which a stored collection is no longer served. Defaults to 24 hours.
+		MaxAge time.Duration
+		// OnError is called when a collection can't be written or a stored one is corrupt
+		OnError func(err error)
+	}
+
+	// DiskCachedReader is an IDBReader writing the last successful result of every collection endpoint to a
+	// directory and serving it while PHD can't be reached, eg. when a service starts during an outage.
+	// Served collections are marked Stale in the Freshness of the context, see WithFreshness.
+	// Single entity lookups and iterators are passed through.
+	DiskCachedReader struct {
+		IDBReader
+		config DiskCacheConfig
+	}
+
+	// diskCacheFile is the content of a stored collection
+	diskCacheFile struct {
+		Version  int             `json:"version"`
+		Key      string          `json:"key"`
+		StoredAt time.Time       `json:"storedAt"`
+		Checksum string          `json:"checksum"`
+		Data     json.RawMessage `json:"data"`
+	}
+)
+
+var _ IDBReader = &DiskCachedReader{}
+
+var (
+	errDiskCacheDirNotProvided error = errors.New("disk cache dir not provided")
+	errDiskCacheCorrupt        error = errors.New("corrupt disk cache file")
+)
+
+// NewDiskCachedReader returns a DiskCachedReader wrapping the given reader
+func NewDiskCachedReader(reader IDBReader, config DiskCacheConfig) (*DiskCachedReader, error) {
+	if config.Dir == "" {
+		return nil, errDiskCacheDirNotProvided
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultDiskCacheMaxAge
+	}
+
+	if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &DiskCachedReader{IDBReader: reader, config: config}, nil
+}
+
+// diskReadThrough fetches a collection and stores it, or serves the stored collection if PHD can't be reached
+func diskReadThrough[T any](ctx context.Context, c *DiskCachedReader, key string, fetch func() (T, error)) (T, error) {
+	value, err := fetch()
+	if err == nil {
+		if storeErr := c.store(key, value); storeErr != nil {
+			c.onError(storeErr)
+		}
+		return value, nil
+	}
+
+	if !isOutage(ctx, err) {
+		return value, err
+	}
+
+	var stored T
+	storedAt, loadErr := c.load(key, &stored)
+	if loadErr != nil {
+		if !errors.Is(loadErr, os.ErrNotExist) {
+			c.onError(loadErr)
+		}
+		return value, err
+	}
+	if time.Since(storedAt) > c.config.MaxAge {
+		return value, err
+	}
+
+	if freshness, ok := ctx.Value(freshnessContextKey{}).(*Freshness); ok {
+		*freshness = Freshness{Stale: true, StoredAt: storedAt}
+	}
+
+	return stored, nil
+}
+
+// isOutage reports whether an error means PHD couldn't serve the request, rather than a rejected request
+// or a cancelled context
+func isOutage(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrUnsupportedOption) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+
+	return true
+}
+
+// store writes the collection to a temporary file and renames it over the previous one, so that a crash
+// never leaves a partially written file behind. An unchanged collection is only rewritten once the stored
+// file is older than half of MaxAge, to keep it servable.
+func (c *DiskCachedReader) store(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	checksum := diskCacheChecksum(data)
+	if storedChecksum, storedAt, err := c.storedChecksum(key); err == nil &&
+		storedChecksum == checksum && time.Since(storedAt) < c.config.MaxAge/2 {
+		return nil
+	}
+
+	content, err := json.Marshal(diskCacheFile{
+		Version:  diskCacheVersion,
+		Key:      key,
+		StoredAt: time.Now().UTC(),
+		Checksum: checksum,
+		Data:     data,
+	})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.config.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return err
+	}
+
+	return syncDir(c.config.Dir)
+}
+
+// storedChecksum returns the checksum of a valid stored collection and when it was stored
+func (c *DiskCachedReader) storedChecksum(key string) (string, time.Time, error) {
+	content, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var file diskCacheFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return "", time.Time{}, err
+	}
+	if file.Version != diskCacheVersion || file.Key != key || file.Checksum != diskCacheChecksum(file.Data) {
+		return "", time.Time{}, errDiskCacheCorrupt
+	}
+
+	return file.Checksum, file.StoredAt, nil
+}
+
+// load reads a stored collection into value and returns when it was stored
+func (c *DiskCachedReader) load(key string, value any) (time.Time, error) {
+	content, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var file diskCacheFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %s", errDiskCacheCorrupt, key, err)
+	}
+	if file.Version != diskCacheVersion || file.Key != key || file.Checksum != diskCacheChecksum(file.Data) {
+		return time.Time{}, fmt.Errorf("%w: %s", errDiskCacheCorrupt, key)
+	}
+
+	if err := json.Unmarshal(file.Data, value); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %s", errDiskCacheCorrupt, key, err)
+	}
+
+	return file.StoredAt, nil
+}
+
+// path returns the file of a collection. Keys are hashed as they contain the encoded options.
+func (c *DiskCachedReader) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.config.Dir, hex.EncodeToString(hash[:8])+".json")
+}
+
+func (c *DiskCachedReader) onError(err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(err)
+	}
+}
+
+// syncDir flushes a directory so that a rename in it survives a crash
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
+
+func diskCacheChecksum(data []byte) string {
+	checksum := sha256.Sum256(data)
+	return hex.EncodeToString(checksum[:])
+}
+
+/* ------------ IDBReader Methods ------------ */
+
+// GetAllChains returns all chains - GET `/v2/chain`
+func (c *DiskCachedReader) GetAllChains(ctx context.Context, options ...ChainOptions) ([]*types.Chain, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityChain, "all", optionsCacheKey(options)), func() ([]*types.Chain, error) {
+		return c.IDBReader.GetAllChains(ctx, options...)
+	})
+}
+
+// GetAllGigastakeApps returns all GigastakeApps - GET `/v2/gigastake`
+func (c *DiskCachedReader) GetAllGigastakeApps(ctx context.Context, options ...GigastakeAppOptions) ([]*types.GigastakeApp, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityGigastakeApp, "all", optionsCacheKey(options)), func() ([]*types.GigastakeApp, error) {
+		return c.IDBReader.GetAllGigastakeApps(ctx, options...)
+	})
+}
+
+// GetAllPortalApps returns all Portal Apps - GET `/v2/portal_app`
+func (c *DiskCachedReader) GetAllPortalApps(ctx context.Context, options ...PortalAppOptions) ([]*types.PortalApp, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityPortalApp, "all", optionsCacheKey(options)), func() ([]*types.PortalApp, error) {
+		return c.IDBReader.GetAllPortalApps(ctx, options...)
+	})
+}
+
+// GetPortalAppsForMiddleware returns all Portal Apps - GET `/v2/middleware/portal_app`
+func (c *DiskCachedReader) GetPortalAppsForMiddleware(ctx context.Context) ([]*types.PortalAppLite, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityMiddleware, "all"), func() ([]*types.PortalAppLite, error) {
+		return c.IDBReader.GetPortalAppsForMiddleware(ctx)
+	})
+}
+
+// GetAllAccounts returns all Accounts - GET `/v2/account`
+func (c *DiskCachedReader) GetAllAccounts(ctx context.Context, options ...AccountOptions) ([]*types.Account, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityAccount, "all", optionsCacheKey(options)), func() ([]*types.Account, error) {
+		return c.IDBReader.GetAllAccounts(ctx, options...)
+	})
+}
+
+// GetAllPlans returns all plans - GET `/v2/plan`
+func (c *DiskCachedReader) GetAllPlans(ctx context.Context) ([]types.Plan, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityPlan, "all"), func() ([]types.Plan, error) {
+		return c.IDBReader.GetAllPlans(ctx)
+	})
+}
+
+// GetBlockedContracts returns all blocked contracts - GET `/v2/blocked_contract`
+func (c *DiskCachedReader) GetBlockedContracts(ctx context.Context) (types.GlobalBlockedContracts, error) {
+	return diskReadThrough(ctx, c, cacheKey(CacheEntityBlockedContracts, "all"), func() (types.GlobalBlockedContracts, error) {
+		return c.IDBReader.GetBlockedContracts(ctx)
+	})
+}
@@ -0,0 +1,197 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// unavailableReader fails the chain collection with err while it is set
+type unavailableReader struct {
+	*FakeDBClient
+	err error
+}
+
+func (r *unavailableReader) GetAllChains(ctx context.Context, options ...ChainOptions) ([]*types.Chain, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.FakeDBClient.GetAllChains(ctx, options...)
+}
+
+func Test_DiskCachedReader(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		maxAge        time.Duration
+		corrupt       bool
+		cancelled     bool
+		expectedStale bool
+		expectedErr   bool
+	}{
+		{
+			name:          "Should serve the stored collection while PHD fails",
+			err:           &APIError{StatusCode: http.StatusServiceUnavailable},
+			expectedStale: true,
+		},
+		{
+			name:          "Should serve the stored collection while PHD can't be reached",
+			err:           errors.New("connection refused"),
+			expectedStale: true,
+		},
+		{
+			name:        "Should return the error of a rejected request",
+			err:         &APIError{StatusCode: http.StatusUnauthorized},
+			expectedErr: true,
+		},
+		{
+			name:        "Should return the error of a cancelled request",
+			err:         context.Canceled,
+			cancelled:   true,
+			expectedErr: true,
+		},
+		{
+			name:        "Should not serve a collection older than MaxAge",
+			err:         &APIError{StatusCode: http.StatusServiceUnavailable},
+			maxAge:      time.Nanosecond,
+			expectedErr: true,
+		},
+		{
+			name:        "Should not serve a corrupt collection",
+			err:         &APIError{StatusCode: http.StatusServiceUnavailable},
+			corrupt:     true,
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			reader := &unavailableReader{FakeDBClient: newTestFakeDBClient()}
+			var cacheErrs []error
+			diskCached, err := NewDiskCachedReader(reader, DiskCacheConfig{
+				Dir:     dir,
+				MaxAge:  test.maxAge,
+				OnError: func(err error) { cacheErrs = append(cacheErrs, err) },
+			})
+			assert.NoError(t, err)
+
+			live, err := diskCached.GetAllChains(context.Background(), ChainOptions{IncludeInactive: BoolPtr(true)})
+			assert.NoError(t, err)
+			if test.corrupt {
+				files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+				assert.Len(t, files, 1)
+				assert.NoError(t, os.WriteFile(files[0], []byte(`{"version": 1, "data": []}`), 0o600))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			if test.cancelled {
+				cancel()
+			}
+			defer cancel()
+
+			reader.err = test.err
+			var freshness Freshness
+			stored, err := diskCached.GetAllChains(WithFreshness(ctx, &freshness), ChainOptions{IncludeInactive: BoolPtr(true)})
+
+			assert.Equal(t, test.expectedStale, freshness.Stale)
+			if test.expectedErr {
+				assert.ErrorIs(t, err, test.err)
+				assert.Nil(t, stored)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, live, stored)
+				assert.WithinDuration(t, time.Now(), freshness.StoredAt, time.Minute)
+			}
+			if test.corrupt {
+				assert.Len(t, cacheErrs, 1)
+				assert.ErrorIs(t, cacheErrs[0], errDiskCacheCorrupt)
+			} else {
+				assert.Empty(t, cacheErrs)
+			}
+		})
+	}
+}
+
+func Test_DiskCachedReader_StoresPerOptions(t *testing.T) {
+	reader := &unavailableReader{FakeDBClient: newTestFakeDBClient()}
+	diskCached, err := NewDiskCachedReader(reader, DiskCacheConfig{Dir: filepath.Join(t.TempDir(), "phd")})
+	assert.NoError(t, err)
+
+	active, err := diskCached.GetAllChains(context.Background())
+	assert.NoError(t, err)
+	all, err := diskCached.GetAllChains(context.Background(), ChainOptions{IncludeInactive: BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.NotEqual(t, len(active), len(all))
+
+	reader.err = &APIError{StatusCode: http.StatusBadGateway}
+	storedActive, err := diskCached.GetAllChains(context.Background())
+	assert.NoError(t, err)
+	storedAll, err := diskCached.GetAllChains(context.Background(), ChainOptions{IncludeInactive: BoolPtr(true)})
+	assert.NoError(t, err)
+
+	assert.Equal(t, active, storedActive)
+	assert.Equal(t, all, storedAll)
+}
+
+func Test_DiskCachedReader_SkipsUnchangedCollections(t *testing.T) {
+	tests := []struct {
+		name            string
+		change          bool
+		maxAge          time.Duration
+		expectedRewrite bool
+	}{
+		{
+			name: "Should not rewrite an unchanged collection",
+		},
+		{
+			name:            "Should rewrite a changed collection",
+			change:          true,
+			expectedRewrite: true,
+		},
+		{
+			name:            "Should rewrite an unchanged collection older than half of MaxAge",
+			maxAge:          time.Nanosecond,
+			expectedRewrite: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			reader := &unavailableReader{FakeDBClient: newTestFakeDBClient()}
+			diskCached, err := NewDiskCachedReader(reader, DiskCacheConfig{Dir: dir, MaxAge: test.maxAge})
+			assert.NoError(t, err)
+
+			chains, err := diskCached.GetAllChains(context.Background())
+			assert.NoError(t, err)
+			files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+			assert.Len(t, files, 1)
+			stored, err := os.ReadFile(files[0])
+			assert.NoError(t, err)
+
+			if test.change {
+				_, err := reader.ActivateChain(context.Background(), chains[0].ID, false)
+				assert.NoError(t, err)
+			}
+			_, err = diskCached.GetAllChains(context.Background())
+			assert.NoError(t, err)
+
+			rewritten, err := os.ReadFile(files[0])
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRewrite, string(stored) != string(rewritten))
+		})
+	}
+}
+
+func Test_NewDiskCachedReader(t *testing.T) {
+	_, err := NewDiskCachedReader(newTestFakeDBClient(), DiskCacheConfig{})
+	assert.ErrorIs(t, err, errDiskCacheDirNotProvided)
+}
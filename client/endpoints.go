@@ -0,0 +1,285 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointStrategy selects how requests are spread across multiple PHD endpoints
+type EndpointStrategy string
+
+const (
+	// EndpointPrimarySecondary sends every request to the first healthy endpoint in the order they were configured
+	EndpointPrimarySecondary EndpointStrategy = "primary_secondary"
+	// EndpointRoundRobin rotates requests across all healthy endpoints
+	EndpointRoundRobin EndpointStrategy = "round_robin"
+
+	defaultMaxEndpointFailures   = 3
+	defaultEndpointProbeInterval = 10 * time.Second
+	endpointProbeTimeout         = 2 * time.Second
+
+	healthCheckPath = "/healthz"
+)
+
+type (
+	// endpointPool is a RoundTripper that rewrites every request built against the primary
+	// endpoint to one of the healthy PHD endpoints, ejecting endpoints after consecutive failures
+	endpointPool struct {
+		underlying    http.RoundTripper
+		endpoints     []*endpoint
+		strategy      EndpointStrategy
+		pinWrites     bool
+		maxFailures   int
+		probeInterval time.Duration
+		next          atomic.Uint64
+	}
+
+	endpoint struct {
+		baseURL string
+		base    *url.URL
+
+		mu       sync.Mutex
+		failures int
+		ejected  bool
+		probeAt  time.Time
+		probing  bool
+	}
+)
+
+// baseURLs returns the configured PHD base URLs without trailing slashes or duplicates, primary first
+func (c Config) baseURLs() []string {
+	seen := make(map[string]bool)
+	baseURLs := make([]string, 0, len(c.BaseURLs)+1)
+
+	for _, baseURL := range append([]string{c.BaseURL}, c.BaseURLs...) {
+		baseURL = strings.TrimRight(baseURL, "/")
+		if baseURL == "" || seen[baseURL] {
+			continue
+		}
+		seen[baseURL] = true
+		baseURLs = append(baseURLs, baseURL)
+	}
+
+	return baseURLs
+}
+
+func newEndpointPool(config Config, underlying http.RoundTripper) *endpointPool {
+	pool := &endpointPool{
+		underlying:    underlying,
+		strategy:      config.EndpointStrategy,
+		pinWrites:     config.PinWritesToPrimary,
+		maxFailures:   config.MaxEndpointFailures,
+		probeInterval: config.EndpointProbeInterval,
+	}
+	if pool.maxFailures <= 0 {
+		pool.maxFailures = defaultMaxEndpointFailures
+	}
+	if pool.probeInterval <= 0 {
+		pool.probeInterval = defaultEndpointProbeInterval
+	}
+
+	for _, baseURL := range config.baseURLs() {
+		// Base URLs are validated by NewDBClient, so they always parse
+		base, _ := url.Parse(baseURL)
+		pool.endpoints = append(pool.endpoints, &endpoint{baseURL: baseURL, base: base})
+	}
+
+	return pool
+}
+
+func (p *endpointPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	if p.pinWrites && !isReadMethod(req.Method) {
+		return p.send(p.endpoints[0], req)
+	}
+
+	tried := make(map[*endpoint]bool, len(p.endpoints))
+	for {
+		if len(tried) > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		ep := p.pick(tried)
+		tried[ep] = true
+
+		resp, err := p.send(ep, req)
+
+		// Idempotent requests fail over to the next endpoint straight away, everything else is left to the RetryPolicy.
+		// Conditional updates are sent once.
+		failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		if !failed || req.Context().Err() != nil || !isIdempotentMethod(req.Method) || isConditional(req) || len(tried) == len(p.endpoints) {
+			return resp, err
+		}
+		if err == nil {
+			drainBody(resp)
+		}
+	}
+}
+
+// send rewrites the request to the given endpoint and records the outcome against it
+func (p *endpointPool) send(ep *endpoint, req *http.Request) (*http.Response, error) {
+	outReq := req
+	if rest, ok := trimBaseURL(req.URL, p.endpoints[0].base); ok && ep != p.endpoints[0] {
+		target, err := url.Parse(ep.baseURL + rest)
+		if err != nil {
+			return nil, err
+		}
+		outReq = req.Clone(req.Context())
+		outReq.URL = target
+		outReq.Host = target.Host
+	}
+
+	resp, err := p.underlying.RoundTrip(outReq)
+
+	switch {
+	case isEndpointFailure(req, resp, err):
+		p.recordFailure(ep)
+	case err == nil:
+		ep.recordSuccess()
+	}
+
+	return resp, err
+}
+
+// trimBaseURL returns the escaped path and query of the request URL following the base URL, if it is built
+// against it. The scheme and host must match and the base path must be a whole prefix of the request path.
+func trimBaseURL(reqURL, base *url.URL) (string, bool) {
+	if base == nil || reqURL.Scheme != base.Scheme || reqURL.Host != base.Host {
+		return "", false
+	}
+
+	path, basePath := reqURL.EscapedPath(), base.EscapedPath()
+	rest := strings.TrimPrefix(path, basePath)
+	if len(rest) == len(path) && basePath != "" || rest != "" && !strings.HasPrefix(rest, "/") {
+		return "", false
+	}
+
+	if reqURL.RawQuery != "" {
+		rest += "?" + reqURL.RawQuery
+	}
+	return rest, true
+}
+
+// isEndpointFailure reports whether an attempt failed because of the endpoint: a transport error or a 5xx
+// response. Requests the caller gave up on and requests rejected by an open circuit breaker don't count.
+func isEndpointFailure(req *http.Request, resp *http.Response, err error) bool {
+	if err == nil {
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+	return req.Context().Err() == nil && !errors.Is(err, ErrCircuitOpen) &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// pick selects the endpoint for the next attempt, skipping endpoints already tried for this request.
+// When every remaining endpoint is ejected the first of them is used anyway rather than failing outright.
+func (p *endpointPool) pick(tried map[*endpoint]bool) *endpoint {
+	now := time.Now()
+
+	var healthy, remaining []*endpoint
+	for _, ep := range p.endpoints {
+		if p.shouldProbe(ep, now) {
+			go p.probe(ep)
+		}
+		if tried[ep] {
+			continue
+		}
+		remaining = append(remaining, ep)
+		if ep.isHealthy() {
+			healthy = append(healthy, ep)
+		}
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = remaining
+	}
+	if len(candidates) == 0 {
+		return p.endpoints[0]
+	}
+
+	if p.strategy == EndpointRoundRobin {
+		return candidates[(p.next.Add(1)-1)%uint64(len(candidates))]
+	}
+	return candidates[0]
+}
+
+func (p *endpointPool) recordFailure(ep *endpoint) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.failures++
+	if !ep.ejected && ep.failures >= p.maxFailures {
+		ep.ejected = true
+		ep.probeAt = time.Now().Add(p.probeInterval)
+	}
+}
+
+func (ep *endpoint) recordSuccess() {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.failures = 0
+	ep.ejected = false
+}
+
+func (ep *endpoint) isHealthy() bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	return !ep.ejected
+}
+
+// shouldProbe reports whether an ejected endpoint is due for a health check and marks it as being probed
+func (p *endpointPool) shouldProbe(ep *endpoint, now time.Time) bool {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if !ep.ejected || ep.probing || now.Before(ep.probeAt) {
+		return false
+	}
+	ep.probing = true
+	return true
+}
+
+// probe checks an ejected endpoint's `/healthz` route and restores it if it responds with 200 OK
+func (p *endpointPool) probe(ep *endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), endpointProbeTimeout)
+	defer cancel()
+
+	healthy := false
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.baseURL+healthCheckPath, nil)
+	if err == nil {
+		if resp, err := p.underlying.RoundTrip(req); err == nil {
+			healthy = resp.StatusCode == http.StatusOK
+			drainBody(resp)
+		}
+	}
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	ep.probing = false
+	if healthy {
+		ep.failures = 0
+		ep.ejected = false
+	} else {
+		ep.probeAt = time.Now().Add(p.probeInterval)
+	}
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+func isIdempotentMethod(method string) bool {
+	return isReadMethod(method) || method == http.MethodPut || method == http.MethodDelete
+}
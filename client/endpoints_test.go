@@ -0,0 +1,272 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPHDInstance is a minimal PHD stand-in that counts requests and can be switched off
+type testPHDInstance struct {
+	*httptest.Server
+	requests atomic.Int32
+	down     atomic.Bool
+}
+
+func newTestPHDInstance() *testPHDInstance {
+	instance := &testPHDInstance{}
+	instance.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if instance.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == healthCheckPath {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		instance.requests.Add(1)
+		_, _ = w.Write([]byte(`{"id":"0001"}`))
+	}))
+	return instance
+}
+
+func Test_EndpointFailover(t *testing.T) {
+	tests := []struct {
+		name             string
+		strategy         EndpointStrategy
+		pinWrites        bool
+		primaryDown      bool
+		primaryClosed    bool
+		write            bool
+		calls            int
+		expectedPrimary  int32
+		expectedFailover int32
+		expectErr        bool
+	}{
+		{
+			name:            "Should send every request to the primary when it is healthy",
+			calls:           4,
+			expectedPrimary: 4,
+		},
+		{
+			name:             "Should rotate requests across endpoints with round robin",
+			strategy:         EndpointRoundRobin,
+			calls:            4,
+			expectedPrimary:  2,
+			expectedFailover: 2,
+		},
+		{
+			name:             "Should fail over to the secondary when the primary returns 503",
+			primaryDown:      true,
+			calls:            4,
+			expectedFailover: 4,
+		},
+		{
+			name:             "Should fail over to the secondary when the primary refuses connections",
+			primaryClosed:    true,
+			calls:            4,
+			expectedFailover: 4,
+		},
+		{
+			name:        "Should not fail over writes pinned to the primary",
+			pinWrites:   true,
+			primaryDown: true,
+			write:       true,
+			calls:       1,
+			expectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			primary, secondary := newTestPHDInstance(), newTestPHDInstance()
+			defer primary.Close()
+			defer secondary.Close()
+
+			primary.down.Store(test.primaryDown)
+			if test.primaryClosed {
+				primary.Close()
+			}
+
+			client, err := NewDBClient(Config{
+				BaseURLs:           []string{primary.URL, secondary.URL + "/"},
+				APIKey:             "test_api_key_6789",
+				Retries:            1,
+				Timeout:            5 * time.Second,
+				EndpointStrategy:   test.strategy,
+				PinWritesToPrimary: test.pinWrites,
+			})
+			assert.NoError(t, err)
+
+			for i := 0; i < test.calls; i++ {
+				if test.write {
+					_, err = client.UpdateChain(context.Background(), types.UpdateChain{ID: "0001"})
+				} else {
+					_, err = client.GetChainByID(context.Background(), "0001")
+				}
+				if test.expectErr {
+					assert.ErrorIs(t, err, ErrServer)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+
+			assert.Equal(t, test.expectedPrimary, primary.requests.Load())
+			assert.Equal(t, test.expectedFailover, secondary.requests.Load())
+		})
+	}
+}
+
+func Test_EndpointEjectionAndProbe(t *testing.T) {
+	primary, secondary := newTestPHDInstance(), newTestPHDInstance()
+	defer primary.Close()
+	defer secondary.Close()
+
+	client, err := NewDBClient(Config{
+		BaseURL:               primary.URL,
+		BaseURLs:              []string{secondary.URL},
+		APIKey:                "test_api_key_6789",
+		Retries:               1,
+		Timeout:               5 * time.Second,
+		RetryPolicy:           fixedRetryPolicy{retries: 1},
+		MaxEndpointFailures:   2,
+		EndpointProbeInterval: 20 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	// Eject the primary after two consecutive failures
+	primary.down.Store(true)
+	for i := 0; i < 2; i++ {
+		_, err = client.GetChainByID(context.Background(), "0001")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), secondary.requests.Load())
+
+	// While ejected the primary is skipped entirely
+	primary.down.Store(false)
+	_, err = client.GetChainByID(context.Background(), "0001")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), primary.requests.Load())
+	assert.Equal(t, int32(3), secondary.requests.Load())
+
+	// Once the probe interval passes the next request triggers a health check that restores the primary
+	<-time.After(30 * time.Millisecond)
+	_, err = client.GetChainByID(context.Background(), "0001")
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, err := client.GetChainByID(context.Background(), "0001")
+		return err == nil && primary.requests.Load() > 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func Test_EndpointPool_RecordsEndpointFailuresOnly(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          int
+		err             error
+		expectedEjected bool
+	}{
+		{
+			name:            "Should count a transport error",
+			err:             errors.New("connection refused"),
+			expectedEjected: true,
+		},
+		{
+			name:            "Should count a 5xx response",
+			status:          http.StatusBadGateway,
+			expectedEjected: true,
+		},
+		{
+			name:   "Should not count a 4xx response",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "Should not count an open circuit breaker",
+			err:  &CircuitOpenError{Host: "phd-1", RetryAt: time.Now().Add(time.Minute)},
+		},
+		{
+			name: "Should not count a cancelled request",
+			err:  fmt.Errorf("dial: %w", context.Canceled),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pool := newEndpointPool(Config{BaseURLs: []string{"http://phd-1", "http://phd-2"}, MaxEndpointFailures: 1},
+				roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					if test.err != nil {
+						return nil, test.err
+					}
+					return &http.Response{StatusCode: test.status, Body: http.NoBody}, nil
+				}))
+
+			req := httptest.NewRequest(http.MethodPost, "http://phd-1/v2/chain", nil)
+			_, _ = pool.send(pool.endpoints[0], req)
+
+			assert.Equal(t, test.expectedEjected, !pool.endpoints[0].isHealthy())
+		})
+	}
+}
+
+func Test_TrimBaseURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		reqURL       string
+		baseURL      string
+		expectedRest string
+		expectedOK   bool
+	}{
+		{
+			name:         "Should trim the base URL",
+			reqURL:       "http://phd-1/v2/portal_app/test%2Fapp?include_deleted=true",
+			baseURL:      "http://phd-1",
+			expectedRest: "/v2/portal_app/test%2Fapp?include_deleted=true",
+			expectedOK:   true,
+		},
+		{
+			name:         "Should trim a base URL with a path",
+			reqURL:       "https://gateway/phd/v2/chain",
+			baseURL:      "https://gateway/phd",
+			expectedRest: "/v2/chain",
+			expectedOK:   true,
+		},
+		{
+			name:    "Should not match a host sharing the base URL as a prefix",
+			reqURL:  "http://phd-10/v2/chain",
+			baseURL: "http://phd-1",
+		},
+		{
+			name:    "Should not match a path sharing the base path as a prefix",
+			reqURL:  "https://gateway/phd-2/v2/chain",
+			baseURL: "https://gateway/phd",
+		},
+		{
+			name:    "Should not match another scheme",
+			reqURL:  "https://phd-1/v2/chain",
+			baseURL: "http://phd-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reqURL, err := url.Parse(test.reqURL)
+			assert.NoError(t, err)
+			base, err := url.Parse(test.baseURL)
+			assert.NoError(t, err)
+
+			rest, ok := trimBaseURL(reqURL, base)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expectedRest, rest)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package dbclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound matches an APIError for a 404 Not Found response
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches an APIError for a 401 Unauthorized or 403 Forbidden response
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrConflict matches an APIError for a 409 Conflict or 412 Precondition Failed response, and a ConflictError
+	ErrConflict = errors.New("conflict")
+	// ErrServer matches an APIError for any 5xx response
+	ErrServer = errors.New("server error")
+)
+
+// APIError is returned whenever PHD responds with a non-200 status code.
+// Use errors.Is with ErrNotFound, ErrUnauthorized, ErrConflict or ErrServer to branch
+// on the kind of failure, or errors.As to inspect the full response.
+type APIError struct {
+	// StatusCode is the HTTP status code returned by PHD
+	StatusCode int
+	// Method is the HTTP method of the failed request
+	Method string
+	// Endpoint is the URL of the failed request
+	Endpoint string
+	// Message is the `error` field of the PHD response body, if present
+	Message string
+	// Body is the raw response body
+	Body []byte
+}
+
+// Error keeps the `Response not OK. {code} {status}: {message}` format of previous client versions
+func (e *APIError) Error() string {
+	errString := fmt.Sprintf("%s. %d %s", errResponseNotOK, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		errString = fmt.Sprintf("%s: %s", errString, e.Message)
+	}
+	return errString
+}
+
+// Is reports whether the APIError matches one of the status code sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case errResponseNotOK:
+		return true
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// ErrUnsupportedOption matches an UnsupportedOptionError
+var ErrUnsupportedOption = errors.New("unsupported option")
+
+// UnsupportedOptionError is returned when an options field is set that the operation can't send to PHD,
+// so that a filter never silently becomes a no-op
+type UnsupportedOptionError struct {
+	// Operation is the name of the client method, eg. `GetAllPortalApps`
+	Operation string
+	// Option is the name of the unsupported options field, eg. `RoleNameFilters`
+	Option string
+}
+
+func (e *UnsupportedOptionError) Error() string {
+	return fmt.Sprintf("%s: %s does not support the %s option", ErrUnsupportedOption, e.Operation, e.Option)
+}
+
+// Is reports whether the target is ErrUnsupportedOption
+func (e *UnsupportedOptionError) Is(target error) bool {
+	return target == ErrUnsupportedOption
+}
+
+// ErrCircuitOpen matches a CircuitOpenError
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitOpenError is returned without sending the request while the circuit breaker of the endpoint host is open
+type CircuitOpenError struct {
+	// Host is the endpoint host whose circuit is open
+	Host string
+	// RetryAt is when the circuit breaker lets a trial request through again
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %s until %s", ErrCircuitOpen, e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether the target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// ConflictError is returned by an update sent with WithIfMatch when the entity was updated since the expected
+// UpdatedAt. It matches ErrConflict and carries the current server copy of the entity.
+type ConflictError[T any] struct {
+	// Current is the server copy of the entity, nil if it couldn't be read
+	Current *T
+	// Err is the error returned by PHD
+	Err error
+}
+
+func (e *ConflictError[T]) Error() string {
+	return fmt.Sprintf("%s: entity was updated since it was read: %s", ErrConflict, e.Err)
+}
+
+// Is reports whether the target is ErrConflict
+func (e *ConflictError[T]) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Unwrap returns the error returned by PHD
+func (e *ConflictError[T]) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,102 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_APIError(t *testing.T) {
+	tests := []struct {
+		name             string
+		statusCode       int
+		body             string
+		expectedMessage  string
+		expectedErrorStr string
+		matches          []error
+		doesNotMatch     []error
+	}{
+		{
+			name:             "Should match ErrNotFound for a 404 response",
+			statusCode:       http.StatusNotFound,
+			body:             `{"error":"error in getChainByID: chain not found"}`,
+			expectedMessage:  "error in getChainByID: chain not found",
+			expectedErrorStr: "Response not OK. 404 Not Found: error in getChainByID: chain not found",
+			matches:          []error{ErrNotFound, errResponseNotOK},
+			doesNotMatch:     []error{ErrServer, ErrUnauthorized, ErrConflict},
+		},
+		{
+			name:             "Should match ErrUnauthorized for a 401 response",
+			statusCode:       http.StatusUnauthorized,
+			expectedErrorStr: "Response not OK. 401 Unauthorized",
+			matches:          []error{ErrUnauthorized},
+			doesNotMatch:     []error{ErrNotFound, ErrServer},
+		},
+		{
+			name:             "Should match ErrConflict for a 409 response",
+			statusCode:       http.StatusConflict,
+			body:             `{"error":"already exists"}`,
+			expectedMessage:  "already exists",
+			expectedErrorStr: "Response not OK. 409 Conflict: already exists",
+			matches:          []error{ErrConflict},
+			doesNotMatch:     []error{ErrNotFound, ErrServer},
+		},
+		{
+			name:             "Should match ErrConflict for a 412 response",
+			statusCode:       http.StatusPreconditionFailed,
+			body:             `{"error":"error in updateChain: entity was updated at 2023-05-01T12:00:00Z"}`,
+			expectedMessage:  "error in updateChain: entity was updated at 2023-05-01T12:00:00Z",
+			expectedErrorStr: "Response not OK. 412 Precondition Failed: error in updateChain: entity was updated at 2023-05-01T12:00:00Z",
+			matches:          []error{ErrConflict},
+			doesNotMatch:     []error{ErrNotFound, ErrServer},
+		},
+		{
+			name:             "Should match ErrServer for a 500 response with a non JSON body",
+			statusCode:       http.StatusInternalServerError,
+			body:             "upstream exploded",
+			expectedErrorStr: "Response not OK. 500 Internal Server Error",
+			matches:          []error{ErrServer},
+			doesNotMatch:     []error{ErrNotFound, ErrConflict},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			client, err := NewDBClient(Config{
+				BaseURL: server.URL,
+				APIKey:  "test_api_key_6789",
+				Timeout: 5 * time.Second,
+			})
+			assert.NoError(t, err)
+
+			_, err = client.GetChainByID(context.Background(), "0001")
+			assert.EqualError(t, err, test.expectedErrorStr)
+
+			for _, target := range test.matches {
+				assert.ErrorIs(t, err, target)
+			}
+			for _, target := range test.doesNotMatch {
+				assert.False(t, errors.Is(err, target))
+			}
+
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, test.statusCode, apiErr.StatusCode)
+			assert.Equal(t, http.MethodGet, apiErr.Method)
+			assert.Equal(t, server.URL+"/v2/chain/0001", apiErr.Endpoint)
+			assert.Equal(t, test.expectedMessage, apiErr.Message)
+			assert.Equal(t, test.body, string(apiErr.Body))
+		})
+	}
+}
@@ -0,0 +1,1527 @@
+package dbclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+type (
+	// FakeDBClientSeed contains the initial data loaded into a FakeDBClient
+	FakeDBClientSeed struct {
+		Chains           map[types.RelayChainID]*types.Chain
+		GigastakeApps    map[types.GigastakeAppID]*types.GigastakeApp
+		PortalApps       map[types.PortalAppID]*types.PortalApp
+		PortalAppUsers   map[types.PortalAppID]map[types.UserID]*types.AccountUserAccess
+		Accounts         map[types.AccountID]*types.Account
+		Users            map[types.UserID]*types.User
+		Plans            map[types.PayPlanType]*types.Plan
+		BlockedContracts types.GlobalBlockedContracts
+	}
+
+	// FakeDBClient is an in-memory IDBClient for unit tests that emulates the behaviour of PHD.
+	// It returns the same validation errors as the DBClient and *APIError values for server-side failures.
+	// All values are copied on the way in and out so callers can't mutate the stored data.
+	FakeDBClient struct {
+		mu sync.RWMutex
+
+		chains           map[types.RelayChainID]*types.Chain
+		gigastakeApps    map[types.GigastakeAppID]*types.GigastakeApp
+		portalApps       map[types.PortalAppID]*types.PortalApp
+		portalAppUsers   map[types.PortalAppID]map[types.UserID]*types.AccountUserAccess
+		accounts         map[types.AccountID]*types.Account
+		users            map[types.UserID]*types.User
+		plans            map[types.PayPlanType]*types.Plan
+		blockedContracts map[types.BlockedAddress]bool
+
+		deletedPortalApps map[types.PortalAppID]bool
+		deletedAccounts   map[types.AccountID]bool
+
+		lastID int
+	}
+)
+
+var (
+	_ IDBClient = &FakeDBClient{}
+	_ IDBLister = &FakeDBClient{}
+)
+
+// fakeAuthProviders are the auth provider types accepted by the FakeDBClient
+var fakeAuthProviders = map[types.AuthType]types.AuthProvider{
+	types.AuthTypeAuth0Username: types.AuthProviderAuth0,
+}
+
+// NewFakeDBClient returns a FakeDBClient loaded with a copy of the given seed
+func NewFakeDBClient(seed FakeDBClientSeed) *FakeDBClient {
+	seed = clone(seed)
+
+	db := &FakeDBClient{
+		chains:            nonNilMap(seed.Chains),
+		gigastakeApps:     nonNilMap(seed.GigastakeApps),
+		portalApps:        nonNilMap(seed.PortalApps),
+		portalAppUsers:    nonNilMap(seed.PortalAppUsers),
+		accounts:          nonNilMap(seed.Accounts),
+		users:             nonNilMap(seed.Users),
+		plans:             nonNilMap(seed.Plans),
+		blockedContracts:  make(map[types.BlockedAddress]bool),
+		deletedPortalApps: make(map[types.PortalAppID]bool),
+		deletedAccounts:   make(map[types.AccountID]bool),
+	}
+	for address := range seed.BlockedContracts.BlockedAddresses {
+		db.blockedContracts[address] = true
+	}
+
+	return db
+}
+
+/* ------------ IDBReader Methods ------------ */
+
+/* -- Chain Read Methods -- */
+
+// GetChainByID returns a single Chain by its relay chain ID
+func (db *FakeDBClient) GetChainByID(ctx context.Context, chainID types.RelayChainID) (*types.Chain, error) {
+	if chainID == "" {
+		return nil, errNoChainID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	chain, ok := db.chains[chainID]
+	if !ok {
+		return nil, fakeServerError(http.StatusNotFound, "getChainByID", "chain not found")
+	}
+
+	return db.chainWithGigastakeApps(chain), nil
+}
+
+// GetGigastakeAppByID returns a single GigastakeApp by its GigastakeAppID
+func (db *FakeDBClient) GetGigastakeAppByID(ctx context.Context, gigastakeAppID types.GigastakeAppID) (*types.GigastakeApp, error) {
+	if gigastakeAppID == "" {
+		return nil, errNoGigastakeAppID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	gigastakeApp, ok := db.gigastakeApps[gigastakeAppID]
+	if !ok {
+		return nil, fakeServerError(http.StatusNotFound, "getGigastakeAppByID", "gigastake app not found")
+	}
+
+	return clone(gigastakeApp), nil
+}
+
+// GetAllChains returns all chains, excluding inactive chains unless IncludeInactive is set
+func (db *FakeDBClient) GetAllChains(ctx context.Context, optionParams ...ChainOptions) ([]*types.Chain, error) {
+	if _, err := encodeOptions("GetAllChains", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := ChainOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	return db.listChains(options), nil
+}
+
+// listChains returns the page of chains selected by the options
+func (db *FakeDBClient) listChains(options ChainOptions) []*types.Chain {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	chains := make([]*types.Chain, 0, len(db.chains))
+	for _, chain := range db.chains {
+		if !chain.Active && !isSet(options.IncludeInactive) {
+			continue
+		}
+		if isSet(options.ExcludeGigastakeApps) {
+			chain = clone(chain)
+			chain.GigastakeApps = nil
+			chains = append(chains, chain)
+			continue
+		}
+		chains = append(chains, db.chainWithGigastakeApps(chain))
+	}
+
+	sort.Slice(chains, func(i, j int) bool { return chains[i].ID < chains[j].ID })
+
+	return paginate(chains, options.Limit, options.Cursor, chainCursor)
+}
+
+// ListChains returns an Iterator over all chains, fetched a page at a time
+func (db *FakeDBClient) ListChains(ctx context.Context, options ChainOptions) *Iterator[types.Chain] {
+	if _, err := encodeOptions("ListChains", []ChainOptions{options}); err != nil {
+		return errIterator[types.Chain](err)
+	}
+
+	return fakeList(options.Limit, options.Cursor, func(limit int, cursor string) ([]*types.Chain, error) {
+		options.Limit, options.Cursor = limit, cursor
+		return db.listChains(options), nil
+	}, chainCursor)
+}
+
+// GetAllGigastakeApps returns all GigastakeApps
+func (db *FakeDBClient) GetAllGigastakeApps(ctx context.Context, optionParams ...GigastakeAppOptions) ([]*types.GigastakeApp, error) {
+	if _, err := encodeOptions("GetAllGigastakeApps", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := GigastakeAppOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	return db.listGigastakeApps(options), nil
+}
+
+// listGigastakeApps returns the page of gigastakeApps selected by the options
+func (db *FakeDBClient) listGigastakeApps(options GigastakeAppOptions) []*types.GigastakeApp {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	gigastakeApps := db.filterGigastakeApps(func(*types.GigastakeApp) bool { return true })
+
+	return paginate(gigastakeApps, options.Limit, options.Cursor, gigastakeAppCursor)
+}
+
+// ListGigastakeApps returns an Iterator over all GigastakeApps, fetched a page at a time
+func (db *FakeDBClient) ListGigastakeApps(ctx context.Context, options GigastakeAppOptions) *Iterator[types.GigastakeApp] {
+	if _, err := encodeOptions("ListGigastakeApps", []GigastakeAppOptions{options}); err != nil {
+		return errIterator[types.GigastakeApp](err)
+	}
+
+	return fakeList(options.Limit, options.Cursor, func(limit int, cursor string) ([]*types.GigastakeApp, error) {
+		options.Limit, options.Cursor = limit, cursor
+		return db.listGigastakeApps(options), nil
+	}, gigastakeAppCursor)
+}
+
+// GetAllGigastakeAppsByChain returns all GigastakeApps for a single chain ID
+func (db *FakeDBClient) GetAllGigastakeAppsByChain(ctx context.Context, chainID types.RelayChainID) ([]*types.GigastakeApp, error) {
+	if chainID == "" {
+		return nil, errNoChainID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if _, ok := db.chains[chainID]; !ok {
+		return nil, fakeServerError(http.StatusBadRequest, "getAllGigastakeAppsByChain", "chain not found")
+	}
+
+	return db.filterGigastakeApps(func(gigastakeApp *types.GigastakeApp) bool {
+		_, ok := gigastakeApp.ChainIDs[chainID]
+		return ok
+	}), nil
+}
+
+/* -- Portal App Read Methods -- */
+
+// GetPortalAppByID returns a single Portal App by its ID
+func (db *FakeDBClient) GetPortalAppByID(ctx context.Context, portalAppID types.PortalAppID) (*types.PortalApp, error) {
+	if portalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if !db.portalAppExists(portalAppID) {
+		return nil, fakeServerError(http.StatusNotFound, "getPortalAppByID", "portal app not found")
+	}
+
+	return db.portalAppWithUsers(portalAppID), nil
+}
+
+// GetAllPortalApps returns all Portal Apps, including soft-deleted apps if IncludeDeleted is set
+func (db *FakeDBClient) GetAllPortalApps(ctx context.Context, optionParams ...PortalAppOptions) ([]*types.PortalApp, error) {
+	if _, err := encodeOptions("GetAllPortalApps", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := PortalAppOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	return db.listPortalApps(options), nil
+}
+
+// listPortalApps returns the page of portalApps selected by the options
+func (db *FakeDBClient) listPortalApps(options PortalAppOptions) []*types.PortalApp {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	portalApps := db.filterPortalApps(options.IncludeDeleted, func(*types.PortalApp) bool { return true })
+
+	return paginate(portalApps, options.Limit, options.Cursor, portalAppCursor)
+}
+
+// ListPortalApps returns an Iterator over all Portal Apps, fetched a page at a time
+func (db *FakeDBClient) ListPortalApps(ctx context.Context, options PortalAppOptions) *Iterator[types.PortalApp] {
+	if _, err := encodeOptions("ListPortalApps", []PortalAppOptions{options}); err != nil {
+		return errIterator[types.PortalApp](err)
+	}
+
+	return fakeList(options.Limit, options.Cursor, func(limit int, cursor string) ([]*types.PortalApp, error) {
+		options.Limit, options.Cursor = limit, cursor
+		return db.listPortalApps(options), nil
+	}, portalAppCursor)
+}
+
+// GetPortalAppsByUser returns all Portal Apps the user has access to, filtered by role and accepted status
+func (db *FakeDBClient) GetPortalAppsByUser(ctx context.Context, userID types.UserID, optionParams ...PortalAppOptions) ([]*types.PortalApp, error) {
+	if userID == "" {
+		return nil, errNoUserID
+	}
+	if _, err := encodeOptions("GetPortalAppsByUser", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := PortalAppOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.filterPortalApps(options.IncludeDeleted, func(portalApp *types.PortalApp) bool {
+		access, ok := db.portalAppUsers[portalApp.ID][userID]
+		if !ok {
+			return false
+		}
+		if len(options.RoleNameFilters) > 0 && !containsRole(options.RoleNameFilters, access.PortalAppRoles[portalApp.ID]) {
+			return false
+		}
+		if options.Accepted != nil && access.PortalAppsAccepted[portalApp.ID] != *options.Accepted {
+			return false
+		}
+		return true
+	}), nil
+}
+
+// GetPortalAppsForMiddleware returns the Portal App Lite of every Portal App
+func (db *FakeDBClient) GetPortalAppsForMiddleware(ctx context.Context) ([]*types.PortalAppLite, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	portalApps := db.filterPortalApps(nil, func(*types.PortalApp) bool { return true })
+
+	portalAppLites := make([]*types.PortalAppLite, len(portalApps))
+	for i, portalApp := range portalApps {
+		publicKeys := make([]types.PortalAppPublicKey, 0, len(portalApp.AATs))
+		for _, aat := range portalApp.AATs {
+			publicKeys = append(publicKeys, types.PortalAppPublicKey(aat.PublicKey))
+		}
+		sort.Slice(publicKeys, func(i, j int) bool { return publicKeys[i] < publicKeys[j] })
+
+		portalAppLites[i] = &types.PortalAppLite{ID: portalApp.ID, PublicKeys: publicKeys}
+	}
+
+	return portalAppLites, nil
+}
+
+/* -- Account Read Methods -- */
+
+// GetAllAccounts returns all Accounts, including soft-deleted accounts if IncludeDeleted is set
+func (db *FakeDBClient) GetAllAccounts(ctx context.Context, optionParams ...AccountOptions) ([]*types.Account, error) {
+	if _, err := encodeOptions("GetAllAccounts", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := AccountOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	return db.listAccounts(options), nil
+}
+
+// listAccounts returns the page of accounts selected by the options
+func (db *FakeDBClient) listAccounts(options AccountOptions) []*types.Account {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	accounts := db.filterAccounts(options.IncludeDeleted, func(*types.Account) bool { return true })
+
+	return paginate(accounts, options.Limit, options.Cursor, accountCursor)
+}
+
+// ListAccounts returns an Iterator over all Accounts, fetched a page at a time
+func (db *FakeDBClient) ListAccounts(ctx context.Context, options AccountOptions) *Iterator[types.Account] {
+	if _, err := encodeOptions("ListAccounts", []AccountOptions{options}); err != nil {
+		return errIterator[types.Account](err)
+	}
+
+	return fakeList(options.Limit, options.Cursor, func(limit int, cursor string) ([]*types.Account, error) {
+		options.Limit, options.Cursor = limit, cursor
+		return db.listAccounts(options), nil
+	}, accountCursor)
+}
+
+// GetUserAccounts returns all Accounts the user is a member of, filtered by role and accepted status
+func (db *FakeDBClient) GetUserAccounts(ctx context.Context, userID types.UserID, optionParams ...AccountOptions) ([]*types.Account, error) {
+	if userID == "" {
+		return nil, errNoUserID
+	}
+	if _, err := encodeOptions("GetUserAccounts", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := AccountOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	accounts := db.filterAccounts(options.IncludeDeleted, accountUserFilter(userID, options))
+	if len(accounts) == 0 {
+		return nil, fakeServerError(http.StatusNotFound, "getUserAccounts", "no accounts were found for user ID")
+	}
+
+	return accounts, nil
+}
+
+// GetUserAccount returns a single Account if the user is a member of it
+func (db *FakeDBClient) GetUserAccount(ctx context.Context, accountID types.AccountID, userID types.UserID, optionParams ...AccountOptions) (*types.Account, error) {
+	if accountID == "" {
+		return nil, errNoAccountID
+	}
+	if userID == "" {
+		return nil, errNoUserID
+	}
+	if _, err := encodeOptions("GetUserAccount", optionParams); err != nil {
+		return nil, err
+	}
+
+	options := AccountOptions{}
+	if len(optionParams) > 0 {
+		options = optionParams[0]
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	filter := accountUserFilter(userID, options)
+	accounts := db.filterAccounts(options.IncludeDeleted, func(account *types.Account) bool {
+		return account.ID == accountID && filter(account)
+	})
+	if len(accounts) == 0 {
+		return nil, fakeServerError(http.StatusNotFound, "getUserAccount", "account not found")
+	}
+
+	return accounts[0], nil
+}
+
+/* -- User Read Methods -- */
+
+// GetPortalUser returns the Portal User for a given user ID, either provider ID or portal ID
+func (db *FakeDBClient) GetPortalUser(ctx context.Context, userID string) (*types.User, error) {
+	if userID == "" {
+		return &types.User{}, errNoUserID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	user, ok := db.findUser(userID)
+	if !ok {
+		return nil, fakeServerError(http.StatusNotFound, "getPortalUser", "user not found for ID: %s", userID)
+	}
+
+	return clone(user), nil
+}
+
+// GetPortalUserID returns the Portal User ID for a given user ID, either provider ID or portal ID
+func (db *FakeDBClient) GetPortalUserID(ctx context.Context, userID string) (types.UserID, error) {
+	if userID == "" {
+		return types.UserID(""), errNoUserID
+	}
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	user, ok := db.findUser(userID)
+	if !ok {
+		return types.UserID(""), fakeServerError(http.StatusNotFound, "getPortalUserID", "user not found for ID: %s", userID)
+	}
+
+	return user.ID, nil
+}
+
+/* -- Plans Read Methods -- */
+
+// GetAllPlans returns all plans
+func (db *FakeDBClient) GetAllPlans(ctx context.Context) ([]types.Plan, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	plans := make([]types.Plan, 0, len(db.plans))
+	for _, plan := range db.plans {
+		plans = append(plans, *clone(plan))
+	}
+
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Type < plans[j].Type })
+
+	return plans, nil
+}
+
+/* -- Blocked Contracts Read Methods -- */
+
+// GetBlockedContracts returns all active blocked contracts
+func (db *FakeDBClient) GetBlockedContracts(ctx context.Context) (types.GlobalBlockedContracts, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	blockedContracts := types.GlobalBlockedContracts{BlockedAddresses: make(map[types.BlockedAddress]struct{})}
+	for address, active := range db.blockedContracts {
+		if active {
+			blockedContracts.BlockedAddresses[address] = struct{}{}
+		}
+	}
+
+	return blockedContracts, nil
+}
+
+/* ------------ IDBWriter Methods ------------ */
+
+/* -- Chain Write Methods -- */
+
+// CreateChainAndGigastakeApps creates a new blockchain and its Gigastake apps
+func (db *FakeDBClient) CreateChainAndGigastakeApps(ctx context.Context, newChainInput types.NewChainInput) (*types.NewChainInput, error) {
+	if newChainInput.Chain == nil {
+		return nil, fakeServerError(http.StatusInternalServerError, "createNewChainAndGigastakeApps", "error chain cannot be nil")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	created := clone(&newChainInput)
+	now := time.Now()
+
+	created.Chain.CreatedAt, created.Chain.UpdatedAt = now, now
+	created.Chain.GigastakeApps = nil
+	db.chains[created.Chain.ID] = clone(created.Chain)
+
+	for _, gigastakeApp := range created.GigastakeApps {
+		if gigastakeApp.ID == "" {
+			gigastakeApp.ID = types.GigastakeAppID(db.nextID("gigastake_app"))
+		}
+		if gigastakeApp.ChainIDs == nil {
+			gigastakeApp.ChainIDs = make(map[types.RelayChainID]struct{})
+		}
+		gigastakeApp.ChainIDs[created.Chain.ID] = struct{}{}
+		gigastakeApp.CreatedAt, gigastakeApp.UpdatedAt = now, now
+		db.gigastakeApps[gigastakeApp.ID] = clone(gigastakeApp)
+	}
+
+	return created, nil
+}
+
+// CreateGigastakeApp creates a new Gigastake app for one or more existing chains
+func (db *FakeDBClient) CreateGigastakeApp(ctx context.Context, gigastakeAppInput types.GigastakeApp) (*types.GigastakeApp, error) {
+	if gigastakeAppInput.Name == "" {
+		return nil, fakeServerError(http.StatusInternalServerError, "createNewGigastakeApp", "gigastake app name cannot be empty")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for chainID := range gigastakeAppInput.ChainIDs {
+		if _, ok := db.chains[chainID]; !ok {
+			return nil, fakeServerError(http.StatusInternalServerError, "createNewGigastakeApp", "error chain does not exist for chain ID '%s'", chainID)
+		}
+	}
+
+	created := clone(&gigastakeAppInput)
+	if created.ID == "" {
+		created.ID = types.GigastakeAppID(db.nextID("gigastake_app"))
+	}
+	created.CreatedAt, created.UpdatedAt = time.Now(), time.Now()
+	db.gigastakeApps[created.ID] = clone(created)
+
+	return created, nil
+}
+
+// UpdateChain updates the set fields of an existing blockchain
+func (db *FakeDBClient) UpdateChain(ctx context.Context, chainUpdate types.UpdateChain) (*types.Chain, error) {
+	if chainUpdate.ID == "" {
+		return nil, errNoChainID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	chain, ok := db.chains[chainUpdate.ID]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateChain", "error chain does not exist for chain ID '%s'", chainUpdate.ID)
+	}
+	if err := checkIfMatch(ctx, "updateChain", chain.UpdatedAt); err != nil {
+		return nil, &ConflictError[types.Chain]{Current: db.chainWithGigastakeApps(chain), Err: err}
+	}
+
+	if err := mergeUpdate(chain, chainUpdate, "id"); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidChainJSON, err)
+	}
+	chain.UpdatedAt = time.Now()
+
+	return db.chainWithGigastakeApps(chain), nil
+}
+
+// UpdateGigastakeApp replaces the name and chain IDs of a Gigastake app
+func (db *FakeDBClient) UpdateGigastakeApp(ctx context.Context, id types.GigastakeAppID, updateGigastakeApp types.UpdateGigastakeApp) (*types.UpdateGigastakeApp, error) {
+	if id == "" {
+		return nil, errNoGigastakeAppID
+	}
+	if updateGigastakeApp.Name == "" {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateGigastakeApp", "gigastake app name cannot be empty")
+	}
+	if len(updateGigastakeApp.ChainIDs) == 0 {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateGigastakeApp", "chainIDs cannot be empty for gigastake app update")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	gigastakeApp, ok := db.gigastakeApps[id]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateGigastakeApp", "error gigastake app does not exist for ID '%s'", id)
+	}
+
+	chainIDs := make(map[types.RelayChainID]struct{}, len(updateGigastakeApp.ChainIDs))
+	for _, chainID := range updateGigastakeApp.ChainIDs {
+		if _, ok := db.chains[chainID]; !ok {
+			return nil, fakeServerError(http.StatusInternalServerError, "updateGigastakeApp", "error chain does not exist for chain ID '%s'", chainID)
+		}
+		chainIDs[chainID] = struct{}{}
+	}
+
+	gigastakeApp.Name = updateGigastakeApp.Name
+	gigastakeApp.ChainIDs = chainIDs
+	gigastakeApp.UpdatedAt = time.Now()
+
+	updated := clone(&updateGigastakeApp)
+	updated.ID = id
+
+	return updated, nil
+}
+
+// ActivateChain activates or deactivates a blockchain by ID
+func (db *FakeDBClient) ActivateChain(ctx context.Context, chainID types.RelayChainID, active bool) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	chain, ok := db.chains[chainID]
+	if !ok {
+		return false, fakeServerError(http.StatusInternalServerError, "activateChain", "error chain does not exist for chain ID '%s'", chainID)
+	}
+
+	chain.Active = active
+	chain.UpdatedAt = time.Now()
+
+	return active, nil
+}
+
+/* -- Portal App Write Methods -- */
+
+// CreatePortalApp creates a new Portal App for an existing account
+func (db *FakeDBClient) CreatePortalApp(ctx context.Context, portalAppInput types.PortalApp) (*types.PortalApp, error) {
+	if portalAppInput.Name == "" {
+		return nil, fakeServerError(http.StatusInternalServerError, "createNewPortalApp", "portal app name cannot be empty")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.accountExists(portalAppInput.AccountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "createNewPortalApp", "error account does not exist for account ID '%s'", portalAppInput.AccountID)
+	}
+	if planType := portalAppInput.LegacyFields.PlanType; planType != "" && db.plans[planType] == nil {
+		return nil, fakeServerError(http.StatusInternalServerError, "createNewPortalApp", "error pay plan '%s' does not exist", planType)
+	}
+
+	created := clone(&portalAppInput)
+	created.ID = types.PortalAppID(db.nextID("portal_app"))
+	created.Users = nil
+	created.CreatedAt, created.UpdatedAt = time.Now(), time.Now()
+	db.portalApps[created.ID] = clone(created)
+
+	return created, nil
+}
+
+// UpdatePortalApp updates the set fields of an existing Portal App
+func (db *FakeDBClient) UpdatePortalApp(ctx context.Context, portalAppUpdate types.UpdatePortalApp) (*types.UpdatePortalApp, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.portalAppExists(portalAppUpdate.AppID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "updatePortalApp", "error portal app does not exist for ID '%s'", portalAppUpdate.AppID)
+	}
+	if planType := portalAppUpdate.PlanType; planType != "" && db.plans[planType] == nil {
+		return nil, fakeServerError(http.StatusInternalServerError, "updatePortalApp", "error pay plan '%s' does not exist", planType)
+	}
+
+	portalApp := db.portalApps[portalAppUpdate.AppID]
+	if err := checkIfMatch(ctx, "updatePortalApp", portalApp.UpdatedAt); err != nil {
+		return nil, &ConflictError[types.PortalApp]{Current: db.portalAppWithUsers(portalAppUpdate.AppID), Err: err}
+	}
+	if err := mergeUpdate(portalApp, portalAppUpdate, "appID", "planType"); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidPortalAppJSON, err)
+	}
+	if portalAppUpdate.PlanType != "" {
+		portalApp.LegacyFields.PlanType = portalAppUpdate.PlanType
+	}
+	portalApp.UpdatedAt = time.Now()
+
+	return clone(&portalAppUpdate), nil
+}
+
+// DeletePortalApp soft-deletes a Portal App
+func (db *FakeDBClient) DeletePortalApp(ctx context.Context, portalAppID types.PortalAppID) (map[string]string, error) {
+	if portalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.portalAppExists(portalAppID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "deletePortalApp", "error portal app does not exist for ID '%s'", portalAppID)
+	}
+
+	db.deletedPortalApps[portalAppID] = true
+
+	return map[string]string{"status": "deleted"}, nil
+}
+
+// UpdatePortalAppsFirstDateSurpassed updates the FirstDateSurpassed field of one or more Portal Apps
+func (db *FakeDBClient) UpdatePortalAppsFirstDateSurpassed(ctx context.Context, firstDateSurpassedUpdate types.UpdateFirstDateSurpassed) (map[string]string, error) {
+	if len(firstDateSurpassedUpdate.PortalAppIDs) == 0 {
+		return nil, &APIError{StatusCode: http.StatusBadRequest}
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	for _, portalAppID := range firstDateSurpassedUpdate.PortalAppIDs {
+		if !db.portalAppExists(portalAppID) {
+			return nil, fakeServerError(http.StatusInternalServerError, "updatePortalAppsFirstDateSurpassed", "error portal app does not exist for ID '%s'", portalAppID)
+		}
+	}
+	for _, portalAppID := range firstDateSurpassedUpdate.PortalAppIDs {
+		db.portalApps[portalAppID].FirstDateSurpassed = firstDateSurpassedUpdate.FirstDateSurpassed
+	}
+
+	return map[string]string{"status": "updated"}, nil
+}
+
+/* -- Account Write Methods -- */
+
+// CreateAccount creates a new Account owned by the given user
+func (db *FakeDBClient) CreateAccount(ctx context.Context, userID types.UserID, account types.Account, timestamp time.Time) (*types.Account, error) {
+	if userID == "" {
+		return nil, errNoUserID
+	}
+	if account.PlanType == "" {
+		return nil, errNoPlanTypeSet
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	user, ok := db.users[userID]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "createAccount", "error user does not exist for portal ID '%s'", userID)
+	}
+	if db.plans[account.PlanType] == nil {
+		return nil, fakeServerError(http.StatusInternalServerError, "createAccount", "error pay plan '%s' does not exist", account.PlanType)
+	}
+
+	created := db.createAccount(user, account, timestamp)
+
+	return db.accountWithRelations(created), nil
+}
+
+// UpdateAccount updates the name and plan of an existing Account
+func (db *FakeDBClient) UpdateAccount(ctx context.Context, account types.UpdateAccount) (*types.Account, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.accountExists(account.AccountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateAccount", "error account does not exist for account ID '%s'", account.AccountID)
+	}
+	if account.PlanType != "" && db.plans[account.PlanType] == nil {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateAccount", "error pay plan '%s' does not exist", account.PlanType)
+	}
+
+	existing := db.accounts[account.AccountID]
+	if err := checkIfMatch(ctx, "updateAccount", existing.UpdatedAt); err != nil {
+		return nil, &ConflictError[types.Account]{Err: err}
+	}
+	if err := mergeUpdate(existing, account, "accountID"); err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidAccountJSON, err)
+	}
+	existing.UpdatedAt = time.Now()
+
+	return db.accountWithRelations(existing), nil
+}
+
+// CreateAccountIntegration sets the integrations of an existing Account
+func (db *FakeDBClient) CreateAccountIntegration(ctx context.Context, accountID types.AccountID, integration types.AccountIntegrations) (*types.AccountIntegrations, error) {
+	return db.setAccountIntegration("createAccountIntegration", accountID, integration)
+}
+
+// UpdateAccountIntegration sets the integrations of an existing Account
+func (db *FakeDBClient) UpdateAccountIntegration(ctx context.Context, accountID types.AccountID, integration types.AccountIntegrations) (*types.AccountIntegrations, error) {
+	return db.setAccountIntegration("updateAccountIntegration", accountID, integration)
+}
+
+// DeleteAccount soft-deletes an Account
+func (db *FakeDBClient) DeleteAccount(ctx context.Context, accountID types.AccountID) (map[string]string, error) {
+	if accountID == "" {
+		return nil, errNoAccountID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.accountExists(accountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "deleteAccount", "error account does not exist for account ID '%s'", accountID)
+	}
+
+	db.deletedAccounts[accountID] = true
+
+	return map[string]string{"status": "deleted"}, nil
+}
+
+/* -- Account User Write Methods -- */
+
+// WriteAccountUser invites a user by email to a Portal App, creating the user if they haven't signed up yet
+func (db *FakeDBClient) WriteAccountUser(ctx context.Context, createUser types.CreateAccountUserAccess, time time.Time) (map[string]types.UserID, error) {
+	if createUser.AccountID == "" {
+		return nil, errNoAccountID
+	}
+	if createUser.PortalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+	if createUser.Email == "" {
+		return nil, errNoEmail
+	}
+	if createUser.RoleName == "" {
+		return nil, errNoRoleName
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.accountExists(createUser.AccountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeAccountUser", "error account does not exist for account ID '%s'", createUser.AccountID)
+	}
+	if !db.portalAppExists(createUser.PortalAppID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeAccountUser", "error portal app does not exist for ID '%s'", createUser.PortalAppID)
+	}
+	if !isValidEmail(createUser.Email) {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeAccountUser", "error email input is not a valid email address '%s'", createUser.Email)
+	}
+	if !createUser.RoleName.IsValid() {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeAccountUser", "error invalid role name set")
+	}
+
+	user := db.findUserByEmail(createUser.Email)
+	if user == nil {
+		user = &types.User{ID: types.UserID(db.nextID("user")), Email: createUser.Email, CreatedAt: time, UpdatedAt: time}
+		db.users[user.ID] = user
+	}
+
+	db.setAccountUserRole(createUser.AccountID, createUser.PortalAppID, user, createUser.RoleName)
+
+	return map[string]types.UserID{"userID": user.ID}, nil
+}
+
+// SetAccountUserRole updates the role of an Account User for a Portal App, transferring ownership if the role is OWNER
+func (db *FakeDBClient) SetAccountUserRole(ctx context.Context, updateUser types.UpdateAccountUserRole, time time.Time) (map[string]string, error) {
+	if updateUser.PortalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+	if updateUser.UserID == "" {
+		return nil, errNoUserID
+	}
+	if updateUser.AccountID == "" {
+		return nil, errNoAccountID
+	}
+	if updateUser.RoleName == "" {
+		return nil, errNoRoleName
+	}
+
+	if !updateUser.RoleName.IsValid() {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateAccountUserRole", "error invalid role name set")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.portalAppExists(updateUser.PortalAppID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateAccountUserRole", "error portal app does not exist for ID '%s'", updateUser.PortalAppID)
+	}
+	access, ok := db.portalAppUsers[updateUser.PortalAppID][updateUser.UserID]
+	if !ok || !db.accountExists(updateUser.AccountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateAccountUserRole", "error user ID '%s' does not exist for portal app ID '%s'", updateUser.UserID, updateUser.AccountID)
+	}
+
+	if updateUser.RoleName == types.RoleOwner {
+		if !access.PortalAppsAccepted[updateUser.PortalAppID] {
+			return nil, fakeServerError(http.StatusInternalServerError, "updateAccountUserRole",
+				"error cannot transfer ownership to user ID '%s' for account ID '%s' because the user has not accepted their invite", updateUser.UserID, updateUser.AccountID)
+		}
+
+		// Only one owner is allowed, so the previous owner becomes an admin
+		for userID, otherAccess := range db.portalAppUsers[updateUser.PortalAppID] {
+			if userID != updateUser.UserID && otherAccess.PortalAppRoles[updateUser.PortalAppID] == types.RoleOwner {
+				db.setAccountUserRole(updateUser.AccountID, updateUser.PortalAppID, db.users[userID], types.RoleAdmin)
+			}
+		}
+	}
+
+	db.setAccountUserRole(updateUser.AccountID, updateUser.PortalAppID, db.users[updateUser.UserID], updateUser.RoleName)
+
+	return map[string]string{"status": "updated"}, nil
+}
+
+// UpdateAcceptAccountUser accepts a user's invite to a Portal App and links their auth provider
+func (db *FakeDBClient) UpdateAcceptAccountUser(ctx context.Context, acceptUser types.UpdateAcceptAccountUser, time time.Time) (map[string]string, error) {
+	if acceptUser.PortalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+	if acceptUser.UserID == "" {
+		return nil, errNoUserID
+	}
+	if acceptUser.AuthProviderType == "" {
+		return nil, errNoAuthProviderType
+	}
+	if acceptUser.ProviderUserID == "" {
+		return nil, errNoProviderUserID
+	}
+
+	provider, ok := fakeAuthProviders[acceptUser.AuthProviderType]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "acceptAccountUser", "error invalid auth provider type '%s'", acceptUser.AuthProviderType)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	user, ok := db.users[acceptUser.UserID]
+	if _, isAppUser := db.portalAppUsers[acceptUser.PortalAppID][acceptUser.UserID]; !ok || !isAppUser {
+		return nil, fakeServerError(http.StatusInternalServerError, "acceptAccountUser", "error user ID '%s' does not exist for portal app ID '%s'", acceptUser.UserID, acceptUser.PortalAppID)
+	}
+
+	if user.AuthProviders == nil {
+		user.AuthProviders = make(map[types.AuthType]types.UserAuthProvider)
+	}
+	user.AuthProviders[acceptUser.AuthProviderType] = types.UserAuthProvider{
+		Type:           acceptUser.AuthProviderType,
+		ProviderUserID: acceptUser.ProviderUserID,
+		Provider:       provider,
+	}
+	user.SignedUp = true
+	user.UpdatedAt = time
+
+	db.updateAccountUserAccess(acceptUser.PortalAppID, acceptUser.UserID, func(access *types.AccountUserAccess) {
+		access.PortalAppsAccepted[acceptUser.PortalAppID] = true
+	})
+
+	return map[string]string{"status": "updated"}, nil
+}
+
+// RemoveAccountUser removes an Account User's access to a Portal App
+func (db *FakeDBClient) RemoveAccountUser(ctx context.Context, removeUser types.UpdateRemoveAccountUser) (map[string]string, error) {
+	if removeUser.PortalAppID == "" {
+		return nil, errNoPortalAppID
+	}
+	if removeUser.UserID == "" {
+		return nil, errNoUserID
+	}
+	if removeUser.AccountID == "" {
+		return nil, errNoAccountID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	access, ok := db.portalAppUsers[removeUser.PortalAppID][removeUser.UserID]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "removeAccountUser", "error user ID '%s' does not exist for portal app ID '%s'", removeUser.UserID, removeUser.PortalAppID)
+	}
+	if access.PortalAppRoles[removeUser.PortalAppID] == types.RoleOwner {
+		return nil, fakeServerError(http.StatusInternalServerError, "removeAccountUser",
+			"error cannot delete user ID '%s' for account ID '%s' because this user is the current account owner", removeUser.UserID, removeUser.AccountID)
+	}
+
+	delete(db.portalAppUsers[removeUser.PortalAppID], removeUser.UserID)
+
+	if account, ok := db.accounts[removeUser.AccountID]; ok {
+		if accountAccess, ok := account.Users[removeUser.UserID]; ok {
+			delete(accountAccess.PortalAppRoles, removeUser.PortalAppID)
+			delete(accountAccess.PortalAppsAccepted, removeUser.PortalAppID)
+			if len(accountAccess.PortalAppRoles) == 0 && !accountAccess.Owner {
+				delete(account.Users, removeUser.UserID)
+			} else {
+				account.Users[removeUser.UserID] = accountAccess
+			}
+		}
+	}
+
+	return map[string]string{"status": "updated"}, nil
+}
+
+/* -- User Write Methods -- */
+
+// CreateUser creates a new signed up User and a personal Account for them
+func (db *FakeDBClient) CreateUser(ctx context.Context, user types.CreateUser) (*types.CreateUserResponse, error) {
+	if user.Email == "" {
+		return nil, errNoEmail
+	}
+	if !isValidEmail(user.Email) {
+		return nil, fakeServerError(http.StatusInternalServerError, "createUser", "error email input is not a valid email address '%s'", user.Email)
+	}
+
+	providerType, _, _ := strings.Cut(string(user.ProviderUserID), "|")
+	authType, provider, ok := fakeAuthProviderForPrefix(providerType)
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "createUser", "error invalid auth provider type '%s'", providerType)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := time.Now()
+
+	// Users invited by email before signing up already have a user ID
+	created := db.findUserByEmail(user.Email)
+	if created == nil {
+		created = &types.User{ID: types.UserID(db.nextID("user")), Email: user.Email, CreatedAt: now}
+		db.users[created.ID] = created
+	}
+	created.SignedUp = true
+	created.UpdatedAt = now
+	created.AuthProviders = map[types.AuthType]types.UserAuthProvider{
+		authType: {Type: authType, ProviderUserID: user.ProviderUserID, Provider: provider},
+	}
+
+	account := db.createAccount(created, types.Account{PlanType: types.FreetierV0}, now)
+
+	return &types.CreateUserResponse{User: *clone(created), AccountID: account.ID}, nil
+}
+
+// UpdateUser updates the set fields of an existing User
+func (db *FakeDBClient) UpdateUser(ctx context.Context, user types.UpdateUser) (*types.User, error) {
+	if user.ID == "" {
+		return nil, errNoUserID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	existing, ok := db.users[user.ID]
+	if !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateUser", "error user does not exist for portal ID '%s'", user.ID)
+	}
+	if err := checkIfMatch(ctx, "updateUser", existing.UpdatedAt); err != nil {
+		return nil, &ConflictError[types.User]{Current: clone(existing), Err: err}
+	}
+
+	if err := mergeUpdate(existing, user, "id"); err != nil {
+		return nil, fmt.Errorf("invalid update user JSON: %w", err)
+	}
+	existing.UpdatedAt = time.Now()
+
+	return clone(existing), nil
+}
+
+// DeleteUser deletes a User that is not a member of any Account
+func (db *FakeDBClient) DeleteUser(ctx context.Context, userID types.UserID) (map[string]string, error) {
+	if userID == "" {
+		return nil, errNoUserID
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.users[userID]; !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "deleteUser", "error user does not exist for portal ID '%s'", userID)
+	}
+	for accountID, account := range db.accounts {
+		if _, ok := account.Users[userID]; ok && !db.deletedAccounts[accountID] {
+			return nil, fakeServerError(http.StatusInternalServerError, "deleteUser", "error cannot delete user because they are still on an account team")
+		}
+	}
+
+	delete(db.users, userID)
+
+	return map[string]string{"status": "deleted"}, nil
+}
+
+/* -- Blocked Contracts Write Methods -- */
+
+// WriteBlockedContract adds a new blocked address to the global blocked contracts
+func (db *FakeDBClient) WriteBlockedContract(ctx context.Context, blockedContract types.BlockedContract) (map[string]string, error) {
+	if blockedContract.BlockedAddress == "" {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeBlockedContract", "error blockchain address must be provided")
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.blockedContracts[blockedContract.BlockedAddress]; ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "writeBlockedContract", "error blockchain address %s is already blocked", blockedContract.BlockedAddress)
+	}
+
+	db.blockedContracts[blockedContract.BlockedAddress] = blockedContract.Active
+
+	return map[string]string{"status": "created"}, nil
+}
+
+// UpdateBlockedContractActive updates the active status of a blocked contract
+func (db *FakeDBClient) UpdateBlockedContractActive(ctx context.Context, address types.BlockedAddress, isActive bool) (map[string]bool, error) {
+	if address == "" {
+		return nil, errNoBlockedAddress
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.blockedContracts[address]; !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "updateBlockedContractActive", "error blockchain address %s does not exist", address)
+	}
+
+	db.blockedContracts[address] = isActive
+
+	return map[string]bool{"active": isActive}, nil
+}
+
+// RemoveBlockedContract deletes a blocked address from the global blocked contracts
+func (db *FakeDBClient) RemoveBlockedContract(ctx context.Context, address types.BlockedAddress) (map[string]string, error) {
+	if address == "" {
+		return nil, errNoBlockedAddress
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.blockedContracts[address]; !ok {
+		return nil, fakeServerError(http.StatusInternalServerError, "removeBlockedContract", "error blockchain address %s does not exist", address)
+	}
+
+	delete(db.blockedContracts, address)
+
+	return map[string]string{"status": "deleted"}, nil
+}
+
+/* ------------ Fake DB Client Helpers ------------ */
+
+// The helpers below must be called with db.mu held
+
+func (db *FakeDBClient) nextID(prefix string) string {
+	db.lastID++
+	return fmt.Sprintf("fake_%s_%d", prefix, db.lastID)
+}
+
+func (db *FakeDBClient) portalAppExists(portalAppID types.PortalAppID) bool {
+	_, ok := db.portalApps[portalAppID]
+	return ok && !db.deletedPortalApps[portalAppID]
+}
+
+func (db *FakeDBClient) accountExists(accountID types.AccountID) bool {
+	_, ok := db.accounts[accountID]
+	return ok && !db.deletedAccounts[accountID]
+}
+
+// chainWithGigastakeApps returns a copy of the chain with the Gigastake apps that include it
+func (db *FakeDBClient) chainWithGigastakeApps(chain *types.Chain) *types.Chain {
+	chain = clone(chain)
+	chain.GigastakeApps = make(map[types.GigastakeAppID]*types.GigastakeApp)
+	for id, gigastakeApp := range db.gigastakeApps {
+		if _, ok := gigastakeApp.ChainIDs[chain.ID]; ok {
+			chain.GigastakeApps[id] = clone(gigastakeApp)
+		}
+	}
+	return chain
+}
+
+func (db *FakeDBClient) filterGigastakeApps(include func(*types.GigastakeApp) bool) []*types.GigastakeApp {
+	gigastakeApps := make([]*types.GigastakeApp, 0)
+	for _, gigastakeApp := range db.gigastakeApps {
+		if include(gigastakeApp) {
+			gigastakeApps = append(gigastakeApps, clone(gigastakeApp))
+		}
+	}
+
+	sort.Slice(gigastakeApps, func(i, j int) bool { return gigastakeApps[i].ID < gigastakeApps[j].ID })
+
+	return gigastakeApps
+}
+
+// portalAppWithUsers returns a copy of the Portal App with its users attached
+func (db *FakeDBClient) portalAppWithUsers(portalAppID types.PortalAppID) *types.PortalApp {
+	portalApp := clone(db.portalApps[portalAppID])
+	if users, ok := db.portalAppUsers[portalAppID]; ok {
+		portalApp.Users = clone(users)
+	}
+	return portalApp
+}
+
+func (db *FakeDBClient) filterPortalApps(includeDeleted *bool, include func(*types.PortalApp) bool) []*types.PortalApp {
+	portalApps := make([]*types.PortalApp, 0)
+	for id, portalApp := range db.portalApps {
+		if db.deletedPortalApps[id] && !isSet(includeDeleted) {
+			continue
+		}
+		if include(portalApp) {
+			portalApps = append(portalApps, db.portalAppWithUsers(id))
+		}
+	}
+
+	sort.Slice(portalApps, func(i, j int) bool { return portalApps[i].ID < portalApps[j].ID })
+
+	return portalApps
+}
+
+// accountWithRelations returns a copy of the Account with its plan and Portal Apps attached
+func (db *FakeDBClient) accountWithRelations(account *types.Account) *types.Account {
+	account = clone(account)
+	account.Plan = clone(db.plans[account.PlanType])
+	account.PortalApps = make(map[types.PortalAppID]*types.PortalApp)
+	for id, portalApp := range db.portalApps {
+		if portalApp.AccountID == account.ID && !db.deletedPortalApps[id] {
+			account.PortalApps[id] = db.portalAppWithUsers(id)
+		}
+	}
+	return account
+}
+
+func (db *FakeDBClient) filterAccounts(includeDeleted *bool, include func(*types.Account) bool) []*types.Account {
+	accounts := make([]*types.Account, 0)
+	for id, account := range db.accounts {
+		if db.deletedAccounts[id] && !isSet(includeDeleted) {
+			continue
+		}
+		if include(account) {
+			accounts = append(accounts, db.accountWithRelations(account))
+		}
+	}
+
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+
+	return accounts
+}
+
+func (db *FakeDBClient) createAccount(owner *types.User, account types.Account, timestamp time.Time) *types.Account {
+	created := clone(&account)
+	if created.ID == "" {
+		created.ID = types.AccountID(db.nextID("account"))
+	}
+	created.Plan = nil
+	created.PortalApps = nil
+	created.CreatedAt, created.UpdatedAt = timestamp, timestamp
+	created.Users = map[types.UserID]types.AccountUserAccess{
+		owner.ID: {
+			UserID:             owner.ID,
+			Email:              owner.Email,
+			Owner:              true,
+			IconURL:            owner.IconURL,
+			UpdatesProduct:     owner.UpdatesProduct,
+			UpdatesMarketing:   owner.UpdatesMarketing,
+			BetaTester:         owner.BetaTester,
+			PortalAppRoles:     map[types.PortalAppID]types.RoleName{},
+			PortalAppsAccepted: map[types.PortalAppID]bool{},
+		},
+	}
+	db.accounts[created.ID] = created
+
+	return created
+}
+
+func (db *FakeDBClient) setAccountIntegration(operation string, accountID types.AccountID, integration types.AccountIntegrations) (*types.AccountIntegrations, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if !db.accountExists(accountID) {
+		return nil, fakeServerError(http.StatusInternalServerError, operation, "error account does not exist for account ID '%s'", accountID)
+	}
+
+	integration.AccountID = accountID
+	db.accounts[accountID].Integrations = integration
+
+	return &integration, nil
+}
+
+// setAccountUserRole gives the user a role for the Portal App, adding them to the Account if needed.
+// Newly invited users have not accepted their invite yet.
+func (db *FakeDBClient) setAccountUserRole(accountID types.AccountID, portalAppID types.PortalAppID, user *types.User, roleName types.RoleName) {
+	if _, ok := db.portalAppUsers[portalAppID]; !ok {
+		db.portalAppUsers[portalAppID] = make(map[types.UserID]*types.AccountUserAccess)
+	}
+	if _, ok := db.portalAppUsers[portalAppID][user.ID]; !ok {
+		db.portalAppUsers[portalAppID][user.ID] = &types.AccountUserAccess{
+			AccountID:          accountID,
+			UserID:             user.ID,
+			Email:              user.Email,
+			IconURL:            user.IconURL,
+			UpdatesProduct:     user.UpdatesProduct,
+			UpdatesMarketing:   user.UpdatesMarketing,
+			BetaTester:         user.BetaTester,
+			PortalAppRoles:     map[types.PortalAppID]types.RoleName{},
+			PortalAppsAccepted: map[types.PortalAppID]bool{portalAppID: false},
+		}
+	}
+
+	account := db.accounts[accountID]
+	if account.Users == nil {
+		account.Users = make(map[types.UserID]types.AccountUserAccess)
+	}
+	if _, ok := account.Users[user.ID]; !ok {
+		access := *clone(db.portalAppUsers[portalAppID][user.ID])
+		access.AccountID = ""
+		access.PortalAppRoles = map[types.PortalAppID]types.RoleName{}
+		access.PortalAppsAccepted = map[types.PortalAppID]bool{portalAppID: false}
+		account.Users[user.ID] = access
+	}
+
+	db.updateAccountUserAccess(portalAppID, user.ID, func(access *types.AccountUserAccess) {
+		access.PortalAppRoles[portalAppID] = roleName
+		if _, ok := access.PortalAppsAccepted[portalAppID]; !ok {
+			access.PortalAppsAccepted[portalAppID] = false
+		}
+	})
+}
+
+// updateAccountUserAccess applies the update to both the Portal App's and the Account's copy of a user's access
+func (db *FakeDBClient) updateAccountUserAccess(portalAppID types.PortalAppID, userID types.UserID, update func(access *types.AccountUserAccess)) {
+	appAccess, ok := db.portalAppUsers[portalAppID][userID]
+	if !ok {
+		return
+	}
+	initAccessMaps(appAccess)
+	update(appAccess)
+
+	portalApp, ok := db.portalApps[portalAppID]
+	if !ok {
+		return
+	}
+	account, ok := db.accounts[portalApp.AccountID]
+	if !ok {
+		return
+	}
+	if access, ok := account.Users[userID]; ok {
+		initAccessMaps(&access)
+		update(&access)
+		account.Users[userID] = access
+	}
+}
+
+func initAccessMaps(access *types.AccountUserAccess) {
+	if access.PortalAppRoles == nil {
+		access.PortalAppRoles = make(map[types.PortalAppID]types.RoleName)
+	}
+	if access.PortalAppsAccepted == nil {
+		access.PortalAppsAccepted = make(map[types.PortalAppID]bool)
+	}
+}
+
+// findUser returns the user by either its portal user ID or an auth provider user ID
+func (db *FakeDBClient) findUser(userID string) (*types.User, bool) {
+	if user, ok := db.users[types.UserID(userID)]; ok {
+		return user, true
+	}
+	for _, user := range db.users {
+		for _, authProvider := range user.AuthProviders {
+			if string(authProvider.ProviderUserID) == userID {
+				return user, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (db *FakeDBClient) findUserByEmail(email string) *types.User {
+	for _, user := range db.users {
+		if user.Email == email {
+			return user
+		}
+	}
+	return nil
+}
+
+// accountUserFilter matches accounts the user is a member of with any of the filtered roles and the given accepted status
+func accountUserFilter(userID types.UserID, options AccountOptions) func(*types.Account) bool {
+	return func(account *types.Account) bool {
+		access, ok := account.Users[userID]
+		if !ok {
+			return false
+		}
+		if len(options.RoleNameFilters) > 0 {
+			hasRole := access.Owner && containsRole(options.RoleNameFilters, types.RoleOwner)
+			for _, roleName := range access.PortalAppRoles {
+				hasRole = hasRole || containsRole(options.RoleNameFilters, roleName)
+			}
+			if !hasRole {
+				return false
+			}
+		}
+		if options.Accepted != nil {
+			hasAccepted := false
+			for _, accepted := range access.PortalAppsAccepted {
+				hasAccepted = hasAccepted || accepted == *options.Accepted
+			}
+			if !hasAccepted {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func fakeAuthProviderForPrefix(prefix string) (types.AuthType, types.AuthProvider, bool) {
+	for authType, provider := range fakeAuthProviders {
+		if string(provider) == prefix {
+			return authType, provider, true
+		}
+	}
+	return "", "", false
+}
+
+// checkIfMatch returns the *APIError PHD responds with when the UpdatedAt set with WithIfMatch isn't the same
+// instant as updatedAt
+func checkIfMatch(ctx context.Context, operation string, updatedAt time.Time) error {
+	ifMatch, ok := ifMatchFromContext(ctx)
+	if !ok || ifMatch.Equal(updatedAt) {
+		return nil
+	}
+	return fakeServerError(http.StatusPreconditionFailed, operation, "entity was updated at %s", updatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// fakeServerError returns the *APIError PHD responds with for the given operation
+func fakeServerError(statusCode int, operation, format string, args ...any) error {
+	return &APIError{StatusCode: statusCode, Message: fmt.Sprintf("error in %s: %s", operation, fmt.Sprintf(format, args...))}
+}
+
+func containsRole(roleNames []types.RoleName, roleName types.RoleName) bool {
+	for _, r := range roleNames {
+		if r == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
+
+// paginate returns at most limit of the items sorted by ID following the cursor, which is the ID of the last item of
+// the previous page. A limit of zero returns every item following the cursor.
+func paginate[T any](items []*T, limit int, cursor string, id func(*T) string) []*T {
+	if cursor != "" {
+		start := sort.Search(len(items), func(i int) bool { return id(items[i]) > cursor })
+		items = items[start:]
+	}
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// fakeList returns an Iterator over pages of list, fetching one item beyond the limit to find the next cursor
+func fakeList[T any](limit int, cursor string, list func(limit int, cursor string) ([]*T, error), id func(*T) string) *Iterator[T] {
+	if limit <= 0 {
+		limit = defaultPageLimit
+	}
+
+	return newIterator(cursor, func(cursor string) (itemPage[T], error) {
+		items, err := list(limit+1, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &slicePage[T]{items: items}
+		if len(items) > limit {
+			page.items = items[:limit]
+			page.cursor = id(items[limit-1])
+		}
+		return page, nil
+	})
+}
+
+func chainCursor(chain *types.Chain) string                      { return string(chain.ID) }
+func gigastakeAppCursor(gigastakeApp *types.GigastakeApp) string { return string(gigastakeApp.ID) }
+func portalAppCursor(portalApp *types.PortalApp) string          { return string(portalApp.ID) }
+func accountCursor(account *types.Account) string                { return string(account.ID) }
+
+func isSet(b *bool) bool {
+	return b != nil && *b
+}
+
+// mergeUpdate applies the set fields of an update struct onto an entity sharing its JSON field names,
+// ignoring null and empty string values as well as the given keys
+func mergeUpdate(entity, update any, ignoredKeys ...string) error {
+	updateJSON, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(updateJSON, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		if string(value) == "null" || string(value) == `""` {
+			delete(fields, key)
+		}
+	}
+	for _, key := range ignoredKeys {
+		delete(fields, key)
+	}
+
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(fieldsJSON, entity)
+}
+
+// clone returns a deep copy of a JSON-serializable value
+func clone[T any](value T) T {
+	var copied T
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("fake db client: cannot copy %T: %s", value, err))
+	}
+	if err := json.Unmarshal(valueJSON, &copied); err != nil {
+		panic(fmt.Sprintf("fake db client: cannot copy %T: %s", value, err))
+	}
+
+	return copied
+}
+
+func nonNilMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
@@ -0,0 +1,433 @@
+package dbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFakeDBClient() *FakeDBClient {
+	return NewFakeDBClient(FakeDBClientSeed{
+		Chains: map[types.RelayChainID]*types.Chain{
+			"0001": {ID: "0001", Active: true},
+			"0002": {ID: "0002", Active: false},
+		},
+		GigastakeApps: map[types.GigastakeAppID]*types.GigastakeApp{
+			"test_gigastake_app_1": {ID: "test_gigastake_app_1", Name: "pokt_gigastake", ChainIDs: map[types.RelayChainID]struct{}{"0001": {}}},
+		},
+		PortalApps: map[types.PortalAppID]*types.PortalApp{
+			"test_app_1": {ID: "test_app_1", AccountID: "account_1", Name: "pokt_app_1"},
+			"test_app_2": {ID: "test_app_2", AccountID: "account_1", Name: "pokt_app_2"},
+		},
+		PortalAppUsers: map[types.PortalAppID]map[types.UserID]*types.AccountUserAccess{
+			"test_app_1": {
+				"user_1": {AccountID: "account_1", UserID: "user_1", PortalAppRoles: map[types.PortalAppID]types.RoleName{"test_app_1": types.RoleOwner}, PortalAppsAccepted: map[types.PortalAppID]bool{"test_app_1": true}},
+				"user_2": {AccountID: "account_1", UserID: "user_2", PortalAppRoles: map[types.PortalAppID]types.RoleName{"test_app_1": types.RoleMember}, PortalAppsAccepted: map[types.PortalAppID]bool{"test_app_1": false}},
+			},
+			"test_app_2": {
+				"user_2": {AccountID: "account_1", UserID: "user_2", PortalAppRoles: map[types.PortalAppID]types.RoleName{"test_app_2": types.RoleAdmin}, PortalAppsAccepted: map[types.PortalAppID]bool{"test_app_2": true}},
+			},
+		},
+		Accounts: map[types.AccountID]*types.Account{
+			"account_1": {
+				ID:       "account_1",
+				PlanType: "basic_plan",
+				Users: map[types.UserID]types.AccountUserAccess{
+					"user_1": {UserID: "user_1", Owner: true, PortalAppRoles: map[types.PortalAppID]types.RoleName{"test_app_1": types.RoleOwner}, PortalAppsAccepted: map[types.PortalAppID]bool{"test_app_1": true}},
+					"user_2": {UserID: "user_2", PortalAppRoles: map[types.PortalAppID]types.RoleName{"test_app_1": types.RoleMember, "test_app_2": types.RoleAdmin}, PortalAppsAccepted: map[types.PortalAppID]bool{"test_app_1": false, "test_app_2": true}},
+				},
+			},
+		},
+		Users: map[types.UserID]*types.User{
+			"user_1": {
+				ID:    "user_1",
+				Email: "james.holden123@test.com",
+				AuthProviders: map[types.AuthType]types.UserAuthProvider{
+					types.AuthTypeAuth0Username: {Type: types.AuthTypeAuth0Username, ProviderUserID: "auth0|james_holden", Provider: types.AuthProviderAuth0},
+				},
+			},
+			"user_2": {ID: "user_2", Email: "paul.atreides456@test.com"},
+		},
+		Plans: map[types.PayPlanType]*types.Plan{
+			"basic_plan": {Type: "basic_plan", Name: "Basic Plan"},
+		},
+		BlockedContracts: types.GlobalBlockedContracts{
+			BlockedAddresses: map[types.BlockedAddress]struct{}{"0xtest_blocked": {}},
+		},
+	})
+}
+
+func Test_FakeDBClient_ValidationParity(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to PHD: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	realClient, err := NewDBClient(Config{BaseURL: ts.URL, APIKey: "test_api_key"})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		call        func(client IDBClient) error
+		expectedErr error
+	}{
+		{
+			name: "Should fail to get a chain without an ID",
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(ctx, "")
+				return err
+			},
+			expectedErr: errNoChainID,
+		},
+		{
+			name: "Should fail to get portal apps with more than one option",
+			call: func(client IDBClient) error {
+				_, err := client.GetAllPortalApps(ctx, PortalAppOptions{}, PortalAppOptions{})
+				return err
+			},
+			expectedErr: errMoreThanOneOption,
+		},
+		{
+			name: "Should fail to get user portal apps with an invalid role filter",
+			call: func(client IDBClient) error {
+				_, err := client.GetPortalAppsByUser(ctx, "user_1", PortalAppOptions{RoleNameFilters: []types.RoleName{"SUPREME_LEADER"}})
+				return err
+			},
+			expectedErr: errInvalidRoleName,
+		},
+		{
+			name: "Should fail to get chains with more than one option",
+			call: func(client IDBClient) error {
+				_, err := client.GetAllChains(ctx, ChainOptions{}, ChainOptions{})
+				return err
+			},
+			expectedErr: errMoreThanOneOption,
+		},
+		{
+			name: "Should fail to get all portal apps with a role filter",
+			call: func(client IDBClient) error {
+				_, err := client.GetAllPortalApps(ctx, PortalAppOptions{RoleNameFilters: []types.RoleName{types.RoleOwner}})
+				return err
+			},
+			expectedErr: ErrUnsupportedOption,
+		},
+		{
+			name: "Should fail to get all portal apps with a limit",
+			call: func(client IDBClient) error {
+				_, err := client.GetAllPortalApps(ctx, PortalAppOptions{Limit: 1, Cursor: "test_app_1"})
+				return err
+			},
+			expectedErr: ErrUnsupportedOption,
+		},
+		{
+			name: "Should fail to get a user account without a user ID",
+			call: func(client IDBClient) error {
+				_, err := client.GetUserAccount(ctx, "account_1", "")
+				return err
+			},
+			expectedErr: errNoUserID,
+		},
+		{
+			name: "Should fail to create an account without a user ID",
+			call: func(client IDBClient) error {
+				_, err := client.CreateAccount(ctx, "", types.Account{PlanType: "basic_plan"}, time.Now())
+				return err
+			},
+			expectedErr: errNoUserID,
+		},
+		{
+			name: "Should fail to create an account without a plan type",
+			call: func(client IDBClient) error {
+				_, err := client.CreateAccount(ctx, "user_1", types.Account{}, time.Now())
+				return err
+			},
+			expectedErr: errNoPlanTypeSet,
+		},
+		{
+			name: "Should fail to write an account user without a role name",
+			call: func(client IDBClient) error {
+				_, err := client.WriteAccountUser(ctx, types.CreateAccountUserAccess{AccountID: "account_1", PortalAppID: "test_app_1", Email: "new.user@test.com"}, time.Now())
+				return err
+			},
+			expectedErr: errNoRoleName,
+		},
+		{
+			name: "Should fail to accept an account user without a provider user ID",
+			call: func(client IDBClient) error {
+				_, err := client.UpdateAcceptAccountUser(ctx, types.UpdateAcceptAccountUser{PortalAppID: "test_app_1", UserID: "user_2", AuthProviderType: types.AuthTypeAuth0Username}, time.Now())
+				return err
+			},
+			expectedErr: errNoProviderUserID,
+		},
+		{
+			name: "Should fail to create a user without an email",
+			call: func(client IDBClient) error {
+				_, err := client.CreateUser(ctx, types.CreateUser{ProviderUserID: "auth0|test"})
+				return err
+			},
+			expectedErr: errNoEmail,
+		},
+		{
+			name: "Should fail to remove a blocked contract without an address",
+			call: func(client IDBClient) error {
+				_, err := client.RemoveBlockedContract(ctx, "")
+				return err
+			},
+			expectedErr: errNoBlockedAddress,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			realErr := test.call(realClient)
+			fakeErr := test.call(newTestFakeDBClient())
+
+			assert.ErrorIs(t, realErr, test.expectedErr)
+			assert.Equal(t, realErr, fakeErr)
+		})
+	}
+}
+
+func Test_FakeDBClient_Reads(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		call        func(db *FakeDBClient) (any, error)
+		expected    any
+		expectedErr error
+	}{
+		{
+			name: "Should exclude inactive chains by default",
+			call: func(db *FakeDBClient) (any, error) {
+				chains, err := db.GetAllChains(ctx)
+				return chainIDs(chains), err
+			},
+			expected: []types.RelayChainID{"0001"},
+		},
+		{
+			name: "Should include inactive chains if requested",
+			call: func(db *FakeDBClient) (any, error) {
+				chains, err := db.GetAllChains(ctx, ChainOptions{IncludeInactive: BoolPtr(true)})
+				return chainIDs(chains), err
+			},
+			expected: []types.RelayChainID{"0001", "0002"},
+		},
+		{
+			name: "Should list the portal apps following the cursor",
+			call: func(db *FakeDBClient) (any, error) {
+				portalApps := db.ListPortalApps(ctx, PortalAppOptions{Limit: 1, Cursor: "test_app_1"})
+				ids := make([]types.PortalAppID, 0)
+				for portalApps.Next() {
+					ids = append(ids, portalApps.Value().ID)
+				}
+				return ids, portalApps.Err()
+			},
+			expected: []types.PortalAppID{"test_app_2"},
+		},
+		{
+			name: "Should list every portal app a page at a time",
+			call: func(db *FakeDBClient) (any, error) {
+				portalApps := db.ListPortalApps(ctx, PortalAppOptions{Limit: 1})
+				ids := make([]types.PortalAppID, 0)
+				for portalApps.Next() {
+					ids = append(ids, portalApps.Value().ID)
+				}
+				return ids, portalApps.Err()
+			},
+			expected: []types.PortalAppID{"test_app_1", "test_app_2"},
+		},
+		{
+			name: "Should get portal apps where user_2 is ADMIN",
+			call: func(db *FakeDBClient) (any, error) {
+				portalApps, err := db.GetPortalAppsByUser(ctx, "user_2", PortalAppOptions{RoleNameFilters: []types.RoleName{types.RoleAdmin}})
+				return portalAppIDs(portalApps), err
+			},
+			expected: []types.PortalAppID{"test_app_2"},
+		},
+		{
+			name: "Should get portal apps where user_2 has not accepted their invite",
+			call: func(db *FakeDBClient) (any, error) {
+				portalApps, err := db.GetPortalAppsByUser(ctx, "user_2", PortalAppOptions{Accepted: BoolPtr(false)})
+				return portalAppIDs(portalApps), err
+			},
+			expected: []types.PortalAppID{"test_app_1"},
+		},
+		{
+			name: "Should get accounts where user_1 is OWNER",
+			call: func(db *FakeDBClient) (any, error) {
+				accounts, err := db.GetUserAccounts(ctx, "user_1", AccountOptions{RoleNameFilters: []types.RoleName{types.RoleOwner}})
+				return len(accounts), err
+			},
+			expected: 1,
+		},
+		{
+			name: "Should get a user by provider user ID",
+			call: func(db *FakeDBClient) (any, error) {
+				return db.GetPortalUserID(ctx, "auth0|james_holden")
+			},
+			expected: types.UserID("user_1"),
+		},
+		{
+			name: "Should return a not found error for a missing portal app",
+			call: func(db *FakeDBClient) (any, error) {
+				_, err := db.GetPortalAppByID(ctx, "test_app_404")
+				return nil, err
+			},
+			expectedErr: &APIError{StatusCode: http.StatusNotFound, Message: "error in getPortalAppByID: portal app not found"},
+		},
+		{
+			name: "Should return a not found error if the user has no accounts",
+			call: func(db *FakeDBClient) (any, error) {
+				_, err := db.GetUserAccounts(ctx, "user_404")
+				return nil, err
+			},
+			expectedErr: &APIError{StatusCode: http.StatusNotFound, Message: "error in getUserAccounts: no accounts were found for user ID"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.call(newTestFakeDBClient())
+			assert.Equal(t, test.expectedErr, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, test.expected, result)
+			}
+		})
+	}
+}
+
+func Test_FakeDBClient_Writes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should soft-delete portal apps", func(t *testing.T) {
+		db := newTestFakeDBClient()
+
+		response, err := db.DeletePortalApp(ctx, "test_app_1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"status": "deleted"}, response)
+
+		_, err = db.GetPortalAppByID(ctx, "test_app_1")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		portalApps, err := db.GetAllPortalApps(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []types.PortalAppID{"test_app_2"}, portalAppIDs(portalApps))
+
+		portalApps, err = db.GetAllPortalApps(ctx, PortalAppOptions{IncludeDeleted: BoolPtr(true)})
+		assert.NoError(t, err)
+		assert.Equal(t, []types.PortalAppID{"test_app_1", "test_app_2"}, portalAppIDs(portalApps))
+	})
+
+	t.Run("Should invite, accept and remove an account user", func(t *testing.T) {
+		db := newTestFakeDBClient()
+
+		userIDResp, err := db.WriteAccountUser(ctx, types.CreateAccountUserAccess{
+			AccountID:   "account_1",
+			PortalAppID: "test_app_2",
+			Email:       "new.user@test.com",
+			RoleName:    types.RoleMember,
+		}, time.Now())
+		assert.NoError(t, err)
+		userID := userIDResp["userID"]
+		assert.NotEmpty(t, userID)
+
+		portalApps, err := db.GetPortalAppsByUser(ctx, userID, PortalAppOptions{Accepted: BoolPtr(false)})
+		assert.NoError(t, err)
+		assert.Equal(t, []types.PortalAppID{"test_app_2"}, portalAppIDs(portalApps))
+
+		_, err = db.SetAccountUserRole(ctx, types.UpdateAccountUserRole{AccountID: "account_1", PortalAppID: "test_app_2", UserID: userID, RoleName: types.RoleOwner}, time.Now())
+		assert.Equal(t, &APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "error in updateAccountUserRole: error cannot transfer ownership to user ID '" + string(userID) + "' for account ID 'account_1' because the user has not accepted their invite",
+		}, err)
+
+		_, err = db.UpdateAcceptAccountUser(ctx, types.UpdateAcceptAccountUser{
+			PortalAppID:      "test_app_2",
+			UserID:           userID,
+			AuthProviderType: types.AuthTypeAuth0Username,
+			ProviderUserID:   "auth0|new_user",
+		}, time.Now())
+		assert.NoError(t, err)
+
+		account, err := db.GetUserAccount(ctx, "account_1", userID, AccountOptions{Accepted: BoolPtr(true)})
+		assert.NoError(t, err)
+		assert.Equal(t, map[types.PortalAppID]bool{"test_app_2": true}, account.Users[userID].PortalAppsAccepted)
+
+		portalUserID, err := db.GetPortalUserID(ctx, "auth0|new_user")
+		assert.NoError(t, err)
+		assert.Equal(t, userID, portalUserID)
+
+		_, err = db.RemoveAccountUser(ctx, types.UpdateRemoveAccountUser{AccountID: "account_1", PortalAppID: "test_app_2", UserID: userID})
+		assert.NoError(t, err)
+
+		_, err = db.GetUserAccount(ctx, "account_1", userID)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Should not remove the account owner", func(t *testing.T) {
+		db := newTestFakeDBClient()
+
+		_, err := db.RemoveAccountUser(ctx, types.UpdateRemoveAccountUser{AccountID: "account_1", PortalAppID: "test_app_1", UserID: "user_1"})
+		assert.Equal(t, &APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "error in removeAccountUser: error cannot delete user ID 'user_1' for account ID 'account_1' because this user is the current account owner",
+		}, err)
+	})
+
+	t.Run("Should update and remove blocked contracts", func(t *testing.T) {
+		db := newTestFakeDBClient()
+
+		_, err := db.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xtest_blocked", Active: true})
+		assert.Equal(t, &APIError{StatusCode: http.StatusInternalServerError, Message: "error in writeBlockedContract: error blockchain address 0xtest_blocked is already blocked"}, err)
+
+		_, err = db.UpdateBlockedContractActive(ctx, "0xtest_blocked", false)
+		assert.NoError(t, err)
+
+		blockedContracts, err := db.GetBlockedContracts(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, blockedContracts.BlockedAddresses)
+
+		_, err = db.RemoveBlockedContract(ctx, "0xtest_blocked")
+		assert.NoError(t, err)
+
+		_, err = db.RemoveBlockedContract(ctx, "0xtest_blocked")
+		assert.Equal(t, &APIError{StatusCode: http.StatusInternalServerError, Message: "error in removeBlockedContract: error blockchain address 0xtest_blocked does not exist"}, err)
+	})
+
+	t.Run("Should not let callers mutate stored values", func(t *testing.T) {
+		db := newTestFakeDBClient()
+
+		portalApp, err := db.GetPortalAppByID(ctx, "test_app_1")
+		assert.NoError(t, err)
+		portalApp.Name = "mutated"
+
+		portalApp, err = db.GetPortalAppByID(ctx, "test_app_1")
+		assert.NoError(t, err)
+		assert.Equal(t, "pokt_app_1", portalApp.Name)
+	})
+}
+
+func chainIDs(chains []*types.Chain) []types.RelayChainID {
+	ids := make([]types.RelayChainID, len(chains))
+	for i, chain := range chains {
+		ids[i] = chain.ID
+	}
+	return ids
+}
+
+func portalAppIDs(portalApps []*types.PortalApp) []types.PortalAppID {
+	ids := make([]types.PortalAppID, len(portalApps))
+	for i, portalApp := range portalApps {
+		ids[i] = portalApp.ID
+	}
+	return ids
+}
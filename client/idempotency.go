@@ -0,0 +1,170 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header carrying the idempotency key of a write
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type (
+	// idempotencyKeyContextKey is the context key holding the idempotency key set with WithIdempotencyKey
+	idempotencyKeyContextKey struct{}
+
+	// idempotencyTransport is a RoundTripper sending an Idempotency-Key header with every write, generating one
+	// for POST requests without a key in their context. The header is set once per call, so every retry of the
+	// call carries the same key. With a dedupe window it also returns the response of an identical create that
+	// succeeded within the window instead of sending it again. Updates and deletes are always sent, as repeating
+	// one after a different write to the same entity is meant to change it again.
+	idempotencyTransport struct {
+		underlying http.RoundTripper
+		window     time.Duration
+		now        func() time.Time
+
+		mu     sync.Mutex
+		writes map[string]*dedupedWrite
+	}
+
+	// dedupedWrite is a write that may be shared with identical writes until window after it completed
+	dedupedWrite struct {
+		key         string
+		done        chan struct{}
+		completedAt time.Time
+
+		resp *http.Response
+		body []byte
+	}
+)
+
+// WithIdempotencyKey returns a context whose writes are sent with the given idempotency key, eg. to reuse the
+// key of a write when retrying it at the application level
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// NewIdempotencyKey returns a random idempotency key
+func NewIdempotencyKey() string {
+	key := make([]byte, 16)
+	_, _ = rand.Read(key)
+	return hex.EncodeToString(key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
+func newIdempotencyTransport(config Config, underlying http.RoundTripper) *idempotencyTransport {
+	return &idempotencyTransport{
+		underlying: underlying,
+		window:     config.WriteDedupeWindow,
+		now:        time.Now,
+		writes:     make(map[string]*dedupedWrite),
+	}
+}
+
+func (t *idempotencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadMethod(req.Method) {
+		return t.underlying.RoundTrip(req)
+	}
+
+	explicitKey := idempotencyKeyFromContext(req.Context())
+	key := explicitKey
+	if key == "" && req.Method == http.MethodPost {
+		key = NewIdempotencyKey()
+	}
+
+	if t.window <= 0 || req.Method != http.MethodPost {
+		return t.send(req, key)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	// Writes with different explicit keys are meant to be separate writes
+	bodyHash := sha256.Sum256(body)
+	identity := fmt.Sprintf("%s\n%s\n%s\n%s\n%x", req.Method, req.URL, explicitKey, req.Header.Get("If-Match"), bodyHash)
+
+	t.mu.Lock()
+	t.sweep()
+	if write, ok := t.writes[identity]; ok {
+		t.mu.Unlock()
+		return t.wait(req, write)
+	}
+	write := &dedupedWrite{key: key, done: make(chan struct{})}
+	t.writes[identity] = write
+	t.mu.Unlock()
+
+	resp, err := t.send(req, key)
+	if err == nil && resp.StatusCode < http.StatusMultipleChoices {
+		write.body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			resp = nil
+		} else {
+			write.resp = resp
+			resp = bufferedResponse(resp, write.body, req)
+		}
+	}
+
+	t.mu.Lock()
+	write.completedAt = t.now()
+	if write.resp == nil {
+		delete(t.writes, identity)
+	}
+	t.mu.Unlock()
+	close(write.done)
+
+	return resp, err
+}
+
+// wait returns the response of an identical write once it completed. If that write failed this one is sent
+// with the same key, so PHD can still recognise it as a duplicate.
+func (t *idempotencyTransport) wait(req *http.Request, write *dedupedWrite) (*http.Response, error) {
+	select {
+	case <-write.done:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	if write.resp != nil {
+		return bufferedResponse(write.resp, write.body, req), nil
+	}
+	return t.send(req, write.key)
+}
+
+// send sets the idempotency key on a copy of the request and sends it
+func (t *idempotencyTransport) send(req *http.Request, key string) (*http.Response, error) {
+	if key != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	return t.underlying.RoundTrip(req)
+}
+
+// sweep removes the writes that completed longer than the window ago
+func (t *idempotencyTransport) sweep() {
+	now := t.now()
+	for identity, write := range t.writes {
+		if !write.completedAt.IsZero() && now.Sub(write.completedAt) > t.window {
+			delete(t.writes, identity)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package dbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// idempotencyTestServer records the Idempotency-Key header of every request and answers with the given statuses
+type idempotencyTestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []string
+	statuses []int
+}
+
+func newIdempotencyTestServer(t *testing.T, statuses ...int) *idempotencyTestServer {
+	server := &idempotencyTestServer{statuses: statuses}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.keys = append(server.keys, r.Header.Get(IdempotencyKeyHeader))
+		status := http.StatusOK
+		if len(server.keys) <= len(server.statuses) {
+			status = server.statuses[len(server.keys)-1]
+		}
+		server.mu.Unlock()
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func Test_IdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		write         func(ctx context.Context, client IDBClient) error
+		statuses      []int
+		expectedKeys  int
+		expectedEmpty bool
+	}{
+		{
+			name: "Should send the same generated key with every retry of a create",
+			write: func(ctx context.Context, client IDBClient) error {
+				_, err := client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xtest_blocked"})
+				return err
+			},
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			expectedKeys: 3,
+		},
+		{
+			name: "Should send the key set on the context",
+			key:  "test_idempotency_key_1",
+			write: func(ctx context.Context, client IDBClient) error {
+				_, err := client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xtest_blocked"})
+				return err
+			},
+			statuses:     []int{http.StatusServiceUnavailable},
+			expectedKeys: 2,
+		},
+		{
+			name: "Should send the key set on the context with an update",
+			key:  "test_idempotency_key_2",
+			write: func(ctx context.Context, client IDBClient) error {
+				_, err := client.ActivateChain(ctx, "0001", true)
+				return err
+			},
+			expectedKeys: 1,
+		},
+		{
+			name: "Should not generate a key for an update",
+			write: func(ctx context.Context, client IDBClient) error {
+				_, err := client.ActivateChain(ctx, "0001", true)
+				return err
+			},
+			expectedKeys:  1,
+			expectedEmpty: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newIdempotencyTestServer(t, test.statuses...)
+			client, err := NewDBClient(Config{
+				BaseURL:     server.URL,
+				APIKey:      "test_api_key_6789",
+				Timeout:     5 * time.Second,
+				RetryPolicy: fixedRetryPolicy{retries: len(test.statuses)},
+			})
+			assert.NoError(t, err)
+
+			ctx := context.Background()
+			if test.key != "" {
+				ctx = WithIdempotencyKey(ctx, test.key)
+			}
+			_ = test.write(ctx, client)
+
+			assert.Len(t, server.keys, test.expectedKeys)
+			for _, key := range server.keys {
+				assert.Equal(t, server.keys[0], key)
+			}
+			switch {
+			case test.expectedEmpty:
+				assert.Empty(t, server.keys[0])
+			case test.key != "":
+				assert.Equal(t, test.key, server.keys[0])
+			default:
+				assert.Len(t, server.keys[0], 32)
+			}
+		})
+	}
+}
+
+func Test_IdempotencyKey_DistinctPerWrite(t *testing.T) {
+	server := newIdempotencyTestServer(t)
+	client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789"})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := client.WriteBlockedContract(context.Background(), types.BlockedContract{BlockedAddress: "0xtest_blocked"})
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, server.keys, 2)
+	assert.NotEqual(t, server.keys[0], server.keys[1], "every logical write has its own key")
+}
+
+func Test_WriteDedupeWindow(t *testing.T) {
+	tests := []struct {
+		name             string
+		secondAddress    types.BlockedAddress
+		secondKey        string
+		elapsed          time.Duration
+		statuses         []int
+		expectedRequests int
+		expectedFirstErr bool
+	}{
+		{
+			name:             "Should return the response of an identical write within the window",
+			secondAddress:    "0xtest_blocked",
+			expectedRequests: 1,
+		},
+		{
+			name:             "Should send a write with a different body",
+			secondAddress:    "0xtest_blocked_2",
+			expectedRequests: 2,
+		},
+		{
+			name:             "Should send a write with a different idempotency key",
+			secondAddress:    "0xtest_blocked",
+			secondKey:        "test_idempotency_key_2",
+			expectedRequests: 2,
+		},
+		{
+			name:             "Should send an identical write once the window has passed",
+			secondAddress:    "0xtest_blocked",
+			elapsed:          2 * time.Minute,
+			expectedRequests: 2,
+		},
+		{
+			name:             "Should send an identical write again after a failure",
+			secondAddress:    "0xtest_blocked",
+			statuses:         []int{http.StatusInternalServerError},
+			expectedRequests: 2,
+			expectedFirstErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newIdempotencyTestServer(t, test.statuses...)
+			client, err := newDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", WriteDedupeWindow: time.Minute})
+			assert.NoError(t, err)
+
+			now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+			client.httpClient.Transport.(*idempotencyTransport).now = func() time.Time { return now }
+
+			ctx := WithIdempotencyKey(context.Background(), "test_idempotency_key_1")
+			first, firstErr := client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xtest_blocked"})
+
+			now = now.Add(test.elapsed)
+			if test.secondKey != "" {
+				ctx = WithIdempotencyKey(context.Background(), test.secondKey)
+			}
+			second, err := client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: test.secondAddress})
+			assert.NoError(t, err)
+
+			assert.Len(t, server.keys, test.expectedRequests)
+			if test.expectedFirstErr {
+				assert.Error(t, firstErr)
+				assert.Equal(t, server.keys[0], server.keys[1])
+			} else {
+				assert.NoError(t, firstErr)
+				assert.Equal(t, first, second)
+			}
+		})
+	}
+}
+
+func Test_WriteDedupeWindow_SendsUpdates(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(ctx context.Context, client IDBClient, active bool) error
+	}{
+		{
+			name: "Should send every toggle of a chain",
+			write: func(ctx context.Context, client IDBClient, active bool) error {
+				_, err := client.ActivateChain(ctx, "0001", active)
+				return err
+			},
+		},
+		{
+			name: "Should send every toggle of a blocked contract",
+			write: func(ctx context.Context, client IDBClient, active bool) error {
+				_, err := client.UpdateBlockedContractActive(ctx, "0xtest_blocked", active)
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newIdempotencyTestServer(t)
+			client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", WriteDedupeWindow: time.Minute})
+			assert.NoError(t, err)
+
+			ctx := WithIdempotencyKey(context.Background(), "test_idempotency_key_1")
+			for _, active := range []bool{true, false, true} {
+				_ = test.write(ctx, client, active)
+			}
+
+			assert.Len(t, server.keys, 3, "the last toggle is sent, leaving PHD in the requested state")
+		})
+	}
+}
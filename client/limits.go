@@ -0,0 +1,154 @@
+package dbclient
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// LimitConfig throttles the requests sent to PHD, eg. to keep batch jobs from overloading it.
+	// Every limit left at zero is disabled.
+	LimitConfig struct {
+		// RequestsPerSecond is the rate at which requests are let through, using a token bucket
+		RequestsPerSecond float64
+		// Burst is the number of requests that may be sent at once above RequestsPerSecond.
+		// Defaults to RequestsPerSecond rounded up.
+		Burst int
+		// MaxInFlight is the number of requests that may be in flight at the same time. A request is in flight
+		// until its response headers arrive, so reading a streamed body, eg. a List* page, doesn't hold a slot.
+		MaxInFlight int
+	}
+
+	// requestLimiter enforces the read and write LimitConfig of a client
+	requestLimiter struct {
+		reads, writes *limit
+	}
+
+	// limit enforces a single LimitConfig
+	limit struct {
+		bucket    *tokenBucket
+		semaphore chan struct{}
+	}
+
+	// tokenBucket holds up to burst tokens, refilled at rate tokens per second. The token count goes negative
+	// for waiting callers so that tokens are handed out in order.
+	tokenBucket struct {
+		rate, burst float64
+		now         func() time.Time
+
+		mu     sync.Mutex
+		tokens float64
+		last   time.Time
+	}
+)
+
+// newRequestLimiter returns the limiter for the configured ReadLimit and WriteLimit, or nil if neither is set
+func newRequestLimiter(config Config) *requestLimiter {
+	if config.ReadLimit == nil && config.WriteLimit == nil {
+		return nil
+	}
+	return &requestLimiter{reads: newLimit(config.ReadLimit), writes: newLimit(config.WriteLimit)}
+}
+
+func newLimit(config *LimitConfig) *limit {
+	if config == nil {
+		return nil
+	}
+
+	l := &limit{}
+	if config.RequestsPerSecond > 0 {
+		burst := config.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(config.RequestsPerSecond))
+		}
+		l.bucket = newTokenBucket(config.RequestsPerSecond, burst)
+	}
+	if config.MaxInFlight > 0 {
+		l.semaphore = make(chan struct{}, config.MaxInFlight)
+	}
+
+	return l
+}
+
+// wait blocks until the request may be sent or the context is done. It returns the time spent waiting and
+// a release func that must be called once the request is no longer in flight.
+func (r *requestLimiter) wait(ctx context.Context, method string) (func(), time.Duration, error) {
+	l := r.writes
+	if isReadMethod(method) {
+		l = r.reads
+	}
+	if l == nil {
+		return func() {}, 0, nil
+	}
+
+	start := time.Now()
+	if l.semaphore != nil {
+		select {
+		case l.semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, time.Since(start), ctx.Err()
+		}
+	}
+	release := func() {
+		if l.semaphore != nil {
+			<-l.semaphore
+		}
+	}
+
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx); err != nil {
+			release()
+			return nil, time.Since(start), err
+		}
+	}
+
+	return release, time.Since(start), nil
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), now: time.Now, tokens: float64(burst), last: time.Now()}
+}
+
+// wait takes a token, waiting until it is available. A caller giving up hands its token back.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns how long until it is available
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.burst)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns a reserved token
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.tokens+1, b.burst)
+}
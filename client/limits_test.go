@@ -0,0 +1,171 @@
+package dbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TokenBucket(t *testing.T) {
+	tests := []struct {
+		name           string
+		rate           float64
+		burst          int
+		elapsed        []time.Duration
+		expectedDelays []time.Duration
+	}{
+		{
+			name:           "Should let a burst through without waiting",
+			rate:           10,
+			burst:          3,
+			elapsed:        []time.Duration{0, 0, 0},
+			expectedDelays: []time.Duration{0, 0, 0},
+		},
+		{
+			name:           "Should queue callers beyond the burst in order",
+			rate:           10,
+			burst:          1,
+			elapsed:        []time.Duration{0, 0, 0},
+			expectedDelays: []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:           "Should refill tokens over time",
+			rate:           10,
+			burst:          1,
+			elapsed:        []time.Duration{0, 50 * time.Millisecond, 150 * time.Millisecond},
+			expectedDelays: []time.Duration{0, 50 * time.Millisecond, 0},
+		},
+		{
+			name:           "Should not refill beyond the burst",
+			rate:           10,
+			burst:          2,
+			elapsed:        []time.Duration{time.Hour, 0, 0},
+			expectedDelays: []time.Duration{0, 0, 100 * time.Millisecond},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+			bucket := newTokenBucket(test.rate, test.burst)
+			bucket.now = func() time.Time { return now }
+			bucket.last = now
+
+			delays := make([]time.Duration, 0)
+			for _, elapsed := range test.elapsed {
+				now = now.Add(elapsed)
+				delays = append(delays, bucket.reserve())
+			}
+
+			assert.Equal(t, test.expectedDelays, delays)
+		})
+	}
+}
+
+func Test_TokenBucket_CancelReturnsToken(t *testing.T) {
+	bucket := newTokenBucket(0.001, 1)
+	assert.NoError(t, bucket.wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, bucket.wait(ctx), context.DeadlineExceeded)
+	assert.InDelta(t, 0, bucket.tokens, 0.01, "the abandoned token is handed back")
+}
+
+func Test_RequestLimits(t *testing.T) {
+	var requests atomic.Int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method == http.MethodPost {
+			<-unblock
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	recorder := &testMetricsRecorder{}
+	client, err := NewDBClient(Config{
+		BaseURL:    server.URL,
+		APIKey:     "test_api_key_6789",
+		Timeout:    5 * time.Second,
+		ReadLimit:  &LimitConfig{RequestsPerSecond: 20, Burst: 1},
+		WriteLimit: &LimitConfig{MaxInFlight: 1},
+		Metrics:    recorder,
+	})
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("Should throttle reads to the rate limit", func(t *testing.T) {
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := client.GetPortalAppByID(ctx, "test_app_1")
+			assert.NoError(t, err)
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+		assert.Len(t, recorder.metrics, 3)
+		assert.Less(t, recorder.metrics[0].Throttled, time.Millisecond)
+		assert.Greater(t, recorder.metrics[2].Throttled, 20*time.Millisecond)
+	})
+
+	t.Run("Should wait for an in-flight write until the context is done", func(t *testing.T) {
+		go func() {
+			_, _ = client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xtest_blocked_1"})
+		}()
+		assert.Eventually(t, func() bool { return requests.Load() == 4 }, time.Second, time.Millisecond)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err := client.WriteBlockedContract(timeoutCtx, types.BlockedContract{BlockedAddress: "0xtest_blocked_2"})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(4), requests.Load(), "the second write is never sent")
+	})
+
+	t.Run("Should not hold reads behind the write limit", func(t *testing.T) {
+		_, err := client.GetPortalAppByID(ctx, "test_app_1")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(5), requests.Load())
+	})
+}
+
+func Test_RequestLimits_ReadsWhileListing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/portal_app" {
+			_, _ = w.Write([]byte(`{"data": [{"id": "test_app_1"}, {"id": "test_app_2"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "test_app_1", "name": "pokt_app_1"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDBClient(Config{
+		BaseURL:   server.URL,
+		APIKey:    "test_api_key_6789",
+		Timeout:   5 * time.Second,
+		ReadLimit: &LimitConfig{MaxInFlight: 1},
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The page body is still being read when the loop reads the portal app
+	portalApps := client.(IDBLister).ListPortalApps(ctx, PortalAppOptions{})
+	defer portalApps.Close()
+	var listed int
+	for portalApps.Next() {
+		listed++
+		_, err := client.GetPortalAppByID(ctx, portalApps.Value().ID)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, portalApps.Err())
+	assert.Equal(t, 2, listed)
+}
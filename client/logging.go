@@ -0,0 +1,168 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyParts are matched against lower-cased JSON keys and header names, with `_` and `-` removed
+var sensitiveKeyParts = []string{"authorization", "password", "secret", "token", "apikey", "privatekey", "signature", "email", "provideruserid"}
+
+// requestLogger writes a structured log record for every PHD request attempt. A nil requestLogger logs nothing.
+type requestLogger struct {
+	logger    *slog.Logger
+	logBodies bool
+}
+
+// newRequestLogger returns the requestLogger for the config, or nil if no Logger is configured
+func newRequestLogger(config Config) *requestLogger {
+	if config.Logger == nil {
+		return nil
+	}
+	return &requestLogger{logger: config.Logger, logBodies: config.LogBodies}
+}
+
+// logRequest logs the request headers and body at debug level, if body logging is enabled
+func (l *requestLogger) logRequest(req *http.Request, body []byte) {
+	if !l.bodiesEnabled(req.Context()) {
+		return
+	}
+
+	l.logger.LogAttrs(req.Context(), slog.LevelDebug, "PHD request body",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Any("headers", redactHeaders(req.Header)),
+		slog.String("body", maskBody(body)),
+	)
+}
+
+// logAttempt logs the outcome of a single attempt. Successes are logged at debug level, failed attempts
+// that will be retried and 4xx responses at warn level, and final 5xx or transport failures at error level.
+func (l *requestLogger) logAttempt(req *http.Request, resp *http.Response, err error, attempt int, latency time.Duration, willRetry bool) {
+	if l == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+	}
+
+	level := slog.LevelDebug
+	switch {
+	case err != nil:
+		attrs = append(attrs, slog.String("error", err.Error()))
+		level = slog.LevelError
+	case resp.StatusCode >= http.StatusInternalServerError:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		level = slog.LevelError
+	case resp.StatusCode >= http.StatusBadRequest:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		level = slog.LevelWarn
+	default:
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if willRetry {
+		attrs = append(attrs, slog.Bool("retrying", true))
+		level = slog.LevelWarn
+	}
+
+	l.logger.LogAttrs(req.Context(), level, "PHD request", attrs...)
+
+	if resp != nil && l.bodiesEnabled(req.Context()) {
+		l.logResponseBody(req, resp)
+	}
+}
+
+// logResponseBody logs the masked response body and replaces it so the caller can still read it
+func (l *requestLogger) logResponseBody(req *http.Request, resp *http.Response) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	l.logger.LogAttrs(req.Context(), slog.LevelDebug, "PHD response body",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Int("status", resp.StatusCode),
+		slog.String("body", maskBody(body)),
+	)
+}
+
+func (l *requestLogger) bodiesEnabled(ctx context.Context) bool {
+	return l != nil && l.logBodies && l.logger.Enabled(ctx, slog.LevelDebug)
+}
+
+// redactHeaders flattens the headers for logging, replacing the values of sensitive headers such as Authorization
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		if isSensitiveKey(name) {
+			redacted[name] = redactedValue
+			continue
+		}
+		redacted[name] = strings.Join(values, ",")
+	}
+	return redacted
+}
+
+// maskBody returns the JSON body with the values of sensitive fields masked. Non-JSON bodies are
+// never logged verbatim since they can't be masked, only their length is.
+func maskBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("[%d bytes non-JSON body]", len(body))
+	}
+
+	masked, err := json.Marshal(maskSensitiveFields(value))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(masked)
+}
+
+// maskSensitiveFields recursively replaces the values of sensitive object keys in a decoded JSON value
+func maskSensitiveFields(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = maskSensitiveFields(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = maskSensitiveFields(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(normalized, part) {
+			return true
+		}
+	}
+	return false
+}
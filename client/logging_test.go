@@ -0,0 +1,171 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestLogging(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          slog.Level
+		logBodies      bool
+		statuses       []int
+		call           func(client IDBClient) error
+		expectedLevels []string
+		expectedPath   string
+		expectedStatus []float64
+		expectedBody   string
+	}{
+		{
+			name:  "Should log a successful request at debug level",
+			level: slog.LevelDebug,
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(context.Background(), "0001")
+				return err
+			},
+			statuses:       []int{http.StatusOK},
+			expectedLevels: []string{"DEBUG"},
+			expectedPath:   "/v2/chain/0001",
+			expectedStatus: []float64{200},
+		},
+		{
+			name:  "Should not log successful requests at info level",
+			level: slog.LevelInfo,
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(context.Background(), "0001")
+				return err
+			},
+			statuses:       []int{http.StatusOK},
+			expectedLevels: []string{},
+		},
+		{
+			name:  "Should log retried attempts at warn level and the final failure at error level",
+			level: slog.LevelInfo,
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(context.Background(), "0001")
+				return err
+			},
+			statuses:       []int{http.StatusServiceUnavailable, http.StatusInternalServerError},
+			expectedLevels: []string{"WARN", "ERROR"},
+			expectedPath:   "/v2/chain/0001",
+			expectedStatus: []float64{503, 500},
+		},
+		{
+			name:  "Should log 4xx responses at warn level",
+			level: slog.LevelInfo,
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(context.Background(), "0001")
+				return err
+			},
+			statuses:       []int{http.StatusNotFound},
+			expectedLevels: []string{"WARN"},
+			expectedPath:   "/v2/chain/0001",
+			expectedStatus: []float64{404},
+		},
+		{
+			name:      "Should log masked bodies when body logging is enabled",
+			level:     slog.LevelDebug,
+			logBodies: true,
+			call: func(client IDBClient) error {
+				_, err := client.CreateUser(context.Background(), types.CreateUser{Email: "james.holden123@test.com"})
+				return err
+			},
+			statuses:       []int{http.StatusOK},
+			expectedLevels: []string{"DEBUG", "DEBUG", "DEBUG"},
+			expectedPath:   "/v2/user",
+			expectedStatus: []float64{200},
+			expectedBody:   `\"email\":\"[REDACTED]\"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := test.statuses[attempts.Add(1)-1]
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"id":"0001","email":"james.holden123@test.com"}`))
+			}))
+			defer server.Close()
+
+			var logs bytes.Buffer
+			client, err := NewDBClient(Config{
+				BaseURL:     server.URL,
+				APIKey:      "test_api_key_6789",
+				Timeout:     5 * time.Second,
+				RetryPolicy: fixedRetryPolicy{retries: len(test.statuses) - 1},
+				Logger:      slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: test.level})),
+				LogBodies:   test.logBodies,
+			})
+			assert.NoError(t, err)
+
+			_ = test.call(client)
+
+			levels, statuses := make([]string, 0), make([]float64, 0)
+			for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+				if line == "" {
+					continue
+				}
+				var record map[string]any
+				assert.NoError(t, json.Unmarshal([]byte(line), &record))
+				levels = append(levels, record["level"].(string))
+				if record["msg"] == "PHD request" {
+					statuses = append(statuses, record["status"].(float64))
+					assert.Equal(t, test.expectedPath, record["path"])
+				}
+			}
+
+			assert.Equal(t, test.expectedLevels, levels)
+			if test.expectedStatus != nil {
+				assert.Equal(t, test.expectedStatus, statuses)
+			}
+			assert.NotContains(t, logs.String(), "test_api_key_6789")
+			assert.NotContains(t, logs.String(), "james.holden123@test.com")
+			if test.expectedBody != "" {
+				assert.Contains(t, logs.String(), test.expectedBody)
+			}
+		})
+	}
+}
+
+func Test_MaskBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "Should mask sensitive fields at any depth",
+			body:     `{"id":"user_1","email":"a@b.com","auth":{"provider_user_id":"auth0|x","secretKey":"s"},"aats":[{"privateKey":"k","publicKey":"p"}]}`,
+			expected: `{"aats":[{"privateKey":"[REDACTED]","publicKey":"p"}],"auth":{"provider_user_id":"[REDACTED]","secretKey":"[REDACTED]"},"email":"[REDACTED]","id":"user_1"}`,
+		},
+		{
+			name:     "Should only log the length of a non-JSON body",
+			body:     `not json`,
+			expected: `[8 bytes non-JSON body]`,
+		},
+		{
+			name:     "Should log an empty body as empty",
+			body:     ``,
+			expected: ``,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, maskBody([]byte(test.body)))
+		})
+	}
+}
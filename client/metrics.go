@@ -0,0 +1,182 @@
+package dbclient
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusClassError is the status class reported for requests that failed without a response
+	StatusClassError = "error"
+
+	unknownOperation = "unknown"
+)
+
+type (
+	// MetricsRecorder is called once for every client call, after all of its attempts have completed.
+	// Implementations must be safe for concurrent use and should not block.
+	MetricsRecorder interface {
+		RecordRequest(ctx context.Context, metrics RequestMetrics)
+	}
+
+	// RequestMetrics describes a single client call to PHD
+	RequestMetrics struct {
+		// Operation is the name of the client method, eg. `GetPortalAppByID`
+		Operation string
+		// Method is the HTTP method of the request
+		Method string
+		// StatusClass is the class of the final response status, eg. `2xx`, or StatusClassError if no response was received
+		StatusClass string
+		// StatusCode is the final response status code, or 0 if no response was received
+		StatusCode int
+		// Attempts is the number of attempts made, including retries
+		Attempts int
+		// Duration is the time from the first attempt until the response body was closed
+		Duration time.Duration
+		// Throttled is the time spent waiting for the ReadLimit or WriteLimit before the first attempt
+		Throttled time.Duration
+		// RequestBytes is the size of the request body
+		RequestBytes int64
+		// ResponseBytes is the number of response body bytes read
+		ResponseBytes int64
+	}
+
+	// NoopMetricsRecorder is the default MetricsRecorder and discards all metrics
+	NoopMetricsRecorder struct{}
+
+	// ExpvarMetricsRecorder is a MetricsRecorder publishing counters per operation as an expvar.Map, eg.
+	// `{"GetPortalAppByID": {"requests_2xx": 10, "attempts": 12, "duration_ns": 51000000, "throttled_ns": 0, "request_bytes": 0, "response_bytes": 8200}}`
+	ExpvarMetricsRecorder struct {
+		vars *expvar.Map
+		mu   sync.Mutex
+	}
+
+	// operationContextKey is the context key holding the name of the client method making a request
+	operationContextKey struct{}
+
+	// meteredBody counts the bytes read from a response body and calls done once when it is closed
+	meteredBody struct {
+		io.ReadCloser
+		read int64
+		once sync.Once
+		done func(read int64)
+	}
+)
+
+var (
+	_ MetricsRecorder = NoopMetricsRecorder{}
+	_ MetricsRecorder = &ExpvarMetricsRecorder{}
+)
+
+// metricsRecorder returns the configured MetricsRecorder or the NoopMetricsRecorder
+func (c Config) metricsRecorder() MetricsRecorder {
+	if c.Metrics != nil {
+		return c.Metrics
+	}
+	return NoopMetricsRecorder{}
+}
+
+// RecordRequest implements MetricsRecorder
+func (NoopMetricsRecorder) RecordRequest(context.Context, RequestMetrics) {}
+
+// NewExpvarMetricsRecorder returns an ExpvarMetricsRecorder published under the given expvar name.
+// Like expvar.Publish it panics if the name is already in use.
+func NewExpvarMetricsRecorder(name string) *ExpvarMetricsRecorder {
+	return &ExpvarMetricsRecorder{vars: expvar.NewMap(name)}
+}
+
+// RecordRequest implements MetricsRecorder
+func (r *ExpvarMetricsRecorder) RecordRequest(_ context.Context, metrics RequestMetrics) {
+	operation := r.operationVars(metrics.Operation)
+
+	operation.Add(fmt.Sprintf("requests_%s", metrics.StatusClass), 1)
+	operation.Add("attempts", int64(metrics.Attempts))
+	operation.Add("duration_ns", int64(metrics.Duration))
+	operation.Add("throttled_ns", int64(metrics.Throttled))
+	operation.Add("request_bytes", metrics.RequestBytes)
+	operation.Add("response_bytes", metrics.ResponseBytes)
+}
+
+// Vars returns the published expvar.Map
+func (r *ExpvarMetricsRecorder) Vars() *expvar.Map {
+	return r.vars
+}
+
+// operationVars returns the counters of an operation, creating them on first use
+func (r *ExpvarMetricsRecorder) operationVars(operation string) *expvar.Map {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if vars, ok := r.vars.Get(operation).(*expvar.Map); ok {
+		return vars
+	}
+
+	vars := new(expvar.Map).Init()
+	r.vars.Set(operation, vars)
+	return vars
+}
+
+// withOperation returns a context carrying the name of the client method making the request
+func withOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, operation)
+}
+
+// operationFromContext returns the name of the client method making the request
+func operationFromContext(ctx context.Context) string {
+	if operation, ok := ctx.Value(operationContextKey{}).(string); ok {
+		return operation
+	}
+	return unknownOperation
+}
+
+// statusClass returns the class of a status code, eg. `4xx`
+func statusClass(statusCode int) string {
+	if statusCode == 0 {
+		return StatusClassError
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+// recordMetrics reports a finished request to the recorder. For a response the metrics are recorded
+// once its body is closed, so the duration and bytes include reading the body.
+func recordMetrics(recorder MetricsRecorder, req *http.Request, resp *http.Response, attempts int, requestBytes int64, throttled time.Duration, start time.Time) {
+	metrics := RequestMetrics{
+		Operation:    operationFromContext(req.Context()),
+		Method:       req.Method,
+		StatusClass:  StatusClassError,
+		Attempts:     attempts,
+		Throttled:    throttled,
+		RequestBytes: requestBytes,
+	}
+
+	if resp == nil {
+		metrics.Duration = time.Since(start)
+		recorder.RecordRequest(req.Context(), metrics)
+		return
+	}
+
+	metrics.StatusCode = resp.StatusCode
+	metrics.StatusClass = statusClass(resp.StatusCode)
+	resp.Body = &meteredBody{ReadCloser: resp.Body, done: func(read int64) {
+		metrics.Duration = time.Since(start)
+		metrics.ResponseBytes = read
+		recorder.RecordRequest(req.Context(), metrics)
+	}}
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	return n, err
+}
+
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.read) })
+	return err
+}
@@ -0,0 +1,127 @@
+package dbclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testMetricsRecorder collects every recorded RequestMetrics
+type testMetricsRecorder struct {
+	mu      sync.Mutex
+	metrics []RequestMetrics
+}
+
+func (r *testMetricsRecorder) RecordRequest(_ context.Context, metrics RequestMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, metrics)
+}
+
+func Test_MetricsRecorder(t *testing.T) {
+	const responseBody = `{"id":"test_app_1"}`
+
+	tests := []struct {
+		name          string
+		statuses      []int
+		serverClosed  bool
+		call          func(client IDBClient) error
+		expected      RequestMetrics
+		responseBytes bool
+	}{
+		{
+			name:     "Should record a successful call with its operation name",
+			statuses: []int{http.StatusOK},
+			call: func(client IDBClient) error {
+				_, err := client.GetPortalAppByID(context.Background(), "test_app_1")
+				return err
+			},
+			expected:      RequestMetrics{Operation: "GetPortalAppByID", Method: http.MethodGet, StatusClass: "2xx", StatusCode: http.StatusOK, Attempts: 1},
+			responseBytes: true,
+		},
+		{
+			name:     "Should record every attempt of a retried call once",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			call: func(client IDBClient) error {
+				_, err := client.GetAllChains(context.Background())
+				return err
+			},
+			expected:      RequestMetrics{Operation: "GetAllChains", Method: http.MethodGet, StatusClass: "2xx", StatusCode: http.StatusOK, Attempts: 2},
+			responseBytes: true,
+		},
+		{
+			name:     "Should record the status class and request bytes of a failed write",
+			statuses: []int{http.StatusNotFound},
+			call: func(client IDBClient) error {
+				_, err := client.WriteBlockedContract(context.Background(), types.BlockedContract{BlockedAddress: "0xtest"})
+				return err
+			},
+			expected:      RequestMetrics{Operation: "WriteBlockedContract", Method: http.MethodPost, StatusClass: "4xx", StatusCode: http.StatusNotFound, Attempts: 1},
+			responseBytes: true,
+		},
+		{
+			name:         "Should record a transport failure with the error status class",
+			serverClosed: true,
+			call: func(client IDBClient) error {
+				_, err := client.GetAllPlans(context.Background())
+				return err
+			},
+			expected: RequestMetrics{Operation: "GetAllPlans", Method: http.MethodGet, StatusClass: StatusClassError, Attempts: 1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statuses[attempts.Add(1)-1])
+				_, _ = w.Write([]byte(responseBody))
+			}))
+			defer server.Close()
+			if test.serverClosed {
+				server.Close()
+			}
+
+			recorder := &testMetricsRecorder{}
+			client, err := NewDBClient(Config{
+				BaseURL:     server.URL,
+				APIKey:      "test_api_key_6789",
+				Timeout:     5 * time.Second,
+				RetryPolicy: fixedRetryPolicy{retries: max(len(test.statuses)-1, 0)},
+				Metrics:     recorder,
+			})
+			assert.NoError(t, err)
+
+			_ = test.call(client)
+
+			assert.Len(t, recorder.metrics, 1)
+			recorded := recorder.metrics[0]
+			assert.Greater(t, recorded.Duration, time.Duration(0))
+			if test.expected.Method == http.MethodPost {
+				assert.Greater(t, recorded.RequestBytes, int64(0))
+			}
+			if test.responseBytes {
+				assert.Equal(t, int64(len(responseBody)), recorded.ResponseBytes)
+			}
+
+			recorded.Duration, recorded.RequestBytes, recorded.ResponseBytes = 0, 0, 0
+			assert.Equal(t, test.expected, recorded)
+		})
+	}
+}
+
+func Test_ExpvarMetricsRecorder(t *testing.T) {
+	recorder := NewExpvarMetricsRecorder("test_phd_client_metrics")
+
+	recorder.RecordRequest(context.Background(), RequestMetrics{Operation: "GetAllChains", StatusClass: "2xx", Attempts: 1, Duration: time.Millisecond, ResponseBytes: 100})
+	recorder.RecordRequest(context.Background(), RequestMetrics{Operation: "GetAllChains", StatusClass: "5xx", Attempts: 3, Duration: 2 * time.Millisecond, Throttled: time.Millisecond})
+
+	assert.JSONEq(t, `{"GetAllChains": {"requests_2xx": 1, "requests_5xx": 1, "attempts": 4, "duration_ns": 3000000, "throttled_ns": 1000000, "request_bytes": 0, "response_bytes": 100}}`, recorder.Vars().String())
+}
@@ -0,0 +1,262 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	defaultSnapshotInterval = 10 * time.Second
+	defaultSnapshotJitter   = 0.1
+)
+
+var errInvalidSnapshotJitter error = errors.New("snapshot jitter must be between 0 and 1")
+
+type (
+	// MiddlewareSnapshotConfig configures a MiddlewareSnapshot
+	MiddlewareSnapshotConfig struct {
+		// Interval between refreshes. Defaults to 10 seconds.
+		Interval time.Duration
+		// Jitter is the fraction of Interval each wait is randomly shifted by, so replicas don't poll PHD in lockstep.
+		// Must be between 0 and 1. Defaults to 0.1 when zero, set DisableJitter to wait exactly Interval.
+		Jitter float64
+		// DisableJitter waits exactly Interval between refreshes
+		DisableJitter bool
+		// StaleThreshold is the age after which the snapshot is considered stale; a snapshot that never loaded is always stale. Zero disables staleness checks.
+		StaleThreshold time.Duration
+		// OnStale is called once when the snapshot becomes stale, with the time of the last successful refresh and the latest refresh error.
+		// While Run is running it is also called when no refresh succeeds within StaleThreshold, eg. because a refresh hangs.
+		OnStale func(lastRefreshed time.Time, err error)
+		// OnRefreshError is called for every failed refresh
+		OnRefreshError func(err error)
+	}
+
+	// MiddlewareSnapshot keeps an indexed copy of all Portal App Lites, periodically refreshed from PHD.
+	// When a refresh fails the last good snapshot keeps being served.
+	MiddlewareSnapshot struct {
+		reader   IDBReader
+		config   MiddlewareSnapshotConfig
+		snapshot atomic.Pointer[middlewareSnapshot]
+
+		mu        sync.Mutex
+		version   uint64
+		fetches   uint64
+		published uint64
+		stale     bool
+		lastErr   error
+	}
+
+	middlewareSnapshot struct {
+		version     uint64
+		refreshedAt time.Time
+		byID        map[types.PortalAppID]*types.PortalAppLite
+		byPublicKey map[types.PortalAppPublicKey]*types.PortalAppLite
+	}
+)
+
+// NewMiddlewareSnapshot returns an empty MiddlewareSnapshot; call Refresh or Run to populate it
+func NewMiddlewareSnapshot(reader IDBReader, config MiddlewareSnapshotConfig) (*MiddlewareSnapshot, error) {
+	if config.Jitter < 0 || config.Jitter > 1 {
+		return nil, errInvalidSnapshotJitter
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = defaultSnapshotInterval
+	}
+	switch {
+	case config.DisableJitter:
+		config.Jitter = 0
+	case config.Jitter == 0:
+		config.Jitter = defaultSnapshotJitter
+	}
+
+	return &MiddlewareSnapshot{reader: reader, config: config}, nil
+}
+
+// Lookup returns the Portal App Lite for the given portal app ID
+func (s *MiddlewareSnapshot) Lookup(portalAppID types.PortalAppID) (*types.PortalAppLite, bool) {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return nil, false
+	}
+
+	app, ok := snapshot.byID[portalAppID]
+	return app, ok
+}
+
+// LookupByPublicKey returns the Portal App Lite owning the given public key
+func (s *MiddlewareSnapshot) LookupByPublicKey(publicKey types.PortalAppPublicKey) (*types.PortalAppLite, bool) {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return nil, false
+	}
+
+	app, ok := snapshot.byPublicKey[publicKey]
+	return app, ok
+}
+
+// Len returns the number of Portal Apps in the current snapshot
+func (s *MiddlewareSnapshot) Len() int {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return 0
+	}
+
+	return len(snapshot.byID)
+}
+
+// Version returns the version of the current snapshot, incremented on every successful refresh. Zero means no snapshot has been loaded yet.
+func (s *MiddlewareSnapshot) Version() uint64 {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return 0
+	}
+
+	return snapshot.version
+}
+
+// LastRefreshed returns the time of the last successful refresh
+func (s *MiddlewareSnapshot) LastRefreshed() time.Time {
+	snapshot := s.snapshot.Load()
+	if snapshot == nil {
+		return time.Time{}
+	}
+
+	return snapshot.refreshedAt
+}
+
+// Refresh fetches all Portal App Lites and atomically swaps in a new snapshot.
+// On error the current snapshot is kept. When refreshes overlap, a fetch that started before the
+// published snapshot's fetch is discarded, so older data never replaces newer data.
+func (s *MiddlewareSnapshot) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	s.fetches++
+	fetch := s.fetches
+	s.mu.Unlock()
+
+	portalAppLites, err := s.reader.GetPortalAppsForMiddleware(ctx)
+	if err != nil {
+		if s.config.OnRefreshError != nil {
+			s.config.OnRefreshError(err)
+		}
+		s.mu.Lock()
+		if fetch > s.published {
+			s.lastErr = err
+		}
+		s.mu.Unlock()
+		s.checkStaleness(err)
+		return err
+	}
+
+	snapshot := &middlewareSnapshot{
+		byID:        make(map[types.PortalAppID]*types.PortalAppLite, len(portalAppLites)),
+		byPublicKey: make(map[types.PortalAppPublicKey]*types.PortalAppLite, len(portalAppLites)),
+	}
+	for _, app := range portalAppLites {
+		snapshot.byID[app.ID] = app
+		for _, publicKey := range app.PublicKeys {
+			snapshot.byPublicKey[publicKey] = app
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fetch < s.published {
+		return nil
+	}
+
+	s.published = fetch
+	s.version++
+	snapshot.version = s.version
+	snapshot.refreshedAt = time.Now()
+	s.snapshot.Store(snapshot)
+	s.stale = false
+	s.lastErr = nil
+
+	return nil
+}
+
+// Run refreshes the snapshot immediately and then on every interval until the context is cancelled
+func (s *MiddlewareSnapshot) Run(ctx context.Context) {
+	if s.config.StaleThreshold > 0 && s.config.OnStale != nil {
+		watchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go s.watchStaleness(watchCtx)
+	}
+
+	_ = s.Refresh(ctx)
+
+	timer := time.NewTimer(s.nextInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			_ = s.Refresh(ctx)
+			timer.Reset(s.nextInterval())
+		}
+	}
+}
+
+func (s *MiddlewareSnapshot) nextInterval() time.Duration {
+	jitter := time.Duration((rand.Float64()*2 - 1) * s.config.Jitter * float64(s.config.Interval))
+	return s.config.Interval + jitter
+}
+
+// watchStaleness checks the staleness of the snapshot whenever it may have passed StaleThreshold until the
+// context is done, so OnStale is called even when no refresh fails, eg. while one hangs
+func (s *MiddlewareSnapshot) watchStaleness(ctx context.Context) {
+	start := time.Now()
+	for {
+		deadline := s.LastRefreshed()
+		if deadline.IsZero() {
+			deadline = start
+		}
+		deadline = deadline.Add(s.config.StaleThreshold)
+		if now := time.Now(); !deadline.After(now) {
+			deadline = now.Add(s.config.StaleThreshold)
+		}
+
+		timer := time.NewTimer(time.Until(deadline))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		err := s.lastErr
+		s.mu.Unlock()
+		s.checkStaleness(err)
+	}
+}
+
+// checkStaleness calls OnStale the first time the snapshot exceeds the stale threshold since its last refresh
+func (s *MiddlewareSnapshot) checkStaleness(err error) {
+	if s.config.StaleThreshold <= 0 || s.config.OnStale == nil {
+		return
+	}
+
+	lastRefreshed := s.LastRefreshed()
+	if !lastRefreshed.IsZero() && time.Since(lastRefreshed) < s.config.StaleThreshold {
+		return
+	}
+
+	s.mu.Lock()
+	alreadyStale := s.stale
+	s.stale = true
+	s.mu.Unlock()
+
+	if !alreadyStale {
+		s.config.OnStale(lastRefreshed, err)
+	}
+}
@@ -0,0 +1,252 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_MiddlewareSnapshot_Refresh(t *testing.T) {
+	errPHDDown := errors.New("phd down")
+
+	tests := []struct {
+		name              string
+		responses         []error
+		staleThreshold    time.Duration
+		expectedVersion   uint64
+		expectedApp       *types.PortalAppLite
+		expectedStaleHits int32
+	}{
+		{
+			name:            "Should index portal apps by ID and public key",
+			responses:       []error{nil},
+			expectedVersion: 1,
+			expectedApp:     &types.PortalAppLite{ID: "test_app_1", PublicKeys: []types.PortalAppPublicKey{"test_key_1"}},
+		},
+		{
+			name:            "Should increment the version on every successful refresh",
+			responses:       []error{nil, nil, nil},
+			expectedVersion: 3,
+			expectedApp:     &types.PortalAppLite{ID: "test_app_1", PublicKeys: []types.PortalAppPublicKey{"test_key_1"}},
+		},
+		{
+			name:              "Should keep serving the last good snapshot when PHD fails",
+			responses:         []error{nil, errPHDDown, errPHDDown},
+			staleThreshold:    time.Hour,
+			expectedVersion:   1,
+			expectedApp:       &types.PortalAppLite{ID: "test_app_1", PublicKeys: []types.PortalAppPublicKey{"test_key_1"}},
+			expectedStaleHits: 0,
+		},
+		{
+			name:              "Should call the stale callback once when the snapshot was never loaded",
+			responses:         []error{errPHDDown, errPHDDown},
+			staleThreshold:    time.Hour,
+			expectedVersion:   0,
+			expectedStaleHits: 1,
+		},
+		{
+			name:              "Should call the stale callback again after recovering and going stale",
+			responses:         []error{errPHDDown, nil, errPHDDown},
+			staleThreshold:    time.Nanosecond,
+			expectedVersion:   1,
+			expectedApp:       &types.PortalAppLite{ID: "test_app_1", PublicKeys: []types.PortalAppPublicKey{"test_key_1"}},
+			expectedStaleHits: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := assert.New(t)
+
+			reader := NewMockIDBReader(t)
+			for _, err := range test.responses {
+				if err != nil {
+					reader.On("GetPortalAppsForMiddleware", mock.Anything).Return(nil, err).Once()
+					continue
+				}
+				reader.On("GetPortalAppsForMiddleware", mock.Anything).Return([]*types.PortalAppLite{
+					{ID: "test_app_1", PublicKeys: []types.PortalAppPublicKey{"test_key_1"}},
+					{ID: "test_app_2", PublicKeys: []types.PortalAppPublicKey{"test_key_2", "test_key_3"}},
+				}, nil).Once()
+			}
+
+			var staleHits atomic.Int32
+			snapshot, err := NewMiddlewareSnapshot(reader, MiddlewareSnapshotConfig{
+				StaleThreshold: test.staleThreshold,
+				OnStale:        func(time.Time, error) { staleHits.Add(1) },
+			})
+			c.NoError(err)
+
+			for _, expectedErr := range test.responses {
+				c.ErrorIs(snapshot.Refresh(context.Background()), expectedErr)
+			}
+
+			c.Equal(test.expectedVersion, snapshot.Version())
+			c.Equal(test.expectedStaleHits, staleHits.Load())
+
+			app, ok := snapshot.Lookup("test_app_1")
+			c.Equal(test.expectedApp != nil, ok)
+			c.Equal(test.expectedApp, app)
+
+			app, ok = snapshot.LookupByPublicKey("test_key_1")
+			c.Equal(test.expectedApp != nil, ok)
+			c.Equal(test.expectedApp, app)
+
+			if test.expectedVersion > 0 {
+				c.False(snapshot.LastRefreshed().IsZero())
+				c.Equal(2, snapshot.Len())
+
+				app, ok = snapshot.LookupByPublicKey("test_key_3")
+				c.True(ok)
+				c.Equal(types.PortalAppID("test_app_2"), app.ID)
+			}
+		})
+	}
+}
+
+func Test_MiddlewareSnapshot_Run(t *testing.T) {
+	c := assert.New(t)
+
+	reader := NewMockIDBReader(t)
+	reader.On("GetPortalAppsForMiddleware", mock.Anything).Return([]*types.PortalAppLite{{ID: "test_app_1"}}, nil)
+
+	snapshot, err := NewMiddlewareSnapshot(reader, MiddlewareSnapshotConfig{Interval: 5 * time.Millisecond})
+	c.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		snapshot.Run(ctx)
+		close(done)
+	}()
+
+	c.Eventually(func() bool { return snapshot.Version() >= 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+
+	_, ok := snapshot.Lookup("test_app_1")
+	c.True(ok)
+}
+
+func Test_MiddlewareSnapshot_OverlappingRefreshes(t *testing.T) {
+	c := assert.New(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	reader := NewMockIDBReader(t)
+	reader.On("GetPortalAppsForMiddleware", mock.Anything).Return([]*types.PortalAppLite{{ID: "test_app_old"}}, nil).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Once()
+	reader.On("GetPortalAppsForMiddleware", mock.Anything).Return([]*types.PortalAppLite{{ID: "test_app_new"}}, nil).Once()
+
+	snapshot, err := NewMiddlewareSnapshot(reader, MiddlewareSnapshotConfig{})
+	c.NoError(err)
+
+	slow := make(chan error)
+	go func() { slow <- snapshot.Refresh(context.Background()) }()
+	<-started
+
+	c.NoError(snapshot.Refresh(context.Background()))
+	close(release)
+	c.NoError(<-slow)
+
+	_, ok := snapshot.Lookup("test_app_new")
+	c.True(ok)
+	_, ok = snapshot.Lookup("test_app_old")
+	c.False(ok, "a fetch started before the published one is discarded")
+	c.Equal(uint64(1), snapshot.Version())
+}
+
+func Test_MiddlewareSnapshot_StaleWhileRefreshHangs(t *testing.T) {
+	c := assert.New(t)
+
+	reader := NewMockIDBReader(t)
+	reader.On("GetPortalAppsForMiddleware", mock.Anything).Return([]*types.PortalAppLite{{ID: "test_app_1"}}, nil).Once()
+	reader.On("GetPortalAppsForMiddleware", mock.Anything).Return(nil, context.Canceled).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	})
+
+	staleHits := make(chan time.Time, 1)
+	snapshot, err := NewMiddlewareSnapshot(reader, MiddlewareSnapshotConfig{
+		Interval:       time.Millisecond,
+		StaleThreshold: 20 * time.Millisecond,
+		OnStale:        func(lastRefreshed time.Time, _ error) { staleHits <- lastRefreshed },
+	})
+	c.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		snapshot.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case lastRefreshed := <-staleHits:
+		c.Equal(snapshot.LastRefreshed(), lastRefreshed)
+	case <-time.After(time.Second):
+		t.Error("OnStale was not called while the refresh hangs")
+	}
+
+	cancel()
+	<-done
+}
+
+func Test_NewMiddlewareSnapshot_Jitter(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         MiddlewareSnapshotConfig
+		expectedJitter float64
+		expectedErr    error
+	}{
+		{
+			name:           "Should default the jitter",
+			expectedJitter: defaultSnapshotJitter,
+		},
+		{
+			name:           "Should keep a jitter between 0 and 1",
+			config:         MiddlewareSnapshotConfig{Jitter: 0.5},
+			expectedJitter: 0.5,
+		},
+		{
+			name:   "Should disable the jitter",
+			config: MiddlewareSnapshotConfig{DisableJitter: true},
+		},
+		{
+			name:        "Should reject a jitter above 1",
+			config:      MiddlewareSnapshotConfig{Jitter: 1.5},
+			expectedErr: errInvalidSnapshotJitter,
+		},
+		{
+			name:        "Should reject a negative jitter",
+			config:      MiddlewareSnapshotConfig{Jitter: -0.1},
+			expectedErr: errInvalidSnapshotJitter,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			snapshot, err := NewMiddlewareSnapshot(NewMockIDBReader(t), test.config)
+			assert.ErrorIs(t, err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+
+			assert.Equal(t, test.expectedJitter, snapshot.config.Jitter)
+			if test.config.DisableJitter {
+				assert.Equal(t, defaultSnapshotInterval, snapshot.nextInterval())
+			}
+		})
+	}
+}
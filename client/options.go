@@ -0,0 +1,171 @@
+package dbclient
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+// Names of the options struct fields, as reported by UnsupportedOptionError
+const (
+	optionExcludeGigastakeApps = "ExcludeGigastakeApps"
+	optionIncludeInactive      = "IncludeInactive"
+	optionIncludeDeleted       = "IncludeDeleted"
+	optionRoleNameFilters      = "RoleNameFilters"
+	optionAccepted             = "Accepted"
+	optionLimit                = "Limit"
+	optionCursor               = "Cursor"
+)
+
+type (
+	// queryOptions is implemented by every options struct accepted by the IDBReader methods
+	queryOptions interface {
+		ChainOptions | GigastakeAppOptions | PortalAppOptions | AccountOptions
+		queryFields() ([]optionField, error)
+	}
+
+	// optionField is a single options struct field and the query parameter it is sent as
+	optionField struct {
+		name  string
+		param QueryParam
+		value string
+		set   bool
+	}
+)
+
+// supportedOptions lists the options fields each operation sends to PHD. Setting any other field
+// returns an UnsupportedOptionError rather than being silently ignored.
+var supportedOptions = map[string][]string{
+	"GetAllChains":        {optionIncludeInactive, optionExcludeGigastakeApps, optionIncludeDeleted},
+	"ListChains":          {optionIncludeInactive, optionExcludeGigastakeApps, optionIncludeDeleted, optionLimit, optionCursor},
+	"GetAllGigastakeApps": {optionIncludeDeleted},
+	"ListGigastakeApps":   {optionIncludeDeleted, optionLimit, optionCursor},
+	"GetAllPortalApps":    {optionIncludeDeleted},
+	"ListPortalApps":      {optionIncludeDeleted, optionLimit, optionCursor},
+	"GetPortalAppsByUser": {optionRoleNameFilters, optionIncludeDeleted, optionAccepted},
+	"GetAllAccounts":      {optionIncludeDeleted},
+	"ListAccounts":        {optionIncludeDeleted, optionLimit, optionCursor},
+	"GetUserAccounts":     {optionRoleNameFilters, optionIncludeDeleted, optionAccepted},
+	"GetUserAccount":      {optionRoleNameFilters, optionIncludeDeleted, optionAccepted},
+}
+
+// encodeOptions returns the query parameters for the options passed to an operation. At most one options struct
+// may be passed, and every field set on it must be supported by the operation.
+func encodeOptions[O queryOptions](operation string, optionParams []O) (url.Values, error) {
+	if len(optionParams) > 1 {
+		return nil, errMoreThanOneOption
+	}
+
+	query := url.Values{}
+	if len(optionParams) == 0 {
+		return query, nil
+	}
+
+	fields, err := optionParams[0].queryFields()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		if !field.set {
+			continue
+		}
+		if !isSupportedOption(operation, field.name) {
+			return nil, &UnsupportedOptionError{Operation: operation, Option: field.name}
+		}
+		query.Set(string(field.param), field.value)
+	}
+
+	return query, nil
+}
+
+func isSupportedOption(operation, option string) bool {
+	for _, supported := range supportedOptions[operation] {
+		if supported == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (o ChainOptions) queryFields() ([]optionField, error) {
+	return []optionField{
+		boolField(optionIncludeInactive, ChainParams.includeInactive, o.IncludeInactive),
+		boolField(optionExcludeGigastakeApps, ChainParams.excludeGigastakeApps, o.ExcludeGigastakeApps),
+		boolField(optionIncludeDeleted, commonParams.includeDeleted, o.IncludeDeleted),
+		limitField(o.Limit),
+		cursorField(o.Cursor),
+	}, nil
+}
+
+func (o GigastakeAppOptions) queryFields() ([]optionField, error) {
+	return []optionField{
+		boolField(optionIncludeDeleted, commonParams.includeDeleted, o.IncludeDeleted),
+		limitField(o.Limit),
+		cursorField(o.Cursor),
+	}, nil
+}
+
+func (o PortalAppOptions) queryFields() ([]optionField, error) {
+	roleNameFilters, err := roleNameFiltersField(PortalAppParams.RoleNameFilters, o.RoleNameFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	return []optionField{
+		roleNameFilters,
+		boolField(optionIncludeDeleted, commonParams.includeDeleted, o.IncludeDeleted),
+		boolField(optionAccepted, PortalAppParams.Accepted, o.Accepted),
+		limitField(o.Limit),
+		cursorField(o.Cursor),
+	}, nil
+}
+
+func (o AccountOptions) queryFields() ([]optionField, error) {
+	roleNameFilters, err := roleNameFiltersField(AccountParams.RoleNameFilters, o.RoleNameFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	return []optionField{
+		roleNameFilters,
+		boolField(optionIncludeDeleted, commonParams.includeDeleted, o.IncludeDeleted),
+		boolField(optionAccepted, AccountParams.Accepted, o.Accepted),
+		limitField(o.Limit),
+		cursorField(o.Cursor),
+	}, nil
+}
+
+func boolField(name string, param QueryParam, value *bool) optionField {
+	if value == nil {
+		return optionField{name: name, param: param}
+	}
+	return optionField{name: name, param: param, value: strconv.FormatBool(*value), set: true}
+}
+
+func limitField(limit int) optionField {
+	return optionField{name: optionLimit, param: commonParams.limit, value: strconv.Itoa(limit), set: limit > 0}
+}
+
+func cursorField(cursor string) optionField {
+	return optionField{name: optionCursor, param: commonParams.cursor, value: cursor, set: cursor != ""}
+}
+
+// roleNameFiltersField validates the role names and joins them into a comma separated list
+func roleNameFiltersField(param QueryParam, roleNames []types.RoleName) (optionField, error) {
+	field := optionField{name: optionRoleNameFilters, param: param, set: len(roleNames) > 0}
+
+	roleNameStrs := make([]string, len(roleNames))
+	for i, roleName := range roleNames {
+		if !roleName.IsValid() {
+			return field, fmt.Errorf("%w: %s", errInvalidRoleName, roleName)
+		}
+		roleNameStrs[i] = string(roleName)
+	}
+	field.value = strings.Join(roleNameStrs, ",")
+
+	return field, nil
+}
@@ -0,0 +1,113 @@
+package dbclient
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EncodeOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		encode         func() (url.Values, error)
+		expectedQuery  url.Values
+		expectedErr    error
+		expectedOption string
+	}{
+		{
+			name:          "Should encode no options as an empty query",
+			encode:        func() (url.Values, error) { return encodeOptions[ChainOptions]("GetAllChains", nil) },
+			expectedQuery: url.Values{},
+		},
+		{
+			name: "Should encode every set chain option",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllChains", []ChainOptions{{IncludeInactive: BoolPtr(true), ExcludeGigastakeApps: BoolPtr(false), IncludeDeleted: BoolPtr(true)}})
+			},
+			expectedQuery: url.Values{"include_inactive": {"true"}, "exclude_gigastake_apps": {"false"}, "include_deleted": {"true"}},
+		},
+		{
+			name: "Should encode role filters and accepted for user accounts",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetUserAccounts", []AccountOptions{{RoleNameFilters: []types.RoleName{types.RoleOwner, types.RoleMember}, Accepted: BoolPtr(false)}})
+			},
+			expectedQuery: url.Values{"filters": {string(types.RoleOwner) + "," + string(types.RoleMember)}, "accepted": {"false"}},
+		},
+		{
+			name: "Should encode the limit and cursor of a list",
+			encode: func() (url.Values, error) {
+				return encodeOptions("ListAccounts", []AccountOptions{{Limit: 25, Cursor: "account_1"}})
+			},
+			expectedQuery: url.Values{"limit": {"25"}, "cursor": {"account_1"}},
+		},
+		{
+			name: "Should fail when a limit is set on all accounts",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllAccounts", []AccountOptions{{Limit: 25}})
+			},
+			expectedErr:    ErrUnsupportedOption,
+			expectedOption: optionLimit,
+		},
+		{
+			name: "Should fail when a limit is set on user accounts",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetUserAccounts", []AccountOptions{{Limit: 25}})
+			},
+			expectedErr:    ErrUnsupportedOption,
+			expectedOption: optionLimit,
+		},
+		{
+			name: "Should fail with more than one chain options",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllChains", []ChainOptions{{}, {}})
+			},
+			expectedErr: errMoreThanOneOption,
+		},
+		{
+			name: "Should fail with more than one gigastake app options",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllGigastakeApps", []GigastakeAppOptions{{}, {}})
+			},
+			expectedErr: errMoreThanOneOption,
+		},
+		{
+			name: "Should fail with an invalid role name",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetPortalAppsByUser", []PortalAppOptions{{RoleNameFilters: []types.RoleName{"SUPREME_LEADER"}}})
+			},
+			expectedErr: errInvalidRoleName,
+		},
+		{
+			name: "Should fail when role filters are set on all portal apps",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllPortalApps", []PortalAppOptions{{RoleNameFilters: []types.RoleName{types.RoleOwner}}})
+			},
+			expectedErr:    ErrUnsupportedOption,
+			expectedOption: optionRoleNameFilters,
+		},
+		{
+			name: "Should fail when accepted is set on all accounts",
+			encode: func() (url.Values, error) {
+				return encodeOptions("GetAllAccounts", []AccountOptions{{Accepted: BoolPtr(true)}})
+			},
+			expectedErr:    ErrUnsupportedOption,
+			expectedOption: optionAccepted,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := test.encode()
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedQuery, query)
+
+			var unsupportedErr *UnsupportedOptionError
+			if errors.As(err, &unsupportedErr) {
+				assert.Equal(t, test.expectedOption, unsupportedErr.Option)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package dbclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	// defaultPageLimit is the page size requested by the List* iterators when the options don't set a Limit
+	defaultPageLimit = 500
+
+	// Fields of a paginated list response: `{"data": [...], "next_cursor": "..."}`
+	pageDataField       = "data"
+	pageNextCursorField = "next_cursor"
+)
+
+type (
+	// Iterator walks the items of a list endpoint a page at a time. Each page is decoded item by item as the
+	// iterator advances, so memory stays bounded by a single item rather than the whole table.
+	//
+	//	portalApps := client.ListPortalApps(ctx, dbclient.PortalAppOptions{})
+	//	defer portalApps.Close()
+	//	for portalApps.Next() {
+	//		portalApp := portalApps.Value()
+	//	}
+	//	if err := portalApps.Err(); err != nil {
+	//		...
+	//	}
+	Iterator[T any] struct {
+		openPage func(cursor string) (itemPage[T], error)
+		page     itemPage[T]
+		cursor   string
+		value    *T
+		err      error
+		done     bool
+	}
+
+	// itemPage is a single page of a list endpoint
+	itemPage[T any] interface {
+		// next returns the next item of the page, or nil once the page is exhausted
+		next() (*T, error)
+		// nextCursor returns the cursor of the following page, empty for the last page
+		nextCursor() string
+		close() error
+	}
+
+	// streamPage decodes a page from a response body, either a paginated `{"data": [...], "next_cursor": "..."}`
+	// object or, for servers without pagination, a plain array holding every item
+	streamPage[T any] struct {
+		body      io.ReadCloser
+		decoder   *json.Decoder
+		paginated bool
+		dataSeen  bool
+		ended     bool
+		cursor    string
+	}
+
+	// slicePage is a page of items already in memory
+	slicePage[T any] struct {
+		items  []*T
+		cursor string
+	}
+)
+
+var (
+	errInvalidPage    error = errors.New("invalid paginated response")
+	errRepeatedCursor error = errors.New("server returned the same page cursor twice")
+)
+
+// newIterator returns an Iterator starting at the page the cursor points at, or the first page if it is empty
+func newIterator[T any](cursor string, openPage func(cursor string) (itemPage[T], error)) *Iterator[T] {
+	return &Iterator[T]{openPage: openPage, cursor: cursor}
+}
+
+// errIterator returns an Iterator failing with err on the first call to Next
+func errIterator[T any](err error) *Iterator[T] {
+	return &Iterator[T]{err: err, done: true}
+}
+
+// sliceIterator returns an Iterator over items already read, or failing with err
+func sliceIterator[T any](items []*T, err error) *Iterator[T] {
+	if err != nil {
+		return errIterator[T](err)
+	}
+
+	return newIterator("", func(string) (itemPage[T], error) {
+		return &slicePage[T]{items: items}, nil
+	})
+}
+
+// listChains returns the ListChains Iterator of the reader, or one over a single GetAllChains call if it isn't an IDBLister
+func listChains(ctx context.Context, reader IDBReader, options ChainOptions) *Iterator[types.Chain] {
+	if lister, ok := reader.(IDBLister); ok {
+		return lister.ListChains(ctx, options)
+	}
+	return sliceIterator(reader.GetAllChains(ctx, options))
+}
+
+// listGigastakeApps returns the ListGigastakeApps Iterator of the reader, or one over a single GetAllGigastakeApps call if it isn't an IDBLister
+func listGigastakeApps(ctx context.Context, reader IDBReader, options GigastakeAppOptions) *Iterator[types.GigastakeApp] {
+	if lister, ok := reader.(IDBLister); ok {
+		return lister.ListGigastakeApps(ctx, options)
+	}
+	return sliceIterator(reader.GetAllGigastakeApps(ctx, options))
+}
+
+// listPortalApps returns the ListPortalApps Iterator of the reader, or one over a single GetAllPortalApps call if it isn't an IDBLister
+func listPortalApps(ctx context.Context, reader IDBReader, options PortalAppOptions) *Iterator[types.PortalApp] {
+	if lister, ok := reader.(IDBLister); ok {
+		return lister.ListPortalApps(ctx, options)
+	}
+	return sliceIterator(reader.GetAllPortalApps(ctx, options))
+}
+
+// listAccounts returns the ListAccounts Iterator of the reader, or one over a single GetAllAccounts call if it isn't an IDBLister
+func listAccounts(ctx context.Context, reader IDBReader, options AccountOptions) *Iterator[types.Account] {
+	if lister, ok := reader.(IDBLister); ok {
+		return lister.ListAccounts(ctx, options)
+	}
+	return sliceIterator(reader.GetAllAccounts(ctx, options))
+}
+
+// Next advances to the next item, fetching the following page when the current one is exhausted.
+// It returns false once every item has been read or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	it.value = nil
+
+	for it.err == nil && !it.done {
+		if it.page == nil {
+			page, err := it.openPage(it.cursor)
+			if err != nil {
+				it.err = err
+				break
+			}
+			it.page = page
+		}
+
+		item, err := it.page.next()
+		if err != nil {
+			it.err = err
+			break
+		}
+		if item != nil {
+			it.value = item
+			return true
+		}
+
+		nextCursor := it.page.nextCursor()
+		_ = it.page.close()
+		it.page = nil
+
+		switch nextCursor {
+		case "":
+			it.done = true
+		case it.cursor:
+			it.err = fmt.Errorf("%w: %s", errRepeatedCursor, nextCursor)
+		default:
+			it.cursor = nextCursor
+		}
+	}
+
+	_ = it.Close()
+	return false
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() *T {
+	return it.value
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close releases the page being read. It is safe to call more than once.
+func (it *Iterator[T]) Close() error {
+	it.done = true
+	if it.page == nil {
+		return nil
+	}
+
+	err := it.page.close()
+	it.page = nil
+	return err
+}
+
+// listReq returns an Iterator over a list endpoint, requesting each page with the limit and cursor query params
+func listReq[T any](ctx context.Context, operation, endpoint string, query url.Values, header http.Header, httpClient *http.Client) *Iterator[T] {
+	if !query.Has(string(commonParams.limit)) {
+		query.Set(string(commonParams.limit), strconv.Itoa(defaultPageLimit))
+	}
+
+	return newIterator(query.Get(string(commonParams.cursor)), func(cursor string) (itemPage[T], error) {
+		if cursor != "" {
+			query.Set(string(commonParams.cursor), cursor)
+		}
+
+		resp, err := doReq(ctx, operation, http.MethodGet, withQuery(endpoint, query), header, nil, httpClient)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := newStreamPage[T](resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		return page, nil
+	})
+}
+
+// newStreamPage reads the start of the page up to its first item
+func newStreamPage[T any](body io.ReadCloser) (*streamPage[T], error) {
+	page := &streamPage[T]{body: body, decoder: json.NewDecoder(body)}
+
+	token, err := page.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('['):
+		// Unpaginated servers return every item in a single array
+		return page, nil
+	case json.Delim('{'):
+		page.paginated = true
+		inData, err := page.readFields()
+		if err != nil {
+			return nil, err
+		}
+		page.ended = !inData
+		return page, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected %v", errInvalidPage, token)
+	}
+}
+
+func (p *streamPage[T]) next() (*T, error) {
+	if p.ended {
+		return nil, nil
+	}
+
+	if p.decoder.More() {
+		var item T
+		if err := p.decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		return &item, nil
+	}
+
+	// Closing bracket of the items
+	if _, err := p.decoder.Token(); err != nil {
+		return nil, err
+	}
+	p.ended = true
+
+	if p.paginated {
+		if _, err := p.readFields(); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// readFields reads the fields of a paginated response up to the start of its items, returning false if the
+// object ended instead
+func (p *streamPage[T]) readFields() (bool, error) {
+	for p.decoder.More() {
+		key, err := p.decoder.Token()
+		if err != nil {
+			return false, err
+		}
+
+		switch key {
+		case pageDataField:
+			if p.dataSeen {
+				return false, fmt.Errorf("%w: duplicate %s field", errInvalidPage, pageDataField)
+			}
+			p.dataSeen = true
+
+			token, err := p.decoder.Token()
+			if err != nil {
+				return false, err
+			}
+			if token == json.Delim('[') {
+				return true, nil
+			}
+			if token != nil {
+				return false, fmt.Errorf("%w: %s is not an array", errInvalidPage, pageDataField)
+			}
+		case pageNextCursorField:
+			var cursor *string
+			if err := p.decoder.Decode(&cursor); err != nil {
+				return false, fmt.Errorf("%w: %s", errInvalidPage, err)
+			}
+			if cursor != nil {
+				p.cursor = *cursor
+			}
+		default:
+			var skipped json.RawMessage
+			if err := p.decoder.Decode(&skipped); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// Closing brace of the response
+	_, err := p.decoder.Token()
+	return false, err
+}
+
+func (p *streamPage[T]) nextCursor() string {
+	return p.cursor
+}
+
+func (p *streamPage[T]) close() error {
+	return p.body.Close()
+}
+
+func (p *slicePage[T]) next() (*T, error) {
+	if len(p.items) == 0 {
+		return nil, nil
+	}
+
+	item := p.items[0]
+	p.items = p.items[1:]
+	return item, nil
+}
+
+func (p *slicePage[T]) nextCursor() string {
+	return p.cursor
+}
+
+func (p *slicePage[T]) close() error {
+	return nil
+}
@@ -0,0 +1,216 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_ListPortalApps(t *testing.T) {
+	tests := []struct {
+		name            string
+		options         PortalAppOptions
+		pages           map[string]string
+		expectedIDs     []types.PortalAppID
+		expectedCursors []string
+		expectedLimit   string
+		expectedErr     error
+	}{
+		{
+			name: "Should walk every page following the next cursor",
+			pages: map[string]string{
+				"":           `{"data": [{"id": "test_app_1"}, {"id": "test_app_2"}], "next_cursor": "test_app_2"}`,
+				"test_app_2": `{"next_cursor": "test_app_3", "total": 3, "data": [{"id": "test_app_3"}]}`,
+				"test_app_3": `{"data": [], "next_cursor": null}`,
+			},
+			expectedIDs:     []types.PortalAppID{"test_app_1", "test_app_2", "test_app_3"},
+			expectedCursors: []string{"", "test_app_2", "test_app_3"},
+			expectedLimit:   "500",
+		},
+		{
+			name:    "Should start from the cursor and limit in the options",
+			options: PortalAppOptions{Limit: 1, Cursor: "test_app_1"},
+			pages: map[string]string{
+				"test_app_1": `{"data": [{"id": "test_app_2"}]}`,
+			},
+			expectedIDs:     []types.PortalAppID{"test_app_2"},
+			expectedCursors: []string{"test_app_1"},
+			expectedLimit:   "1",
+		},
+		{
+			name: "Should fall back to a single page if the server returns an unpaginated array",
+			pages: map[string]string{
+				"": `[{"id": "test_app_1"}, {"id": "test_app_2"}]`,
+			},
+			expectedIDs:     []types.PortalAppID{"test_app_1", "test_app_2"},
+			expectedCursors: []string{""},
+			expectedLimit:   "500",
+		},
+		{
+			name: "Should treat null data as an empty page",
+			pages: map[string]string{
+				"": `{"data": null}`,
+			},
+			expectedIDs:     []types.PortalAppID{},
+			expectedCursors: []string{""},
+			expectedLimit:   "500",
+		},
+		{
+			name: "Should fail if the server repeats a cursor",
+			pages: map[string]string{
+				"":           `{"data": [{"id": "test_app_1"}], "next_cursor": "test_app_1"}`,
+				"test_app_1": `{"data": [{"id": "test_app_1"}], "next_cursor": "test_app_1"}`,
+			},
+			expectedIDs:     []types.PortalAppID{"test_app_1", "test_app_1"},
+			expectedCursors: []string{"", "test_app_1"},
+			expectedLimit:   "500",
+			expectedErr:     errRepeatedCursor,
+		},
+		{
+			name: "Should fail on a response that is neither a page nor an array",
+			pages: map[string]string{
+				"": `"test_app_1"`,
+			},
+			expectedIDs:     []types.PortalAppID{},
+			expectedCursors: []string{""},
+			expectedLimit:   "500",
+			expectedErr:     errInvalidPage,
+		},
+		{
+			name: "Should return the APIError of a failed page",
+			pages: map[string]string{
+				"": `{"data": [{"id": "test_app_1"}], "next_cursor": "test_app_1"}`,
+			},
+			expectedIDs:     []types.PortalAppID{"test_app_1"},
+			expectedCursors: []string{"", "test_app_1"},
+			expectedLimit:   "500",
+			expectedErr:     ErrNotFound,
+		},
+		{
+			name:        "Should fail without a request on an unsupported option",
+			options:     PortalAppOptions{Accepted: BoolPtr(true)},
+			expectedIDs: []types.PortalAppID{},
+			expectedErr: ErrUnsupportedOption,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cursors, limits := make([]string, 0), make(map[string]bool)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				cursor := r.URL.Query().Get("cursor")
+				cursors = append(cursors, cursor)
+				limits[r.URL.Query().Get("limit")] = true
+
+				page, ok := test.pages[cursor]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"error": "page not found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(page))
+			}))
+			defer server.Close()
+
+			client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", Timeout: 5 * time.Second})
+			assert.NoError(t, err)
+
+			portalApps := client.(IDBLister).ListPortalApps(context.Background(), test.options)
+			defer portalApps.Close()
+
+			ids := make([]types.PortalAppID, 0)
+			for portalApps.Next() {
+				ids = append(ids, portalApps.Value().ID)
+			}
+
+			assert.ErrorIs(t, portalApps.Err(), test.expectedErr)
+			assert.Equal(t, test.expectedIDs, ids)
+			if test.expectedCursors != nil {
+				assert.Equal(t, test.expectedCursors, cursors)
+				assert.Equal(t, map[string]bool{test.expectedLimit: true}, limits)
+			} else {
+				assert.Empty(t, cursors)
+			}
+			assert.False(t, portalApps.Next())
+		})
+	}
+}
+
+func Test_Iterator_DecodesLazily(t *testing.T) {
+	var once sync.Once
+	release := make(chan struct{})
+	defer once.Do(func() { close(release) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": [{"id": "test_app_1"},`))
+		w.(http.Flusher).Flush()
+
+		// The rest of the page is only sent once the first item has been read
+		<-release
+		_, _ = w.Write([]byte(`{"id": "test_app_2"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+
+	portalApps := client.(IDBLister).ListPortalApps(context.Background(), PortalAppOptions{})
+	defer portalApps.Close()
+
+	assert.True(t, portalApps.Next())
+	assert.Equal(t, types.PortalAppID("test_app_1"), portalApps.Value().ID)
+
+	once.Do(func() { close(release) })
+
+	assert.True(t, portalApps.Next())
+	assert.Equal(t, types.PortalAppID("test_app_2"), portalApps.Value().ID)
+	assert.False(t, portalApps.Next())
+	assert.NoError(t, portalApps.Err())
+}
+
+func Test_ListPortalApps_ReaderWithoutLister(t *testing.T) {
+	errPHDDown := errors.New("phd down")
+
+	tests := []struct {
+		name        string
+		portalApps  []*types.PortalApp
+		err         error
+		expectedIDs []types.PortalAppID
+	}{
+		{
+			name:        "Should iterate over a single GetAllPortalApps call",
+			portalApps:  []*types.PortalApp{{ID: "test_app_1"}, {ID: "test_app_2"}},
+			expectedIDs: []types.PortalAppID{"test_app_1", "test_app_2"},
+		},
+		{
+			name:        "Should fail with the GetAllPortalApps error",
+			err:         errPHDDown,
+			expectedIDs: []types.PortalAppID{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader := NewMockIDBReader(t)
+			reader.On("GetAllPortalApps", mock.Anything, PortalAppOptions{}).Return(test.portalApps, test.err).Once()
+
+			portalApps := listPortalApps(context.Background(), reader, PortalAppOptions{})
+			defer portalApps.Close()
+
+			ids := make([]types.PortalAppID, 0)
+			for portalApps.Next() {
+				ids = append(ids, portalApps.Value().ID)
+			}
+			assert.ErrorIs(t, portalApps.Err(), test.err)
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
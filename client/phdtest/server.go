@@ -0,0 +1,739 @@
+// Package phdtest provides an in-process Portal HTTP DB for tests.
+//
+// The Server implements the /v2 routes used by the DB client on top of a dbclient.FakeDBClient,
+// so the client can be exercised end-to-end over real HTTP without the Docker test environment.
+package phdtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"github.com/pokt-foundation/portal-db/v2/testdata"
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	// DefaultAPIKey is the API key accepted by a Server when none is configured
+	DefaultAPIKey = "test_api_key_6789"
+	// DefaultImageTag is the image tag reported by the `/healthz` route when none is configured
+	DefaultImageTag = "development"
+
+	healthCheckPath = "/healthz"
+	v2Prefix        = "/v2/"
+)
+
+type (
+	// Config configures a Server
+	Config struct {
+		// APIKey is the value the Authorization header must match. Defaults to DefaultAPIKey.
+		APIKey string
+		// Seed is the data the Server starts with. Defaults to TestdataSeed.
+		Seed *dbclient.FakeDBClientSeed
+		// DB is the backing store. Set it to share data between several Servers; Seed is then ignored.
+		DB *dbclient.FakeDBClient
+		// Addr is the address to listen on, eg. `localhost:8080`. Defaults to a random local port.
+		Addr string
+		// ImageTag is reported by the `/healthz` route. Defaults to DefaultImageTag.
+		ImageTag string
+	}
+
+	// Server is an httptest.Server serving the PHD /v2 routes from a dbclient.FakeDBClient
+	Server struct {
+		*httptest.Server
+		// APIKey is the value the Authorization header must match
+		APIKey string
+		// DB is the store backing the Server, useful for arranging and asserting on data directly
+		DB *dbclient.FakeDBClient
+	}
+
+	// handlerFunc handles a request for a matched route, with params holding the wildcard path segments
+	handlerFunc func(w http.ResponseWriter, r *http.Request, params []string)
+
+	route struct {
+		method  string
+		pattern []string
+		handle  handlerFunc
+	}
+
+	// page is a single page of a list route requested with the `limit` param
+	page[T any] struct {
+		Data       []*T   `json:"data"`
+		NextCursor string `json:"next_cursor,omitempty"`
+	}
+
+	handler struct {
+		db       *dbclient.FakeDBClient
+		apiKey   string
+		imageTag string
+		routes   []route
+	}
+)
+
+var (
+	errUnauthorized   error = errors.New("unauthorized")
+	errRouteNotFound  error = errors.New("route not found")
+	errInvalidBody    error = errors.New("invalid request body")
+	errInvalidBoolean error = errors.New("invalid boolean query parameter")
+	errInvalidLimit   error = errors.New("invalid limit query parameter")
+	errInvalidIfMatch error = errors.New("invalid If-Match header")
+)
+
+// NewServer starts a Server with the given config. Callers should Close it when done.
+func NewServer(config Config) (*Server, error) {
+	config = config.withDefaults()
+
+	server := &Server{
+		Server: httptest.NewUnstartedServer(NewHandler(config.DB, config.APIKey, config.ImageTag)),
+		APIKey: config.APIKey,
+		DB:     config.DB,
+	}
+
+	if config.Addr != "" {
+		listener, err := net.Listen("tcp", config.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("phdtest: listen on %s: %w", config.Addr, err)
+		}
+		server.Listener.Close()
+		server.Listener = listener
+	}
+
+	server.Start()
+
+	return server, nil
+}
+
+// NewTestdataServer starts a Server seeded from the portal-db testdata fixtures on a random local port
+func NewTestdataServer() *Server {
+	server, err := NewServer(Config{})
+	if err != nil {
+		panic(err)
+	}
+	return server
+}
+
+// ClientConfig returns a dbclient.Config pointing at the Server
+func (s *Server) ClientConfig() dbclient.Config {
+	return dbclient.Config{
+		BaseURL: s.URL,
+		APIKey:  s.APIKey,
+		Retries: 1,
+		Timeout: 10 * time.Second,
+	}
+}
+
+func (c Config) withDefaults() Config {
+	if c.APIKey == "" {
+		c.APIKey = DefaultAPIKey
+	}
+	if c.ImageTag == "" {
+		c.ImageTag = DefaultImageTag
+	}
+	if c.DB == nil {
+		seed := TestdataSeed()
+		if c.Seed != nil {
+			seed = *c.Seed
+		}
+		c.DB = dbclient.NewFakeDBClient(seed)
+	}
+	return c
+}
+
+// TestdataSeed returns a FakeDBClientSeed containing the portal-db/v2 testdata fixtures
+func TestdataSeed() dbclient.FakeDBClientSeed {
+	return dbclient.FakeDBClientSeed{
+		Chains:           testdata.Chains,
+		GigastakeApps:    testdata.GigastakeApps,
+		PortalApps:       testdata.PortalApps,
+		PortalAppUsers:   testdata.PortalAppUsers,
+		Accounts:         testdata.Accounts,
+		Users:            testdata.Users,
+		Plans:            testdata.PayPlans,
+		BlockedContracts: testdata.GlobalBlockedContracts,
+	}
+}
+
+// NewHandler returns an http.Handler serving the PHD /v2 routes from db. Every route other
+// than `/healthz` requires the Authorization header to equal apiKey.
+func NewHandler(db *dbclient.FakeDBClient, apiKey, imageTag string) http.Handler {
+	h := &handler{db: db, apiKey: apiKey, imageTag: imageTag}
+
+	h.routes = []route{
+		/* -- Chain Routes -- */
+		{http.MethodGet, []string{"chain"}, h.getAllChains},
+		{http.MethodGet, []string{"chain", "*"}, h.getChainByID},
+		{http.MethodGet, []string{"chain", "*", "gigastake"}, h.getAllGigastakeAppsByChain},
+		{http.MethodGet, []string{"gigastake"}, h.getAllGigastakeApps},
+		{http.MethodGet, []string{"gigastake", "*"}, h.getGigastakeAppByID},
+		{http.MethodPost, []string{"chain"}, h.createChainAndGigastakeApps},
+		{http.MethodPost, []string{"chain", "gigastake"}, h.createGigastakeApp},
+		{http.MethodPut, []string{"chain", "gigastake", "*"}, h.updateGigastakeApp},
+		{http.MethodPut, []string{"chain", "*"}, h.updateChain},
+		{http.MethodPut, []string{"chain", "*", "activate"}, h.activateChain},
+
+		/* -- Portal App Routes -- */
+		{http.MethodGet, []string{"portal_app"}, h.getAllPortalApps},
+		{http.MethodGet, []string{"portal_app", "*"}, h.getPortalAppByID},
+		{http.MethodGet, []string{"middleware", "portal_app"}, h.getPortalAppsForMiddleware},
+		{http.MethodPost, []string{"portal_app"}, h.createPortalApp},
+		{http.MethodPost, []string{"portal_app", "first_date_surpassed"}, h.updatePortalAppsFirstDateSurpassed},
+		{http.MethodPut, []string{"portal_app", "*"}, h.updatePortalApp},
+		{http.MethodDelete, []string{"portal_app", "*"}, h.deletePortalApp},
+
+		/* -- Account Routes -- */
+		{http.MethodGet, []string{"account"}, h.getAllAccounts},
+		{http.MethodPost, []string{"account", "user"}, h.writeAccountUser},
+		{http.MethodPut, []string{"account", "user", "update_role"}, h.setAccountUserRole},
+		{http.MethodPut, []string{"account", "user", "accept"}, h.updateAcceptAccountUser},
+		{http.MethodPut, []string{"account", "user", "remove"}, h.removeAccountUser},
+		{http.MethodPut, []string{"account", "*"}, h.updateAccount},
+		{http.MethodDelete, []string{"account", "*"}, h.deleteAccount},
+		{http.MethodPost, []string{"account", "*", "integration"}, h.createAccountIntegration},
+		{http.MethodPut, []string{"account", "*", "integration"}, h.updateAccountIntegration},
+
+		/* -- User Routes -- */
+		{http.MethodGet, []string{"user", "*"}, h.getPortalUser},
+		{http.MethodGet, []string{"user", "*", "portal_app"}, h.getPortalAppsByUser},
+		{http.MethodGet, []string{"user", "*", "account"}, h.getUserAccounts},
+		{http.MethodGet, []string{"user", "*", "account", "*"}, h.getUserAccount},
+		{http.MethodPost, []string{"user"}, h.createUser},
+		{http.MethodPost, []string{"user", "*", "account"}, h.createAccount},
+		{http.MethodPut, []string{"user"}, h.updateUser},
+		{http.MethodDelete, []string{"user", "*"}, h.deleteUser},
+
+		/* -- Plan Routes -- */
+		{http.MethodGet, []string{"plan"}, h.getAllPlans},
+
+		/* -- Blocked Contract Routes -- */
+		{http.MethodGet, []string{"blocked_contract"}, h.getBlockedContracts},
+		{http.MethodPost, []string{"blocked_contract"}, h.writeBlockedContract},
+		{http.MethodPut, []string{"blocked_contract", "*", "active"}, h.updateBlockedContractActive},
+		{http.MethodDelete, []string{"blocked_contract", "*"}, h.removeBlockedContract},
+	}
+
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == healthCheckPath {
+		_, _ = fmt.Fprintf(w, "DB Check Done. Portal HTTP DB is up and running!\nImage Tag: %s", h.imageTag)
+		return
+	}
+
+	if r.Header.Get("Authorization") != h.apiKey {
+		writeError(w, http.StatusUnauthorized, errUnauthorized)
+		return
+	}
+
+	segments, ok := pathSegments(r.URL)
+	if !ok {
+		writeError(w, http.StatusNotFound, errRouteNotFound)
+		return
+	}
+
+	// Literal segments take precedence over wildcards, eg. `/v2/chain/gigastake` over `/v2/chain/{id}`
+	var matched *route
+	var params []string
+	for i := range h.routes {
+		if h.routes[i].method != r.Method {
+			continue
+		}
+		routeParams, ok := h.routes[i].match(segments)
+		if ok && (matched == nil || len(routeParams) < len(params)) {
+			matched, params = &h.routes[i], routeParams
+		}
+	}
+	if matched == nil {
+		writeError(w, http.StatusNotFound, errRouteNotFound)
+		return
+	}
+
+	matched.handle(w, r, params)
+}
+
+// match reports whether the path segments match the route pattern, returning the wildcard values
+func (rt route) match(segments []string) ([]string, bool) {
+	if len(segments) != len(rt.pattern) {
+		return nil, false
+	}
+
+	params := make([]string, 0)
+	for i, part := range rt.pattern {
+		switch {
+		case part == "*" && segments[i] != "":
+			params = append(params, segments[i])
+		case part != segments[i]:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+/* ------------ Chain Handlers ------------ */
+
+func (h *handler) getAllChains(w http.ResponseWriter, r *http.Request, _ []string) {
+	var options dbclient.ChainOptions
+	err := parseBoolParams(r, map[string]**bool{
+		"include_inactive":       &options.IncludeInactive,
+		"exclude_gigastake_apps": &options.ExcludeGigastakeApps,
+		"include_deleted":        &options.IncludeDeleted,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondPage(w, r, func(limit int, cursor string) *dbclient.Iterator[types.Chain] {
+		options.Limit, options.Cursor = limit, cursor
+		return h.db.ListChains(r.Context(), options)
+	}, func(chain *types.Chain) string { return string(chain.ID) })
+}
+
+func (h *handler) getChainByID(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.GetChainByID(r.Context(), types.RelayChainID(params[0])))
+}
+
+func (h *handler) getAllGigastakeAppsByChain(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.GetAllGigastakeAppsByChain(r.Context(), types.RelayChainID(params[0])))
+}
+
+func (h *handler) getAllGigastakeApps(w http.ResponseWriter, r *http.Request, _ []string) {
+	var options dbclient.GigastakeAppOptions
+	err := parseBoolParams(r, map[string]**bool{"include_deleted": &options.IncludeDeleted})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondPage(w, r, func(limit int, cursor string) *dbclient.Iterator[types.GigastakeApp] {
+		options.Limit, options.Cursor = limit, cursor
+		return h.db.ListGigastakeApps(r.Context(), options)
+	}, func(gigastakeApp *types.GigastakeApp) string { return string(gigastakeApp.ID) })
+}
+
+func (h *handler) getGigastakeAppByID(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.GetGigastakeAppByID(r.Context(), types.GigastakeAppID(params[0])))
+}
+
+func (h *handler) createChainAndGigastakeApps(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, input types.NewChainInput) (any, error) {
+		return h.db.CreateChainAndGigastakeApps(ctx, input)
+	})
+}
+
+func (h *handler) createGigastakeApp(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, input types.GigastakeApp) (any, error) {
+		return h.db.CreateGigastakeApp(ctx, input)
+	})
+}
+
+func (h *handler) updateChain(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, update types.UpdateChain) (any, error) {
+		update.ID = types.RelayChainID(params[0])
+		ctx, err := ifMatchContext(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return h.db.UpdateChain(ctx, update)
+	})
+}
+
+func (h *handler) updateGigastakeApp(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, update types.UpdateGigastakeApp) (any, error) {
+		return h.db.UpdateGigastakeApp(ctx, types.GigastakeAppID(params[0]), update)
+	})
+}
+
+func (h *handler) activateChain(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, active bool) (any, error) {
+		return h.db.ActivateChain(ctx, types.RelayChainID(params[0]), active)
+	})
+}
+
+/* ------------ Portal App Handlers ------------ */
+
+func (h *handler) getAllPortalApps(w http.ResponseWriter, r *http.Request, _ []string) {
+	var options dbclient.PortalAppOptions
+	err := parseBoolParams(r, map[string]**bool{"include_deleted": &options.IncludeDeleted})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondPage(w, r, func(limit int, cursor string) *dbclient.Iterator[types.PortalApp] {
+		options.Limit, options.Cursor = limit, cursor
+		return h.db.ListPortalApps(r.Context(), options)
+	}, func(portalApp *types.PortalApp) string { return string(portalApp.ID) })
+}
+
+func (h *handler) getPortalAppByID(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.GetPortalAppByID(r.Context(), types.PortalAppID(params[0])))
+}
+
+func (h *handler) getPortalAppsForMiddleware(w http.ResponseWriter, r *http.Request, _ []string) {
+	respond(w)(h.db.GetPortalAppsForMiddleware(r.Context()))
+}
+
+func (h *handler) createPortalApp(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, input types.PortalApp) (any, error) {
+		return h.db.CreatePortalApp(ctx, input)
+	})
+}
+
+func (h *handler) updatePortalAppsFirstDateSurpassed(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, update types.UpdateFirstDateSurpassed) (any, error) {
+		return h.db.UpdatePortalAppsFirstDateSurpassed(ctx, update)
+	})
+}
+
+func (h *handler) updatePortalApp(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, update types.UpdatePortalApp) (any, error) {
+		update.AppID = types.PortalAppID(params[0])
+		ctx, err := ifMatchContext(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return h.db.UpdatePortalApp(ctx, update)
+	})
+}
+
+func (h *handler) deletePortalApp(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.DeletePortalApp(r.Context(), types.PortalAppID(params[0])))
+}
+
+/* ------------ Account Handlers ------------ */
+
+func (h *handler) getAllAccounts(w http.ResponseWriter, r *http.Request, _ []string) {
+	var options dbclient.AccountOptions
+	err := parseBoolParams(r, map[string]**bool{"include_deleted": &options.IncludeDeleted})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respondPage(w, r, func(limit int, cursor string) *dbclient.Iterator[types.Account] {
+		options.Limit, options.Cursor = limit, cursor
+		return h.db.ListAccounts(r.Context(), options)
+	}, func(account *types.Account) string { return string(account.ID) })
+}
+
+func (h *handler) updateAccount(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, update types.UpdateAccount) (any, error) {
+		update.AccountID = types.AccountID(params[0])
+		ctx, err := ifMatchContext(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return h.db.UpdateAccount(ctx, update)
+	})
+}
+
+func (h *handler) deleteAccount(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.DeleteAccount(r.Context(), types.AccountID(params[0])))
+}
+
+func (h *handler) createAccountIntegration(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, integration types.AccountIntegrations) (any, error) {
+		return h.db.CreateAccountIntegration(ctx, types.AccountID(params[0]), integration)
+	})
+}
+
+func (h *handler) updateAccountIntegration(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, integration types.AccountIntegrations) (any, error) {
+		return h.db.UpdateAccountIntegration(ctx, types.AccountID(params[0]), integration)
+	})
+}
+
+func (h *handler) writeAccountUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, createUser types.CreateAccountUserAccess) (any, error) {
+		return h.db.WriteAccountUser(ctx, createUser, time.Now())
+	})
+}
+
+func (h *handler) setAccountUserRole(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, updateUser types.UpdateAccountUserRole) (any, error) {
+		return h.db.SetAccountUserRole(ctx, updateUser, time.Now())
+	})
+}
+
+func (h *handler) updateAcceptAccountUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, acceptUser types.UpdateAcceptAccountUser) (any, error) {
+		return h.db.UpdateAcceptAccountUser(ctx, acceptUser, time.Now())
+	})
+}
+
+func (h *handler) removeAccountUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, removeUser types.UpdateRemoveAccountUser) (any, error) {
+		return h.db.RemoveAccountUser(ctx, removeUser)
+	})
+}
+
+/* ------------ User Handlers ------------ */
+
+// getPortalUser returns the full user when `full_details=true` is set, otherwise only the portal user ID
+func (h *handler) getPortalUser(w http.ResponseWriter, r *http.Request, params []string) {
+	if r.URL.Query().Get("full_details") == "true" {
+		respond(w)(h.db.GetPortalUser(r.Context(), params[0]))
+		return
+	}
+
+	respond(w)(h.db.GetPortalUserID(r.Context(), params[0]))
+}
+
+func (h *handler) getPortalAppsByUser(w http.ResponseWriter, r *http.Request, params []string) {
+	var options dbclient.PortalAppOptions
+	err := parseBoolParams(r, map[string]**bool{
+		"include_deleted": &options.IncludeDeleted,
+		"accepted":        &options.Accepted,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	options.RoleNameFilters = parseRoleNameFilters(r)
+
+	respond(w)(h.db.GetPortalAppsByUser(r.Context(), types.UserID(params[0]), options))
+}
+
+func (h *handler) getUserAccounts(w http.ResponseWriter, r *http.Request, params []string) {
+	options, err := parseAccountOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respond(w)(h.db.GetUserAccounts(r.Context(), types.UserID(params[0]), options))
+}
+
+func (h *handler) getUserAccount(w http.ResponseWriter, r *http.Request, params []string) {
+	options, err := parseAccountOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	respond(w)(h.db.GetUserAccount(r.Context(), types.AccountID(params[1]), types.UserID(params[0]), options))
+}
+
+func (h *handler) createUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, user types.CreateUser) (any, error) {
+		return h.db.CreateUser(ctx, user)
+	})
+}
+
+func (h *handler) createAccount(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, account types.Account) (any, error) {
+		return h.db.CreateAccount(ctx, types.UserID(params[0]), account, time.Now())
+	})
+}
+
+func (h *handler) updateUser(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, user types.UpdateUser) (any, error) {
+		ctx, err := ifMatchContext(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+		return h.db.UpdateUser(ctx, user)
+	})
+}
+
+func (h *handler) deleteUser(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.DeleteUser(r.Context(), types.UserID(params[0])))
+}
+
+/* ------------ Plan Handlers ------------ */
+
+func (h *handler) getAllPlans(w http.ResponseWriter, r *http.Request, _ []string) {
+	respond(w)(h.db.GetAllPlans(r.Context()))
+}
+
+/* ------------ Blocked Contract Handlers ------------ */
+
+func (h *handler) getBlockedContracts(w http.ResponseWriter, r *http.Request, _ []string) {
+	respond(w)(h.db.GetBlockedContracts(r.Context()))
+}
+
+func (h *handler) writeBlockedContract(w http.ResponseWriter, r *http.Request, _ []string) {
+	withBody(w, r, func(ctx context.Context, blockedContract types.BlockedContract) (any, error) {
+		return h.db.WriteBlockedContract(ctx, blockedContract)
+	})
+}
+
+func (h *handler) updateBlockedContractActive(w http.ResponseWriter, r *http.Request, params []string) {
+	withBody(w, r, func(ctx context.Context, activeStatus struct {
+		Active bool `json:"active"`
+	}) (any, error) {
+		return h.db.UpdateBlockedContractActive(ctx, types.BlockedAddress(params[0]), activeStatus.Active)
+	})
+}
+
+func (h *handler) removeBlockedContract(w http.ResponseWriter, r *http.Request, params []string) {
+	respond(w)(h.db.RemoveBlockedContract(r.Context(), types.BlockedAddress(params[0])))
+}
+
+/* ------------ Helpers ------------ */
+
+// respond returns a func writing either the result or the error of a db call, for use as `respond(w)(h.db.X(...))`
+func respond(w http.ResponseWriter) func(result any, err error) {
+	return func(result any, err error) {
+		if err != nil {
+			writeDBError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// respondPage writes the result of a list route. Without a `limit` param every item is written as a plain array,
+// otherwise a single page is written with the cursor of the next one, found by listing one item beyond the limit.
+func respondPage[T any](w http.ResponseWriter, r *http.Request, list func(limit int, cursor string) *dbclient.Iterator[T], cursorOf func(*T) string) {
+	query := r.URL.Query()
+
+	if !query.Has("limit") {
+		respond(w)(collect(list(0, query.Get("cursor")), 0))
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		writeError(w, http.StatusBadRequest, errInvalidLimit)
+		return
+	}
+
+	items, err := collect(list(limit+1, query.Get("cursor")), limit+1)
+	if err != nil {
+		writeDBError(w, err)
+		return
+	}
+
+	result := page[T]{Data: items}
+	if len(items) > limit {
+		result.Data = items[:limit]
+		result.NextCursor = cursorOf(items[limit-1])
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// collect returns up to limit items of the iterator, or every item if limit is zero
+func collect[T any](items *dbclient.Iterator[T], limit int) ([]*T, error) {
+	defer items.Close()
+
+	result := make([]*T, 0)
+	for (limit == 0 || len(result) < limit) && items.Next() {
+		result = append(result, items.Value())
+	}
+	return result, items.Err()
+}
+
+// withBody decodes the JSON request body into T and writes the result of call
+func withBody[T any](w http.ResponseWriter, r *http.Request, call func(ctx context.Context, input T) (any, error)) {
+	var input T
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w: %s", errInvalidBody, err))
+		return
+	}
+
+	respond(w)(call(r.Context(), input))
+}
+
+// ifMatchContext returns a context expecting the UpdatedAt of the If-Match header, if the request has one
+func ifMatchContext(ctx context.Context, r *http.Request) (context.Context, error) {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return ctx, nil
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, strings.Trim(ifMatch, `"`))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidIfMatch, err)
+	}
+	return dbclient.WithIfMatch(ctx, updatedAt), nil
+}
+
+// writeDBError writes an APIError with its own status code and message; any other error is a validation failure
+func writeDBError(w http.ResponseWriter, err error) {
+	var apiErr *dbclient.APIError
+	if errors.As(err, &apiErr) {
+		writeJSON(w, apiErr.StatusCode, map[string]string{"error": apiErr.Message})
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, err)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, err error) {
+	writeJSON(w, statusCode, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// pathSegments splits the escaped path after `/v2/` and unescapes each segment, so IDs may contain `/`
+func pathSegments(u *url.URL) ([]string, bool) {
+	escapedPath := u.EscapedPath()
+	if !strings.HasPrefix(escapedPath, v2Prefix) {
+		return nil, false
+	}
+
+	segments := strings.Split(strings.TrimPrefix(escapedPath, v2Prefix), "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, false
+		}
+		segments[i] = unescaped
+	}
+	return segments, true
+}
+
+// parseBoolParams sets each target to the value of its query parameter, leaving it nil when absent
+func parseBoolParams(r *http.Request, targets map[string]**bool) error {
+	query := r.URL.Query()
+	for param, target := range targets {
+		if !query.Has(param) {
+			continue
+		}
+		value, err := strconv.ParseBool(query.Get(param))
+		if err != nil {
+			return fmt.Errorf("%w: %s", errInvalidBoolean, param)
+		}
+		*target = &value
+	}
+	return nil
+}
+
+func parseRoleNameFilters(r *http.Request) []types.RoleName {
+	filters := r.URL.Query().Get("filters")
+	if filters == "" {
+		return nil
+	}
+
+	roleNames := make([]types.RoleName, 0)
+	for _, roleName := range strings.Split(filters, ",") {
+		roleNames = append(roleNames, types.RoleName(roleName))
+	}
+	return roleNames
+}
+
+func parseAccountOptions(r *http.Request) (dbclient.AccountOptions, error) {
+	var options dbclient.AccountOptions
+	err := parseBoolParams(r, map[string]**bool{
+		"include_deleted": &options.IncludeDeleted,
+		"accepted":        &options.Accepted,
+	})
+	options.RoleNameFilters = parseRoleNameFilters(r)
+	return options, err
+}
@@ -0,0 +1,301 @@
+package phdtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *Server {
+	server, err := NewServer(Config{
+		Seed: &dbclient.FakeDBClientSeed{
+			Chains: map[types.RelayChainID]*types.Chain{
+				"0001": {ID: "0001", Active: true},
+				"0002": {ID: "0002", Active: false},
+			},
+			PortalApps: map[types.PortalAppID]*types.PortalApp{
+				"test_app_1": {ID: "test_app_1", AccountID: "account_1", Name: "pokt_app_1"},
+				"test/app 2": {ID: "test/app 2", AccountID: "account_1", Name: "pokt_app_2"},
+			},
+			Accounts: map[types.AccountID]*types.Account{
+				"account_1": {ID: "account_1", Name: "pokt_account_1"},
+			},
+			Users: map[types.UserID]*types.User{
+				"user_1": {
+					ID:    "user_1",
+					Email: "james.holden123@test.com",
+					AuthProviders: map[types.AuthType]types.UserAuthProvider{
+						types.AuthTypeAuth0Username: {Type: types.AuthTypeAuth0Username, ProviderUserID: "auth0|james_holden", Provider: types.AuthProviderAuth0},
+					},
+				},
+			},
+			BlockedContracts: types.GlobalBlockedContracts{
+				BlockedAddresses: map[types.BlockedAddress]struct{}{"0xtest_blocked": {}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_TestdataSeed(t *testing.T) {
+	assert.NotPanics(t, func() { dbclient.NewFakeDBClient(TestdataSeed()) })
+}
+
+func Test_Server_HealthCheck(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/healthz")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "DB Check Done. Portal HTTP DB is up and running!\nImage Tag: development", string(body))
+}
+
+func Test_Server_Routes(t *testing.T) {
+	server := newTestServer(t)
+
+	client, err := dbclient.NewDBClient(server.ClientConfig())
+	assert.NoError(t, err)
+
+	wrongKeyConfig := server.ClientConfig()
+	wrongKeyConfig.APIKey = "wrong_api_key"
+	wrongKeyClient, err := dbclient.NewDBClient(wrongKeyConfig)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		call        func() (any, error)
+		expected    any
+		expectedErr error
+	}{
+		{
+			name: "Should reject requests with the wrong API key",
+			call: func() (any, error) {
+				return wrongKeyClient.GetChainByID(ctx, "0001")
+			},
+			expectedErr: dbclient.ErrUnauthorized,
+		},
+		{
+			name: "Should return a chain by ID",
+			call: func() (any, error) {
+				chain, err := client.GetChainByID(ctx, "0001")
+				return chain.ID, err
+			},
+			expected: types.RelayChainID("0001"),
+		},
+		{
+			name: "Should pass query parameters through as options",
+			call: func() (any, error) {
+				chains, err := client.GetAllChains(ctx, dbclient.ChainOptions{IncludeInactive: dbclient.BoolPtr(true)})
+				return len(chains), err
+			},
+			expected: 2,
+		},
+		{
+			name: "Should list every page of a list route",
+			call: func() (any, error) {
+				portalApps := client.(dbclient.IDBLister).ListPortalApps(ctx, dbclient.PortalAppOptions{Limit: 1})
+				ids := make([]types.PortalAppID, 0)
+				for portalApps.Next() {
+					ids = append(ids, portalApps.Value().ID)
+				}
+				return ids, portalApps.Err()
+			},
+			expected: []types.PortalAppID{"test/app 2", "test_app_1"},
+		},
+		{
+			name: "Should return a 404 APIError for a missing chain",
+			call: func() (any, error) {
+				return client.GetChainByID(ctx, "9999")
+			},
+			expectedErr: dbclient.ErrNotFound,
+		},
+		{
+			name: "Should resolve a provider user ID containing a pipe",
+			call: func() (any, error) {
+				return client.GetPortalUserID(ctx, "auth0|james_holden")
+			},
+			expected: types.UserID("user_1"),
+		},
+		{
+			name: "Should route an ID containing a slash as a single path segment",
+			call: func() (any, error) {
+				portalApp, err := client.GetPortalAppByID(ctx, "test/app 2")
+				return portalApp.Name, err
+			},
+			expected: "pokt_app_2",
+		},
+		{
+			name: "Should return the full user when full details are requested",
+			call: func() (any, error) {
+				user, err := client.GetPortalUser(ctx, "user_1")
+				return user.Email, err
+			},
+			expected: "james.holden123@test.com",
+		},
+		{
+			name: "Should decode write bodies and return the write response",
+			call: func() (any, error) {
+				return client.UpdateBlockedContractActive(ctx, "0xtest_blocked", false)
+			},
+			expected: map[string]bool{"active": false},
+		},
+		{
+			name: "Should route literal segments ahead of wildcards",
+			call: func() (any, error) {
+				gigastakeApp, err := client.CreateGigastakeApp(ctx, types.GigastakeApp{Name: "new_gigastake_app"})
+				return gigastakeApp.Name, err
+			},
+			expected: "new_gigastake_app",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.call()
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+}
+
+func Test_Server_SharedDB(t *testing.T) {
+	db := dbclient.NewFakeDBClient(dbclient.FakeDBClientSeed{})
+
+	server1, err := NewServer(Config{DB: db})
+	assert.NoError(t, err)
+	defer server1.Close()
+	server2, err := NewServer(Config{DB: db})
+	assert.NoError(t, err)
+	defer server2.Close()
+
+	client1, err := dbclient.NewDBClient(server1.ClientConfig())
+	assert.NoError(t, err)
+	client2, err := dbclient.NewDBClient(server2.ClientConfig())
+	assert.NoError(t, err)
+
+	_, err = client1.WriteBlockedContract(context.Background(), types.BlockedContract{BlockedAddress: "0xnew_blocked", Active: true})
+	assert.NoError(t, err)
+
+	// Writes through one server are visible through the other, like two PHD instances sharing a database
+	blockedContracts, err := client2.GetBlockedContracts(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, blockedContracts.BlockedAddresses, types.BlockedAddress("0xnew_blocked"))
+}
+
+func Test_Server_UpdateWithIfMatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		ifMatch        func(updatedAt time.Time) string
+		expectedStatus int
+	}{
+		{
+			name:           "Should update a chain whose UpdatedAt matches If-Match",
+			ifMatch:        dbclient.IfMatchUpdatedAt,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "Should update a chain whose UpdatedAt is the instant of an If-Match in another zone",
+			ifMatch: func(updatedAt time.Time) string {
+				return fmt.Sprintf("%q", updatedAt.In(time.FixedZone("CEST", 2*60*60)).Format(time.RFC3339Nano))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Should reject an update of a chain updated since If-Match",
+			ifMatch:        func(updatedAt time.Time) string { return dbclient.IfMatchUpdatedAt(updatedAt.Add(-time.Second)) },
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "Should reject an invalid If-Match",
+			ifMatch:        func(time.Time) string { return `"yesterday"` },
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t)
+			client, err := dbclient.NewDBClient(server.ClientConfig())
+			assert.NoError(t, err)
+
+			chain, err := client.GetChainByID(context.Background(), "0001")
+			assert.NoError(t, err)
+
+			req, err := http.NewRequest(http.MethodPut, server.URL+"/v2/chain/0001", strings.NewReader(`{"allowedMethods": ["eth_call"]}`))
+			assert.NoError(t, err)
+			req.Header.Set("Authorization", server.ClientConfig().APIKey)
+			req.Header.Set("If-Match", test.ifMatch(chain.UpdatedAt))
+
+			resp, err := http.DefaultClient.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func Test_Server_UpdateAccountWithIfMatch(t *testing.T) {
+	server := newTestServer(t)
+	client, err := dbclient.NewDBClient(server.ClientConfig())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	renamed, stale := "pokt_account_renamed", "pokt_account_stale"
+	updated, err := client.UpdateAccount(ctx, types.UpdateAccount{AccountID: "account_1", Name: &renamed})
+	assert.NoError(t, err)
+
+	_, err = client.UpdateAccount(dbclient.WithIfMatch(ctx, updated.UpdatedAt.Add(-time.Second)), types.UpdateAccount{AccountID: "account_1", Name: &stale})
+	assert.ErrorIs(t, err, dbclient.ErrConflict)
+
+	var conflict *dbclient.ConflictError[types.Account]
+	assert.ErrorAs(t, err, &conflict)
+	assert.Nil(t, conflict.Current, "PHD has no single Account lookup")
+}
+
+func Test_Server_ReadModifyWrite(t *testing.T) {
+	server := newTestServer(t)
+	client, err := dbclient.NewDBClient(server.ClientConfig())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	var writes int
+	err = dbclient.ReadModifyWrite(ctx, 3, func(ctx context.Context) (*types.PortalApp, error) {
+		return client.GetPortalAppByID(ctx, "test_app_1")
+	}, func(ctx context.Context, portalApp *types.PortalApp) error {
+		writes++
+		if writes == 1 {
+			// Another writer updates the Portal App between the read and the write
+			_, err := client.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1", Description: "updated elsewhere"})
+			assert.NoError(t, err)
+		}
+		_, err := client.UpdatePortalApp(dbclient.WithIfMatch(ctx, portalApp.UpdatedAt), types.UpdatePortalApp{AppID: "test_app_1", Name: portalApp.Name + "_renamed"})
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, writes)
+
+	portalApp, err := client.GetPortalAppByID(ctx, "test_app_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pokt_app_1_renamed", portalApp.Name)
+	assert.Equal(t, "updated elsewhere", portalApp.Description)
+}
@@ -0,0 +1,44 @@
+package dbclient_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"github.com/pokt-foundation/db-client/v2/client/phdtest"
+)
+
+// phdTestServerEnv selects the in-process phdtest server as the backend for the E2E suites
+const phdTestServerEnv = "PHD_TEST_SERVER"
+
+// phdBaseURLEnvs receive the URLs of the phdtest instances, read by the E2E suites in place of the test ports
+var phdBaseURLEnvs = []string{"PHD_BASE_URL_1", "PHD_BASE_URL_2"}
+
+// TestMain starts two phdtest instances sharing one database on random local ports when
+// PHD_TEST_SERVER=phdtest is set, so the E2E suites can run without the Docker test environment
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	if os.Getenv(phdTestServerEnv) == "phdtest" {
+		db := dbclient.NewFakeDBClient(phdtest.TestdataSeed())
+
+		for _, env := range phdBaseURLEnvs {
+			server, err := phdtest.NewServer(phdtest.Config{DB: db})
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			defer server.Close()
+
+			if err := os.Setenv(env, server.URL); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+	}
+
+	return m.Run()
+}
@@ -0,0 +1,87 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ifMatchContextKey is the context key holding the UpdatedAt expected by a conditional update
+type ifMatchContextKey struct{}
+
+// WithIfMatch returns a context whose updates are sent with an If-Match header expecting the given UpdatedAt.
+// PHD must support If-Match on the endpoint. If the entity was updated since, it rejects the update and UpdateChain,
+// UpdatePortalApp, UpdateAccount and UpdateUser return a ConflictError, whose Current is always nil for accounts.
+// Conditional updates are neither retried nor failed over to another endpoint.
+func WithIfMatch(ctx context.Context, updatedAt time.Time) context.Context {
+	return context.WithValue(ctx, ifMatchContextKey{}, updatedAt)
+}
+
+// ifMatchFromContext returns the UpdatedAt set with WithIfMatch
+func ifMatchFromContext(ctx context.Context) (time.Time, bool) {
+	updatedAt, ok := ctx.Value(ifMatchContextKey{}).(time.Time)
+	return updatedAt, ok
+}
+
+// IfMatchUpdatedAt returns the If-Match header value sent for the given UpdatedAt, a quoted RFC 3339
+// timestamp in UTC
+func IfMatchUpdatedAt(updatedAt time.Time) string {
+	return fmt.Sprintf("%q", updatedAt.UTC().Format(time.RFC3339Nano))
+}
+
+// isConditional reports whether a request carries an If-Match header. Such a request is sent once, as a resent
+// update that was already applied would fail its own precondition.
+func isConditional(req *http.Request) bool {
+	return req.Header.Get("If-Match") != ""
+}
+
+// withCurrentCopy turns a conflict returned by a conditional update into a ConflictError carrying the current
+// server copy, read with the given func. Current is nil if current is nil or the copy couldn't be read.
+func withCurrentCopy[T any](ctx context.Context, err error, current func(ctx context.Context) (*T, error)) error {
+	var conflict *ConflictError[T]
+	if _, ok := ifMatchFromContext(ctx); !ok || !errors.Is(err, ErrConflict) || errors.As(err, &conflict) {
+		return err
+	}
+
+	conflict = &ConflictError[T]{Err: err}
+	if current != nil {
+		conflict.Current, _ = current(ctx)
+	}
+	return conflict
+}
+
+// ReadModifyWrite reads the current copy of an entity and passes it to write, which should update the entity
+// with a context from WithIfMatch and the UpdatedAt of the copy. If the entity was updated in between,
+// write is called again with the server copy of the ConflictError, or a fresh read if it has none, up to
+// attempts times in total. The error of the last attempt is returned.
+//
+//	err := dbclient.ReadModifyWrite(ctx, 3, func(ctx context.Context) (*types.PortalApp, error) {
+//		return client.GetPortalAppByID(ctx, portalAppID)
+//	}, func(ctx context.Context, portalApp *types.PortalApp) error {
+//		_, err := client.UpdatePortalApp(dbclient.WithIfMatch(ctx, portalApp.UpdatedAt), types.UpdatePortalApp{AppID: portalAppID, Name: "new name"})
+//		return err
+//	})
+func ReadModifyWrite[T any](ctx context.Context, attempts int, read func(ctx context.Context) (*T, error), write func(ctx context.Context, current *T) error) error {
+	current, err := read(ctx)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = write(ctx, current)
+		if err == nil || !errors.Is(err, ErrConflict) || attempt >= attempts {
+			return err
+		}
+
+		var conflict *ConflictError[T]
+		if errors.As(err, &conflict) && conflict.Current != nil {
+			current = conflict.Current
+			continue
+		}
+		if current, err = read(ctx); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WithIfMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		read      func(ctx context.Context, db *FakeDBClient) (time.Time, error)
+		update    func(ctx context.Context, db *FakeDBClient, updatedAt time.Time) error
+		conflict  func(err error) (time.Time, bool)
+		noCurrent bool
+	}{
+		{
+			name: "Should update a chain only if it is unchanged",
+			read: func(ctx context.Context, db *FakeDBClient) (time.Time, error) {
+				chain, err := db.GetChainByID(ctx, "0001")
+				if err != nil {
+					return time.Time{}, err
+				}
+				return chain.UpdatedAt, nil
+			},
+			update: func(ctx context.Context, db *FakeDBClient, updatedAt time.Time) error {
+				_, err := db.UpdateChain(WithIfMatch(ctx, updatedAt), types.UpdateChain{ID: "0001", AllowedMethods: []string{"eth_call"}})
+				return err
+			},
+			conflict: func(err error) (time.Time, bool) {
+				var conflict *ConflictError[types.Chain]
+				if !errors.As(err, &conflict) || conflict.Current == nil {
+					return time.Time{}, false
+				}
+				return conflict.Current.UpdatedAt, true
+			},
+		},
+		{
+			name: "Should update a Portal App only if it is unchanged",
+			read: func(ctx context.Context, db *FakeDBClient) (time.Time, error) {
+				portalApp, err := db.GetPortalAppByID(ctx, "test_app_1")
+				if err != nil {
+					return time.Time{}, err
+				}
+				return portalApp.UpdatedAt, nil
+			},
+			update: func(ctx context.Context, db *FakeDBClient, updatedAt time.Time) error {
+				_, err := db.UpdatePortalApp(WithIfMatch(ctx, updatedAt), types.UpdatePortalApp{AppID: "test_app_1", Name: "pokt_app_renamed"})
+				return err
+			},
+			conflict: func(err error) (time.Time, bool) {
+				var conflict *ConflictError[types.PortalApp]
+				if !errors.As(err, &conflict) || conflict.Current == nil {
+					return time.Time{}, false
+				}
+				return conflict.Current.UpdatedAt, true
+			},
+		},
+		{
+			name: "Should update an Account only if it is unchanged",
+			read: func(ctx context.Context, db *FakeDBClient) (time.Time, error) {
+				accounts, err := db.GetAllAccounts(ctx)
+				if err != nil {
+					return time.Time{}, err
+				}
+				for _, account := range accounts {
+					if account.ID == "account_1" {
+						return account.UpdatedAt, nil
+					}
+				}
+				return time.Time{}, ErrNotFound
+			},
+			update: func(ctx context.Context, db *FakeDBClient, updatedAt time.Time) error {
+				name := "pokt_account_renamed"
+				_, err := db.UpdateAccount(WithIfMatch(ctx, updatedAt), types.UpdateAccount{AccountID: "account_1", Name: &name})
+				return err
+			},
+			conflict: func(err error) (time.Time, bool) {
+				var conflict *ConflictError[types.Account]
+				if !errors.As(err, &conflict) || conflict.Current == nil {
+					return time.Time{}, false
+				}
+				return conflict.Current.UpdatedAt, true
+			},
+			noCurrent: true,
+		},
+		{
+			name: "Should update a User only if it is unchanged",
+			read: func(ctx context.Context, db *FakeDBClient) (time.Time, error) {
+				user, err := db.GetPortalUser(ctx, "user_1")
+				if err != nil {
+					return time.Time{}, err
+				}
+				return user.UpdatedAt, nil
+			},
+			update: func(ctx context.Context, db *FakeDBClient, updatedAt time.Time) error {
+				_, err := db.UpdateUser(WithIfMatch(ctx, updatedAt), types.UpdateUser{ID: "user_1", BetaTester: BoolPtr(true)})
+				return err
+			},
+			conflict: func(err error) (time.Time, bool) {
+				var conflict *ConflictError[types.User]
+				if !errors.As(err, &conflict) || conflict.Current == nil {
+					return time.Time{}, false
+				}
+				return conflict.Current.UpdatedAt, true
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestFakeDBClient()
+
+			readAt, err := test.read(ctx, db)
+			assert.NoError(t, err)
+			assert.NoError(t, test.update(ctx, db, readAt.In(time.FixedZone("CEST", 2*60*60))), "the same instant in another zone matches")
+
+			updatedAt, err := test.read(ctx, db)
+			assert.NoError(t, err)
+			assert.True(t, updatedAt.After(readAt))
+
+			err = test.update(ctx, db, readAt)
+			assert.ErrorIs(t, err, ErrConflict)
+			current, ok := test.conflict(err)
+			if test.noCurrent {
+				assert.False(t, ok, "the conflict carries no current copy")
+			} else {
+				assert.True(t, ok, "the conflict carries the current copy")
+				assert.True(t, current.Equal(updatedAt))
+			}
+
+			stillAt, err := test.read(ctx, db)
+			assert.NoError(t, err)
+			assert.True(t, stillAt.Equal(updatedAt), "a conflicting update doesn't change the entity")
+		})
+	}
+}
+
+func Test_WithIfMatch_Requests(t *testing.T) {
+	updatedAt := time.Date(2023, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name             string
+		ifMatch          bool
+		status           int
+		expectedIfMatch  []string
+		expectedErr      error
+		expectedConflict bool
+	}{
+		{
+			name:            "Should send the expected UpdatedAt as If-Match",
+			ifMatch:         true,
+			status:          http.StatusOK,
+			expectedIfMatch: []string{`"2023-05-01T12:00:00Z"`},
+		},
+		{
+			name:             "Should return a ConflictError with the current Portal App for a 412 response",
+			ifMatch:          true,
+			status:           http.StatusPreconditionFailed,
+			expectedIfMatch:  []string{`"2023-05-01T12:00:00Z"`},
+			expectedErr:      ErrConflict,
+			expectedConflict: true,
+		},
+		{
+			name:            "Should neither retry nor fail over a conditional update",
+			ifMatch:         true,
+			status:          http.StatusServiceUnavailable,
+			expectedIfMatch: []string{`"2023-05-01T12:00:00Z"`},
+			expectedErr:     ErrServer,
+		},
+		{
+			name:            "Should not send If-Match with a plain update",
+			status:          http.StatusOK,
+			expectedIfMatch: []string{""},
+		},
+		{
+			name:            "Should fail over a plain update",
+			status:          http.StatusServiceUnavailable,
+			expectedIfMatch: []string{"", "", "", "", "", ""},
+			expectedErr:     ErrServer,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var ifMatches []string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					_, _ = w.Write([]byte(`{"id": "test_app_1", "name": "pokt_app_current"}`))
+					return
+				}
+				mu.Lock()
+				ifMatches = append(ifMatches, r.Header.Get("If-Match"))
+				mu.Unlock()
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(`{"appID": "test_app_1"}`))
+			})
+			primary, secondary := httptest.NewServer(handler), httptest.NewServer(handler)
+			defer primary.Close()
+			defer secondary.Close()
+
+			client, err := NewDBClient(Config{
+				BaseURLs:    []string{primary.URL, secondary.URL},
+				APIKey:      "test_api_key_6789",
+				Timeout:     5 * time.Second,
+				RetryPolicy: &ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond},
+			})
+			assert.NoError(t, err)
+
+			ctx := context.Background()
+			if test.ifMatch {
+				ctx = WithIfMatch(ctx, updatedAt)
+			}
+			_, err = client.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1", Name: "pokt_app_renamed"})
+
+			assert.Equal(t, test.expectedIfMatch, ifMatches)
+			assert.ErrorIs(t, err, test.expectedErr)
+			var conflict *ConflictError[types.PortalApp]
+			assert.Equal(t, test.expectedConflict, errors.As(err, &conflict))
+			if test.expectedConflict {
+				assert.Equal(t, "pokt_app_current", conflict.Current.Name)
+			}
+		})
+	}
+}
+
+func Test_ReadModifyWrite(t *testing.T) {
+	errWrite := errors.New("write failed")
+
+	tests := []struct {
+		name           string
+		attempts       int
+		interferences  int
+		writeErr       error
+		expectedWrites int
+		expectedErr    error
+	}{
+		{
+			name:           "Should write once when the entity is unchanged",
+			attempts:       3,
+			expectedWrites: 1,
+		},
+		{
+			name:           "Should retry with the current copy after a conflict",
+			attempts:       3,
+			interferences:  2,
+			expectedWrites: 3,
+		},
+		{
+			name:           "Should return the conflict once the attempts are exhausted",
+			attempts:       2,
+			interferences:  2,
+			expectedWrites: 2,
+			expectedErr:    ErrConflict,
+		},
+		{
+			name:           "Should not retry other errors",
+			attempts:       3,
+			writeErr:       errWrite,
+			expectedWrites: 1,
+			expectedErr:    errWrite,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			db := newTestFakeDBClient()
+
+			var reads, writes int
+			err := ReadModifyWrite(ctx, test.attempts, func(ctx context.Context) (*types.PortalApp, error) {
+				reads++
+				return db.GetPortalAppByID(ctx, "test_app_1")
+			}, func(ctx context.Context, portalApp *types.PortalApp) error {
+				writes++
+				if test.writeErr != nil {
+					return test.writeErr
+				}
+				if writes <= test.interferences {
+					_, err := db.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1", Description: portalApp.Description + "+"})
+					assert.NoError(t, err)
+				}
+				_, err := db.UpdatePortalApp(WithIfMatch(ctx, portalApp.UpdatedAt), types.UpdatePortalApp{AppID: "test_app_1", Name: portalApp.Name + "_renamed"})
+				return err
+			})
+
+			assert.ErrorIs(t, err, test.expectedErr)
+			assert.Equal(t, test.expectedWrites, writes)
+			assert.Equal(t, 1, reads, "conflicts carry the current copy so it isn't read again")
+
+			portalApp, err := db.GetPortalAppByID(ctx, "test_app_1")
+			assert.NoError(t, err)
+			if test.expectedErr == nil {
+				assert.Equal(t, "pokt_app_1_renamed", portalApp.Name)
+			} else {
+				assert.Equal(t, "pokt_app_1", portalApp.Name)
+			}
+		})
+	}
+}
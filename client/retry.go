@@ -0,0 +1,236 @@
+package dbclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay      = 100 * time.Millisecond
+	defaultRetryMaxDelay       = 5 * time.Second
+	defaultRetryMaxElapsedTime = 30 * time.Second
+)
+
+type (
+	// RetryPolicy decides whether a failed request attempt should be retried and how long to wait before the next attempt
+	RetryPolicy interface {
+		// NextRetry is called after every failed attempt. attempt is the 1-based number of the attempt that just failed
+		// and elapsed is the time since the first attempt was sent. resp is nil when err is set.
+		// It returns the delay before the next attempt and whether a retry should be made at all.
+		NextRetry(req *http.Request, resp *http.Response, err error, attempt int, elapsed time.Duration) (time.Duration, bool)
+	}
+
+	// ExponentialBackoff is the default RetryPolicy. It retries transport errors, 429 and 5xx responses
+	// using exponential backoff with full jitter, respects the Retry-After header of 429 and 503 responses
+	// and only retries idempotent methods unless RetryNonIdempotent is set.
+	ExponentialBackoff struct {
+		// MaxRetries is the maximum number of retries after the first attempt
+		MaxRetries int
+		// BaseDelay is the backoff cap for the first retry, doubled for every following retry. Defaults to 100ms.
+		BaseDelay time.Duration
+		// MaxDelay caps the backoff between two attempts. Defaults to 5s.
+		MaxDelay time.Duration
+		// MaxElapsedTime stops retrying once the next attempt would start later than this after the first one. Defaults to 30s.
+		MaxElapsedTime time.Duration
+		// RetryNonIdempotent allows retrying POST requests, which may create duplicate entities in PHD
+		RetryNonIdempotent bool
+	}
+
+	retryTransport struct {
+		underlying http.RoundTripper
+		policy     RetryPolicy
+		logger     *requestLogger
+		metrics    MetricsRecorder
+		tracer     Tracer
+		limiter    *requestLimiter
+	}
+)
+
+// retryPolicy returns the configured RetryPolicy or the default ExponentialBackoff policy
+func (c Config) retryPolicy() RetryPolicy {
+	if c.RetryPolicy != nil {
+		return c.RetryPolicy
+	}
+	return &ExponentialBackoff{MaxRetries: c.Retries}
+}
+
+// NextRetry implements RetryPolicy
+func (b *ExponentialBackoff) NextRetry(req *http.Request, resp *http.Response, err error, attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if attempt > b.MaxRetries {
+		return 0, false
+	}
+	if !b.retryableMethod(req.Method) {
+		return 0, false
+	}
+	if err == nil && !retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+
+	delay, ok := retryAfter(resp)
+	if !ok {
+		delay = b.backoff(attempt)
+	}
+
+	maxElapsedTime := b.MaxElapsedTime
+	if maxElapsedTime == 0 {
+		maxElapsedTime = defaultRetryMaxElapsedTime
+	}
+	if elapsed+delay > maxElapsedTime {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// backoff returns a random delay between zero and the exponential backoff cap for the given attempt
+func (b *ExponentialBackoff) backoff(attempt int) time.Duration {
+	baseDelay, maxDelay := b.BaseDelay, b.MaxDelay
+	if baseDelay == 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	if maxDelay == 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	backoffCap := baseDelay
+	for i := 1; i < attempt && backoffCap < maxDelay; i++ {
+		backoffCap *= 2
+	}
+	backoffCap = min(backoffCap, maxDelay)
+
+	return time.Duration(rand.Int63n(int64(backoffCap) + 1))
+}
+
+func (b *ExponentialBackoff) retryableMethod(method string) bool {
+	return isIdempotentMethod(method) || b.RetryNonIdempotent
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= http.StatusInternalServerError && code != http.StatusNotImplemented)
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response, given either in seconds or as an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	rt := t.underlying
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	policy := t.policy
+	if policy == nil {
+		policy = &ExponentialBackoff{}
+	}
+
+	start := time.Now()
+
+	ctx, operationSpan := t.startOperationSpan(req.Context(), req)
+	defer func() { endSpan(operationSpan, resp, err) }()
+
+	// Report the outcome of the whole call, however it ends
+	var bodyBytes []byte
+	var throttled time.Duration
+	attempts := 0
+	if t.metrics != nil {
+		defer func() {
+			recordMetrics(t.metrics, req, resp, attempts, int64(len(bodyBytes)), throttled, start)
+		}()
+	}
+
+	// Retries of a call keep the in-flight slot and token it waited for
+	if t.limiter != nil {
+		var release func()
+		release, throttled, err = t.limiter.wait(ctx, req.Method)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		start = time.Now()
+	}
+
+	// Cache request body
+	if req.Body != nil {
+		bodyBytes, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	t.logger.logRequest(req, bodyBytes)
+
+	for attempt := 1; ; attempt++ {
+		attempts = attempt
+
+		// Recreate body reader
+		if bodyBytes != nil {
+			req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		}
+
+		attemptReq, attemptSpan := t.startAttemptSpan(ctx, req, attempt)
+		attemptStart := time.Now()
+		resp, err = rt.RoundTrip(attemptReq)
+		endSpan(attemptSpan, resp, err)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			t.logger.logAttempt(req, resp, nil, attempt, time.Since(attemptStart), false)
+			return resp, nil
+		}
+
+		// An open circuit fails fast rather than piling retries onto a struggling host, and a conditional
+		// update is never resent whatever the policy
+		delay, retry := policy.NextRetry(req, resp, err, attempt, time.Since(start))
+		retry = retry && !errors.Is(err, ErrCircuitOpen) && !isConditional(req)
+		t.logger.logAttempt(req, resp, err, attempt, time.Since(attemptStart), retry)
+		if !retry {
+			break
+		}
+
+		// Discard the failed response so its connection can be reused
+		if err == nil {
+			drainBody(resp)
+		}
+
+		// Abort the backoff as soon as the caller's context is done
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// drainBody reads the remainder of a response body and closes it
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,218 @@
+package dbclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExponentialBackoff_NextRetry(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        ExponentialBackoff
+		method        string
+		statusCode    int
+		retryAfter    string
+		err           error
+		attempt       int
+		elapsed       time.Duration
+		expectedRetry bool
+		expectedDelay time.Duration
+		maxDelay      time.Duration
+	}{
+		{
+			name:          "Should retry a GET returning 500 with a jittered delay below the base delay",
+			policy:        ExponentialBackoff{MaxRetries: 3, BaseDelay: 100 * time.Millisecond},
+			method:        http.MethodGet,
+			statusCode:    http.StatusInternalServerError,
+			attempt:       1,
+			expectedRetry: true,
+			maxDelay:      100 * time.Millisecond,
+		},
+		{
+			name:          "Should double the backoff cap for every attempt",
+			policy:        ExponentialBackoff{MaxRetries: 5, BaseDelay: 100 * time.Millisecond},
+			method:        http.MethodGet,
+			statusCode:    http.StatusBadGateway,
+			attempt:       3,
+			expectedRetry: true,
+			maxDelay:      400 * time.Millisecond,
+		},
+		{
+			name:          "Should cap the backoff at MaxDelay",
+			policy:        ExponentialBackoff{MaxRetries: 50, BaseDelay: time.Second, MaxDelay: 2 * time.Second, MaxElapsedTime: time.Hour},
+			method:        http.MethodGet,
+			statusCode:    http.StatusInternalServerError,
+			attempt:       40,
+			expectedRetry: true,
+			maxDelay:      2 * time.Second,
+		},
+		{
+			name:          "Should retry transport errors",
+			policy:        ExponentialBackoff{MaxRetries: 1},
+			method:        http.MethodDelete,
+			err:           errors.New("connection reset by peer"),
+			attempt:       1,
+			expectedRetry: true,
+			maxDelay:      defaultRetryBaseDelay,
+		},
+		{
+			name:          "Should respect Retry-After in seconds for a 429",
+			policy:        ExponentialBackoff{MaxRetries: 1},
+			method:        http.MethodGet,
+			statusCode:    http.StatusTooManyRequests,
+			retryAfter:    "2",
+			attempt:       1,
+			expectedRetry: true,
+			expectedDelay: 2 * time.Second,
+		},
+		{
+			name:          "Should respect Retry-After in seconds for a 503",
+			policy:        ExponentialBackoff{MaxRetries: 1},
+			method:        http.MethodPut,
+			statusCode:    http.StatusServiceUnavailable,
+			retryAfter:    "1",
+			attempt:       1,
+			expectedRetry: true,
+			expectedDelay: time.Second,
+		},
+		{
+			name:       "Should not retry when Retry-After exceeds the max elapsed time",
+			policy:     ExponentialBackoff{MaxRetries: 1, MaxElapsedTime: 10 * time.Second},
+			method:     http.MethodGet,
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: "60",
+			attempt:    1,
+		},
+		{
+			name:       "Should not retry once the max elapsed time has passed",
+			policy:     ExponentialBackoff{MaxRetries: 5, MaxElapsedTime: time.Second},
+			method:     http.MethodGet,
+			statusCode: http.StatusInternalServerError,
+			attempt:    2,
+			elapsed:    2 * time.Second,
+		},
+		{
+			name:       "Should not retry once MaxRetries is reached",
+			policy:     ExponentialBackoff{MaxRetries: 2},
+			method:     http.MethodGet,
+			statusCode: http.StatusInternalServerError,
+			attempt:    3,
+		},
+		{
+			name:       "Should not retry a POST by default",
+			policy:     ExponentialBackoff{MaxRetries: 3},
+			method:     http.MethodPost,
+			statusCode: http.StatusInternalServerError,
+			attempt:    1,
+		},
+		{
+			name:          "Should retry a POST when RetryNonIdempotent is set",
+			policy:        ExponentialBackoff{MaxRetries: 3, RetryNonIdempotent: true},
+			method:        http.MethodPost,
+			statusCode:    http.StatusInternalServerError,
+			attempt:       1,
+			expectedRetry: true,
+			maxDelay:      defaultRetryBaseDelay,
+		},
+		{
+			name:       "Should not retry 501 Not Implemented",
+			policy:     ExponentialBackoff{MaxRetries: 3},
+			method:     http.MethodGet,
+			statusCode: http.StatusNotImplemented,
+			attempt:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "http://localhost/v2/chain", nil)
+
+			var resp *http.Response
+			if test.err == nil {
+				resp = &http.Response{StatusCode: test.statusCode, Header: http.Header{}}
+				if test.retryAfter != "" {
+					resp.Header.Set("Retry-After", test.retryAfter)
+				}
+			}
+
+			delay, retry := test.policy.NextRetry(req, resp, test.err, test.attempt, test.elapsed)
+			assert.Equal(t, test.expectedRetry, retry)
+
+			if test.expectedDelay != 0 {
+				assert.Equal(t, test.expectedDelay, delay)
+			}
+			if test.maxDelay != 0 {
+				assert.GreaterOrEqual(t, delay, time.Duration(0))
+				assert.LessOrEqual(t, delay, test.maxDelay)
+			}
+		})
+	}
+}
+
+func Test_RetryTransport(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           RetryPolicy
+		call             func(client IDBClient) error
+		expectedAttempts int32
+	}{
+		{
+			name:   "Should retry a failing GET request up to MaxRetries",
+			policy: &ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond},
+			call: func(client IDBClient) error {
+				_, err := client.GetChainByID(context.Background(), "0001")
+				return err
+			},
+			expectedAttempts: 3,
+		},
+		{
+			name:   "Should not retry a failing CreatePortalApp by default",
+			policy: &ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond},
+			call: func(client IDBClient) error {
+				_, err := client.CreatePortalApp(context.Background(), types.PortalApp{Name: "test"})
+				return err
+			},
+			expectedAttempts: 1,
+		},
+		{
+			name:   "Should retry a failing CreatePortalApp when the caller opts in",
+			policy: &ExponentialBackoff{MaxRetries: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true},
+			call: func(client IDBClient) error {
+				_, err := client.CreatePortalApp(context.Background(), types.PortalApp{Name: "test"})
+				return err
+			},
+			expectedAttempts: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			defer server.Close()
+
+			client, err := NewDBClient(Config{
+				BaseURL:     server.URL,
+				APIKey:      "test_api_key_6789",
+				Timeout:     5 * time.Second,
+				RetryPolicy: test.policy,
+			})
+			assert.NoError(t, err)
+
+			err = test.call(client)
+			assert.ErrorIs(t, err, ErrServer)
+			assert.Equal(t, test.expectedAttempts, attempts.Load())
+		})
+	}
+}
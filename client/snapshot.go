@@ -0,0 +1,792 @@
+package dbclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+// SnapshotVersion is the version of the snapshot archive format written by ExportSnapshot
+const SnapshotVersion = 1
+
+const (
+	SnapshotKindChain           SnapshotKind = "chain"
+	SnapshotKindGigastakeApp    SnapshotKind = "gigastake_app"
+	SnapshotKindUser            SnapshotKind = "user"
+	SnapshotKindAccount         SnapshotKind = "account"
+	SnapshotKindPortalApp       SnapshotKind = "portal_app"
+	SnapshotKindPlan            SnapshotKind = "plan"
+	SnapshotKindBlockedContract SnapshotKind = "blocked_contract"
+
+	snapshotKindHeader  SnapshotKind = "header"
+	snapshotKindTrailer SnapshotKind = "trailer"
+	// snapshotKindAccountUser is only used for progress keys, account users are part of the account records
+	snapshotKindAccountUser SnapshotKind = "account_user"
+)
+
+type (
+	// SnapshotKind is the kind of entity stored in a snapshot record
+	SnapshotKind string
+
+	// SnapshotRecord is a single entity of a snapshot, stored as one line of the archive
+	SnapshotRecord struct {
+		Kind SnapshotKind `json:"kind"`
+		ID   string       `json:"id"`
+		// Deleted is set for soft-deleted entities, which are only listed when deleted entities are included
+		Deleted bool            `json:"deleted,omitempty"`
+		Data    json.RawMessage `json:"data"`
+	}
+
+	// SnapshotSummary describes a snapshot archive
+	SnapshotSummary struct {
+		Version    int                  `json:"version"`
+		ExportedAt time.Time            `json:"exportedAt"`
+		Counts     map[SnapshotKind]int `json:"counts"`
+		// Checksum is the hex SHA-256 of every line of the archive before the trailer
+		Checksum string `json:"checksum"`
+	}
+
+	// Snapshot is a verified snapshot archive read by ReadSnapshot
+	Snapshot struct {
+		SnapshotSummary
+		Records []SnapshotRecord
+	}
+
+	// ImportOptions configures ImportSnapshot
+	ImportOptions struct {
+		// Progress resumes an interrupted import of the same snapshot; the records it lists as done are skipped
+		Progress *ImportProgress
+		// Checkpoint is called with the progress after every replayed record, eg. to save it to a file
+		Checkpoint func(progress *ImportProgress) error
+	}
+
+	// ImportProgress is the resumable state of an import
+	ImportProgress struct {
+		// Checksum is the checksum of the snapshot being imported
+		Checksum string `json:"checksum"`
+		// Done holds the keys of the replayed records
+		Done map[string]bool `json:"done"`
+		// IDs maps the IDs in the snapshot to the IDs created in the target, by kind
+		IDs map[SnapshotKind]map[string]string `json:"ids"`
+	}
+
+	snapshotHeader struct {
+		Kind       SnapshotKind `json:"kind"`
+		Version    int          `json:"version"`
+		ExportedAt time.Time    `json:"exportedAt"`
+	}
+
+	snapshotTrailer struct {
+		Kind     SnapshotKind         `json:"kind"`
+		Counts   map[SnapshotKind]int `json:"counts"`
+		Checksum string               `json:"checksum"`
+	}
+
+	// snapshotWriter writes the lines of an archive and hashes them
+	snapshotWriter struct {
+		w      *bufio.Writer
+		hash   hash.Hash
+		counts map[SnapshotKind]int
+	}
+
+	// snapshotImporter replays the live records of a snapshot through an IDBWriter
+	snapshotImporter struct {
+		writer   IDBWriter
+		options  ImportOptions
+		progress *ImportProgress
+
+		chains           []recordOf[types.Chain]
+		gigastakeApps    []recordOf[types.GigastakeApp]
+		users            []recordOf[types.User]
+		accounts         []recordOf[types.Account]
+		portalApps       []recordOf[types.PortalApp]
+		blockedContracts []recordOf[types.BlockedContract]
+		usersByID        map[string]*types.User
+	}
+
+	recordOf[T any] struct {
+		id    string
+		value T
+	}
+)
+
+var (
+	errInvalidSnapshot          error = errors.New("invalid snapshot")
+	errUnsupportedSnapshot      error = errors.New("unsupported snapshot version")
+	errSnapshotChecksumMismatch error = errors.New("snapshot checksum mismatch")
+	errSnapshotProgressMismatch error = errors.New("import progress is for a different snapshot")
+	errSnapshotMissingID        error = errors.New("snapshot references an entity that was not imported")
+)
+
+/* -- Export -- */
+
+// ExportSnapshot writes all chains, including inactive and deleted ones, Gigastake apps, Portal Apps, Accounts,
+// the Users of those Accounts, plans and active blocked contracts readable through the reader to w as a JSON-lines
+// archive. Inactive blocked contracts are not exported, as PHD only lists active ones.
+// The archive starts with a versioned header and ends with a trailer holding the record counts and a checksum.
+func ExportSnapshot(ctx context.Context, reader IDBReader, w io.Writer) (*SnapshotSummary, error) {
+	sw := &snapshotWriter{w: bufio.NewWriter(w), hash: sha256.New(), counts: make(map[SnapshotKind]int)}
+	exportedAt := time.Now().UTC()
+
+	if err := sw.writeLine(snapshotHeader{Kind: snapshotKindHeader, Version: SnapshotVersion, ExportedAt: exportedAt}); err != nil {
+		return nil, err
+	}
+
+	err := exportList(sw, SnapshotKindChain,
+		func() *Iterator[types.Chain] {
+			return listChains(ctx, reader, ChainOptions{IncludeInactive: BoolPtr(true), ExcludeGigastakeApps: BoolPtr(true), IncludeDeleted: BoolPtr(true)})
+		},
+		func() *Iterator[types.Chain] {
+			return listChains(ctx, reader, ChainOptions{IncludeInactive: BoolPtr(true), ExcludeGigastakeApps: BoolPtr(true)})
+		},
+		func(chain *types.Chain) string { return string(chain.ID) }, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = exportList(sw, SnapshotKindGigastakeApp,
+		func() *Iterator[types.GigastakeApp] {
+			return listGigastakeApps(ctx, reader, GigastakeAppOptions{IncludeDeleted: BoolPtr(true)})
+		},
+		func() *Iterator[types.GigastakeApp] { return listGigastakeApps(ctx, reader, GigastakeAppOptions{}) },
+		func(gigastakeApp *types.GigastakeApp) string { return string(gigastakeApp.ID) }, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = exportList(sw, SnapshotKindPortalApp,
+		func() *Iterator[types.PortalApp] {
+			return listPortalApps(ctx, reader, PortalAppOptions{IncludeDeleted: BoolPtr(true)})
+		},
+		func() *Iterator[types.PortalApp] { return listPortalApps(ctx, reader, PortalAppOptions{}) },
+		func(portalApp *types.PortalApp) string { return string(portalApp.ID) }, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make(map[types.UserID]bool)
+	err = exportList(sw, SnapshotKindAccount,
+		func() *Iterator[types.Account] {
+			return listAccounts(ctx, reader, AccountOptions{IncludeDeleted: BoolPtr(true)})
+		},
+		func() *Iterator[types.Account] { return listAccounts(ctx, reader, AccountOptions{}) },
+		func(account *types.Account) string { return string(account.ID) },
+		func(account *types.Account) {
+			for userID := range account.Users {
+				userIDs[userID] = true
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range sortedKeys(userIDs) {
+		user, err := reader.GetPortalUser(ctx, string(userID))
+		// Users can be removed while the accounts are being read
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := sw.writeRecord(SnapshotKindUser, string(userID), false, user); err != nil {
+			return nil, err
+		}
+	}
+
+	plans, err := reader.GetAllPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if err := sw.writeRecord(SnapshotKindPlan, string(plan.Type), false, plan); err != nil {
+			return nil, err
+		}
+	}
+
+	blockedContracts, err := reader.GetBlockedContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// GetBlockedContracts only lists the active addresses
+	for _, address := range sortedKeys(blockedContracts.BlockedAddresses) {
+		blockedContract := types.BlockedContract{BlockedAddress: address, Active: true}
+		if err := sw.writeRecord(SnapshotKindBlockedContract, string(address), false, blockedContract); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := &SnapshotSummary{
+		Version:    SnapshotVersion,
+		ExportedAt: exportedAt,
+		Counts:     sw.counts,
+		Checksum:   hex.EncodeToString(sw.hash.Sum(nil)),
+	}
+
+	trailer, err := json.Marshal(snapshotTrailer{Kind: snapshotKindTrailer, Counts: summary.Counts, Checksum: summary.Checksum})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sw.w.Write(append(trailer, '\n')); err != nil {
+		return nil, err
+	}
+
+	return summary, sw.w.Flush()
+}
+
+// exportList writes a record for every item of the collection, marking the items missing from live as deleted
+func exportList[T any](sw *snapshotWriter, kind SnapshotKind, all, live func() *Iterator[T], id func(*T) string, each func(*T)) error {
+	return listWithDeleted(all, live, id, func(item *T, deleted bool) error {
+		if err := sw.writeRecord(kind, id(item), deleted, item); err != nil {
+			return err
+		}
+		if each != nil {
+			each(item)
+		}
+		return nil
+	})
+}
+
+// listWithDeleted reads every item of all and then the IDs of live, and calls visit for each item with whether it
+// is deleted, ie. missing from live. Reading all first means an item created in between is listed by live rather
+// than marked deleted; it is visited after the others. The items of all are held in memory until live is read.
+func listWithDeleted[T any](all, live func() *Iterator[T], id func(*T) string, visit func(item *T, deleted bool) error) error {
+	allItems := all()
+	defer allItems.Close()
+
+	items := make([]*T, 0)
+	listed := make(map[string]bool)
+	for allItems.Next() {
+		items = append(items, allItems.Value())
+		listed[id(allItems.Value())] = true
+	}
+	if err := allItems.Err(); err != nil {
+		return err
+	}
+
+	liveItems := live()
+	defer liveItems.Close()
+
+	liveIDs := make(map[string]bool, len(items))
+	created := make([]*T, 0)
+	for liveItems.Next() {
+		item := liveItems.Value()
+		liveIDs[id(item)] = true
+		if !listed[id(item)] {
+			created = append(created, item)
+		}
+	}
+	if err := liveItems.Err(); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := visit(item, !liveIDs[id(item)]); err != nil {
+			return err
+		}
+	}
+	for _, item := range created {
+		if err := visit(item, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sw *snapshotWriter) writeRecord(kind SnapshotKind, id string, deleted bool, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	sw.counts[kind]++
+	return sw.writeLine(SnapshotRecord{Kind: kind, ID: id, Deleted: deleted, Data: data})
+}
+
+func (sw *snapshotWriter) writeLine(value any) error {
+	line, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	sw.hash.Write(line)
+	_, err = sw.w.Write(line)
+	return err
+}
+
+/* -- Read -- */
+
+// ReadSnapshot reads an archive written by ExportSnapshot, verifying its version, counts and checksum
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	br := bufio.NewReader(r)
+	hash := sha256.New()
+	snapshot := &Snapshot{SnapshotSummary: SnapshotSummary{Counts: make(map[SnapshotKind]int)}}
+
+	headerLine, err := readSnapshotLine(br)
+	if err != nil {
+		return nil, err
+	}
+	var header snapshotHeader
+	if err := json.Unmarshal(headerLine, &header); err != nil || header.Kind != snapshotKindHeader {
+		return nil, fmt.Errorf("%w: missing header", errInvalidSnapshot)
+	}
+	if header.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", errUnsupportedSnapshot, header.Version)
+	}
+	snapshot.Version, snapshot.ExportedAt = header.Version, header.ExportedAt
+	hash.Write(headerLine)
+
+	for {
+		line, err := readSnapshotLine(br)
+		if err != nil {
+			return nil, err
+		}
+
+		var record SnapshotRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %s", errInvalidSnapshot, len(snapshot.Records)+2, err)
+		}
+
+		if record.Kind == snapshotKindTrailer {
+			var trailer snapshotTrailer
+			if err := json.Unmarshal(line, &trailer); err != nil {
+				return nil, fmt.Errorf("%w: trailer: %s", errInvalidSnapshot, err)
+			}
+			snapshot.Checksum = hex.EncodeToString(hash.Sum(nil))
+			if trailer.Checksum != snapshot.Checksum || !reflect.DeepEqual(trailer.Counts, snapshot.Counts) {
+				return nil, errSnapshotChecksumMismatch
+			}
+			return snapshot, nil
+		}
+
+		hash.Write(line)
+		snapshot.Counts[record.Kind]++
+		snapshot.Records = append(snapshot.Records, record)
+	}
+}
+
+// readSnapshotLine reads a whole line, however long; an archive ending before the trailer is truncated
+func readSnapshotLine(br *bufio.Reader) ([]byte, error) {
+	line, err := br.ReadBytes('\n')
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: truncated archive", errInvalidSnapshot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return nil, fmt.Errorf("%w: empty line", errInvalidSnapshot)
+	}
+	return line, nil
+}
+
+/* -- Import -- */
+
+// ImportSnapshot replays the live entities of a snapshot through the writer in dependency order: chains,
+// Gigastake apps, users, Accounts, Portal Apps, Account users and blocked contracts. Deleted entities and plans,
+// which have no writer, are not replayed.
+//
+// PHD assigns new IDs to everything but chains, so the returned progress maps the snapshot IDs to the new ones.
+// Users are created through CreateUser, which also creates a personal Account; it stands in for the user's
+// earliest owned free tier Account of the snapshot. Users who never signed up are created by their invites.
+//
+// If the import fails, calling ImportSnapshot again with the returned progress resumes it. A record that
+// failed mid-write may be written twice.
+func ImportSnapshot(ctx context.Context, writer IDBWriter, snapshot *Snapshot, options ImportOptions) (*ImportProgress, error) {
+	progress := options.Progress
+	if progress == nil {
+		progress = &ImportProgress{Checksum: snapshot.Checksum}
+	}
+	if progress.Checksum != snapshot.Checksum {
+		return progress, errSnapshotProgressMismatch
+	}
+	if progress.Done == nil {
+		progress.Done = make(map[string]bool)
+	}
+	if progress.IDs == nil {
+		progress.IDs = make(map[SnapshotKind]map[string]string)
+	}
+
+	importer := &snapshotImporter{writer: writer, options: options, progress: progress, usersByID: make(map[string]*types.User)}
+	if err := importer.decode(snapshot.Records); err != nil {
+		return progress, err
+	}
+
+	steps := []func(context.Context) error{
+		importer.importChains,
+		importer.importGigastakeApps,
+		importer.importUsers,
+		importer.importAccounts,
+		importer.importPortalApps,
+		importer.importAccountUsers,
+		importer.importBlockedContracts,
+	}
+	for _, step := range steps {
+		if err := step(ctx); err != nil {
+			return progress, err
+		}
+	}
+
+	return progress, nil
+}
+
+// decode decodes the live records by kind
+func (i *snapshotImporter) decode(records []SnapshotRecord) error {
+	for _, record := range records {
+		if record.Deleted {
+			continue
+		}
+
+		var err error
+		switch record.Kind {
+		case SnapshotKindChain:
+			i.chains, err = appendRecord(i.chains, record)
+		case SnapshotKindGigastakeApp:
+			i.gigastakeApps, err = appendRecord(i.gigastakeApps, record)
+		case SnapshotKindUser:
+			i.users, err = appendRecord(i.users, record)
+		case SnapshotKindAccount:
+			i.accounts, err = appendRecord(i.accounts, record)
+		case SnapshotKindPortalApp:
+			i.portalApps, err = appendRecord(i.portalApps, record)
+		case SnapshotKindBlockedContract:
+			i.blockedContracts, err = appendRecord(i.blockedContracts, record)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s %s: %s", errInvalidSnapshot, record.Kind, record.ID, err)
+		}
+	}
+
+	for index := range i.users {
+		i.usersByID[i.users[index].id] = &i.users[index].value
+	}
+
+	return nil
+}
+
+func appendRecord[T any](records []recordOf[T], record SnapshotRecord) ([]recordOf[T], error) {
+	var value T
+	if err := json.Unmarshal(record.Data, &value); err != nil {
+		return records, err
+	}
+	return append(records, recordOf[T]{id: record.ID, value: value}), nil
+}
+
+func (i *snapshotImporter) importChains(ctx context.Context) error {
+	for _, record := range i.chains {
+		chain := record.value
+		err := i.replay(SnapshotKindChain, record.id, func() error {
+			chain.GigastakeApps = nil
+			created, err := i.writer.CreateChainAndGigastakeApps(ctx, types.NewChainInput{Chain: &chain})
+			if err != nil {
+				return err
+			}
+			i.setID(SnapshotKindChain, record.id, string(created.Chain.ID))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *snapshotImporter) importGigastakeApps(ctx context.Context) error {
+	for _, record := range i.gigastakeApps {
+		gigastakeApp := record.value
+		err := i.replay(SnapshotKindGigastakeApp, record.id, func() error {
+			chainIDs := make(map[types.RelayChainID]struct{}, len(gigastakeApp.ChainIDs))
+			for chainID := range gigastakeApp.ChainIDs {
+				newChainID, err := i.id(SnapshotKindChain, string(chainID))
+				if err != nil {
+					return err
+				}
+				chainIDs[types.RelayChainID(newChainID)] = struct{}{}
+			}
+			gigastakeApp.ID, gigastakeApp.ChainIDs = "", chainIDs
+
+			created, err := i.writer.CreateGigastakeApp(ctx, gigastakeApp)
+			if err != nil {
+				return err
+			}
+			i.setID(SnapshotKindGigastakeApp, record.id, string(created.ID))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *snapshotImporter) importUsers(ctx context.Context) error {
+	for _, record := range i.users {
+		user := record.value
+		provider, ok := firstAuthProvider(&user)
+		if !ok {
+			continue
+		}
+
+		err := i.replay(SnapshotKindUser, record.id, func() error {
+			created, err := i.writer.CreateUser(ctx, types.CreateUser{Email: user.Email, ProviderUserID: provider.ProviderUserID})
+			if err != nil {
+				return err
+			}
+			i.setID(SnapshotKindUser, record.id, string(created.User.ID))
+			if accountID, ok := i.personalAccount(record.id); ok {
+				i.setID(SnapshotKindAccount, accountID, string(created.AccountID))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// personalAccount returns the user's earliest owned free tier Account that isn't mapped yet
+func (i *snapshotImporter) personalAccount(userID string) (string, bool) {
+	var personal *recordOf[types.Account]
+	for index, record := range i.accounts {
+		access, ok := record.value.Users[types.UserID(userID)]
+		if !ok || !access.Owner || record.value.PlanType != types.FreetierV0 || i.progress.IDs[SnapshotKindAccount][record.id] != "" {
+			continue
+		}
+		if personal == nil || record.value.CreatedAt.Before(personal.value.CreatedAt) {
+			personal = &i.accounts[index]
+		}
+	}
+	if personal == nil {
+		return "", false
+	}
+	return personal.id, true
+}
+
+func (i *snapshotImporter) importAccounts(ctx context.Context) error {
+	for _, record := range i.accounts {
+		account := record.value
+		err := i.replay(SnapshotKindAccount, record.id, func() error {
+			accountID := i.progress.IDs[SnapshotKindAccount][record.id]
+			if accountID != "" {
+				// The personal account created with the owner only needs its name and plan restored
+				_, err := i.writer.UpdateAccount(ctx, types.UpdateAccount{AccountID: types.AccountID(accountID), Name: &account.Name, PlanType: account.PlanType})
+				if err != nil {
+					return err
+				}
+			} else {
+				ownerID, err := i.accountOwner(&account)
+				if err != nil {
+					return err
+				}
+
+				account.ID, account.Users, account.PortalApps, account.Plan = "", nil, nil, nil
+				created, err := i.writer.CreateAccount(ctx, types.UserID(ownerID), account, account.CreatedAt)
+				if err != nil {
+					return err
+				}
+				accountID = string(created.ID)
+				i.setID(SnapshotKindAccount, record.id, accountID)
+			}
+
+			integrations := account.Integrations
+			integrations.AccountID = ""
+			if reflect.ValueOf(integrations).IsZero() {
+				return nil
+			}
+			_, err := i.writer.CreateAccountIntegration(ctx, types.AccountID(accountID), integrations)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *snapshotImporter) accountOwner(account *types.Account) (string, error) {
+	for userID, access := range account.Users {
+		if access.Owner {
+			return i.id(SnapshotKindUser, string(userID))
+		}
+	}
+	return "", fmt.Errorf("%w: account %s has no owner", errSnapshotMissingID, account.ID)
+}
+
+func (i *snapshotImporter) importPortalApps(ctx context.Context) error {
+	for _, record := range i.portalApps {
+		portalApp := record.value
+		err := i.replay(SnapshotKindPortalApp, record.id, func() error {
+			accountID, err := i.id(SnapshotKindAccount, string(portalApp.AccountID))
+			if err != nil {
+				return err
+			}
+			portalApp.ID, portalApp.AccountID, portalApp.Users = "", types.AccountID(accountID), nil
+
+			created, err := i.writer.CreatePortalApp(ctx, portalApp)
+			if err != nil {
+				return err
+			}
+			i.setID(SnapshotKindPortalApp, record.id, string(created.ID))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importAccountUsers invites every Account user to their Portal Apps with their role, then accepts the
+// invite for users who had accepted it and have an auth provider. Roles on deleted Portal Apps are skipped.
+func (i *snapshotImporter) importAccountUsers(ctx context.Context) error {
+	for _, record := range i.accounts {
+		for _, userID := range sortedKeys(record.value.Users) {
+			access := record.value.Users[userID]
+			for _, portalAppID := range sortedKeys(access.PortalAppRoles) {
+				if _, ok := i.progress.IDs[SnapshotKindPortalApp][string(portalAppID)]; !ok {
+					continue
+				}
+
+				key := fmt.Sprintf("%s/%s/%s", record.id, userID, portalAppID)
+				err := i.replay(snapshotKindAccountUser, key, func() error {
+					return i.importAccountUser(ctx, record.id, access, portalAppID)
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (i *snapshotImporter) importAccountUser(ctx context.Context, accountID string, access types.AccountUserAccess, portalAppID types.PortalAppID) error {
+	newAccountID, err := i.id(SnapshotKindAccount, accountID)
+	if err != nil {
+		return err
+	}
+	newPortalAppID, err := i.id(SnapshotKindPortalApp, string(portalAppID))
+	if err != nil {
+		return err
+	}
+
+	user := i.usersByID[string(access.UserID)]
+	email := access.Email
+	if email == "" && user != nil {
+		email = user.Email
+	}
+
+	created, err := i.writer.WriteAccountUser(ctx, types.CreateAccountUserAccess{
+		AccountID:   types.AccountID(newAccountID),
+		PortalAppID: types.PortalAppID(newPortalAppID),
+		Email:       email,
+		RoleName:    access.PortalAppRoles[portalAppID],
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+	newUserID := created["userID"]
+	if i.progress.IDs[SnapshotKindUser][string(access.UserID)] == "" {
+		i.setID(SnapshotKindUser, string(access.UserID), string(newUserID))
+	}
+
+	if !access.PortalAppsAccepted[portalAppID] || user == nil {
+		return nil
+	}
+	provider, ok := firstAuthProvider(user)
+	if !ok {
+		return nil
+	}
+
+	_, err = i.writer.UpdateAcceptAccountUser(ctx, types.UpdateAcceptAccountUser{
+		PortalAppID:      types.PortalAppID(newPortalAppID),
+		UserID:           newUserID,
+		AuthProviderType: provider.Type,
+		ProviderUserID:   provider.ProviderUserID,
+	}, time.Now())
+	return err
+}
+
+func (i *snapshotImporter) importBlockedContracts(ctx context.Context) error {
+	for _, record := range i.blockedContracts {
+		blockedContract := record.value
+		err := i.replay(SnapshotKindBlockedContract, record.id, func() error {
+			_, err := i.writer.WriteBlockedContract(ctx, blockedContract)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replay runs write unless the record is already done, then marks it done and checkpoints the progress
+func (i *snapshotImporter) replay(kind SnapshotKind, id string, write func() error) error {
+	key := progressKey(kind, id)
+	if i.progress.Done[key] {
+		return nil
+	}
+
+	if err := write(); err != nil {
+		return fmt.Errorf("importing %s: %w", key, err)
+	}
+	i.progress.Done[key] = true
+
+	if i.options.Checkpoint != nil {
+		return i.options.Checkpoint(i.progress)
+	}
+	return nil
+}
+
+// id returns the ID the entity of the snapshot was imported as
+func (i *snapshotImporter) id(kind SnapshotKind, id string) (string, error) {
+	newID, ok := i.progress.IDs[kind][id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s %s", errSnapshotMissingID, kind, id)
+	}
+	return newID, nil
+}
+
+func (i *snapshotImporter) setID(kind SnapshotKind, id, newID string) {
+	if i.progress.IDs[kind] == nil {
+		i.progress.IDs[kind] = make(map[string]string)
+	}
+	i.progress.IDs[kind][id] = newID
+}
+
+func progressKey(kind SnapshotKind, id string) string {
+	return string(kind) + ":" + id
+}
+
+// firstAuthProvider returns the user's auth provider with the lowest type, so imports are deterministic
+func firstAuthProvider(user *types.User) (types.UserAuthProvider, bool) {
+	authTypes := sortedKeys(user.AuthProviders)
+	if len(authTypes) == 0 {
+		return types.UserAuthProvider{}, false
+	}
+	return user.AuthProviders[authTypes[0]], true
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
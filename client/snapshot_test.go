@@ -0,0 +1,244 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter fails the first CreatePortalApp call and counts chain creations
+type failingWriter struct {
+	*FakeDBClient
+	failPortalApp bool
+	chainsCreated int
+}
+
+func (w *failingWriter) CreateChainAndGigastakeApps(ctx context.Context, newChainInput types.NewChainInput) (*types.NewChainInput, error) {
+	w.chainsCreated++
+	return w.FakeDBClient.CreateChainAndGigastakeApps(ctx, newChainInput)
+}
+
+func (w *failingWriter) CreatePortalApp(ctx context.Context, portalAppInput types.PortalApp) (*types.PortalApp, error) {
+	if w.failPortalApp {
+		w.failPortalApp = false
+		return nil, errors.New("connection reset")
+	}
+	return w.FakeDBClient.CreatePortalApp(ctx, portalAppInput)
+}
+
+// insertingReader creates a Portal App right before the second Portal App page is read, ie. between reading the
+// listings with and without deleted Portal Apps
+type insertingReader struct {
+	*FakeDBClient
+	pages   int
+	created *types.PortalApp
+}
+
+func (r *insertingReader) ListPortalApps(ctx context.Context, options PortalAppOptions) *Iterator[types.PortalApp] {
+	return fakeList(options.Limit, options.Cursor, func(limit int, cursor string) ([]*types.PortalApp, error) {
+		r.pages++
+		if r.pages == 2 {
+			r.created, _ = r.FakeDBClient.CreatePortalApp(ctx, types.PortalApp{AccountID: "account_1", Name: "pokt_app_new"})
+		}
+		options.Limit, options.Cursor = limit, cursor
+		return r.FakeDBClient.listPortalApps(options), nil
+	}, portalAppCursor)
+}
+
+func newTestSnapshot(t *testing.T) []byte {
+	ctx := context.Background()
+	source := newTestFakeDBClient()
+	_, err := source.DeletePortalApp(ctx, "test_app_2")
+	assert.NoError(t, err)
+	_, err = source.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xinactive_blocked", Active: true})
+	assert.NoError(t, err)
+	_, err = source.UpdateBlockedContractActive(ctx, "0xinactive_blocked", false)
+	assert.NoError(t, err)
+
+	var archive bytes.Buffer
+	summary, err := ExportSnapshot(ctx, source, &archive)
+	assert.NoError(t, err)
+	assert.Equal(t, map[SnapshotKind]int{
+		SnapshotKindChain:           2,
+		SnapshotKindGigastakeApp:    1,
+		SnapshotKindPortalApp:       2,
+		SnapshotKindAccount:         1,
+		SnapshotKindUser:            2,
+		SnapshotKindPlan:            1,
+		SnapshotKindBlockedContract: 1,
+	}, summary.Counts)
+
+	return archive.Bytes()
+}
+
+func newTestSnapshotTarget() *FakeDBClient {
+	return NewFakeDBClient(FakeDBClientSeed{
+		Plans: map[types.PayPlanType]*types.Plan{
+			"basic_plan":     {Type: "basic_plan", Name: "Basic Plan"},
+			types.FreetierV0: {Type: types.FreetierV0, Name: "Free Tier"},
+		},
+	})
+}
+
+func Test_ImportSnapshot(t *testing.T) {
+	ctx := context.Background()
+	archive := newTestSnapshot(t)
+
+	snapshot, err := ReadSnapshot(bytes.NewReader(archive))
+	assert.NoError(t, err)
+	assert.Equal(t, SnapshotVersion, snapshot.Version)
+	assert.Len(t, snapshot.Records, 10)
+
+	target := newTestSnapshotTarget()
+	progress, err := ImportSnapshot(ctx, target, snapshot, ImportOptions{})
+	assert.NoError(t, err)
+
+	chains, err := target.GetAllChains(ctx, ChainOptions{IncludeInactive: BoolPtr(true)})
+	assert.NoError(t, err)
+	assert.Len(t, chains, 2)
+
+	gigastakeApp, err := target.GetGigastakeAppByID(ctx, types.GigastakeAppID(progress.IDs[SnapshotKindGigastakeApp]["test_gigastake_app_1"]))
+	assert.NoError(t, err)
+	assert.Equal(t, "pokt_gigastake", gigastakeApp.Name)
+	assert.Contains(t, gigastakeApp.ChainIDs, types.RelayChainID("0001"))
+
+	accountID := types.AccountID(progress.IDs[SnapshotKindAccount]["account_1"])
+	ownerID := types.UserID(progress.IDs[SnapshotKindUser]["user_1"])
+	account, err := target.GetUserAccount(ctx, accountID, ownerID)
+	assert.NoError(t, err)
+	assert.Equal(t, types.PayPlanType("basic_plan"), account.PlanType)
+	assert.True(t, account.Users[ownerID].Owner)
+
+	assert.NotContains(t, progress.IDs[SnapshotKindPortalApp], "test_app_2", "deleted portal apps are not replayed")
+	portalAppID := types.PortalAppID(progress.IDs[SnapshotKindPortalApp]["test_app_1"])
+	assert.Len(t, account.PortalApps, 1)
+	assert.Equal(t, "pokt_app_1", account.PortalApps[portalAppID].Name)
+
+	memberID := types.UserID(progress.IDs[SnapshotKindUser]["user_2"])
+	assert.NotEmpty(t, memberID)
+	assert.Equal(t, types.RoleMember, account.Users[memberID].PortalAppRoles[portalAppID])
+	assert.Equal(t, types.RoleOwner, account.Users[ownerID].PortalAppRoles[portalAppID])
+
+	owner, err := target.GetPortalUser(ctx, string(ownerID))
+	assert.NoError(t, err)
+	assert.True(t, owner.SignedUp)
+
+	blockedContracts, err := target.GetBlockedContracts(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, blockedContracts.BlockedAddresses, types.BlockedAddress("0xtest_blocked"))
+	assert.NotContains(t, blockedContracts.BlockedAddresses, types.BlockedAddress("0xinactive_blocked"), "inactive blocked contracts are not exported")
+}
+
+func Test_ImportSnapshot_Resume(t *testing.T) {
+	ctx := context.Background()
+	snapshot, err := ReadSnapshot(bytes.NewReader(newTestSnapshot(t)))
+	assert.NoError(t, err)
+
+	writer := &failingWriter{FakeDBClient: newTestSnapshotTarget(), failPortalApp: true}
+	var saved []byte
+	checkpoint := func(progress *ImportProgress) error {
+		saved, err = json.Marshal(progress)
+		return err
+	}
+
+	_, err = ImportSnapshot(ctx, writer, snapshot, ImportOptions{Checkpoint: checkpoint})
+	assert.ErrorContains(t, err, "importing portal_app:test_app_1: connection reset")
+
+	var progress ImportProgress
+	assert.NoError(t, json.Unmarshal(saved, &progress))
+	assert.True(t, progress.Done["account:account_1"])
+	assert.False(t, progress.Done["portal_app:test_app_1"])
+
+	resumed, err := ImportSnapshot(ctx, writer, snapshot, ImportOptions{Progress: &progress, Checkpoint: checkpoint})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, writer.chainsCreated, "chains replayed before the failure are not replayed again")
+	assert.NotEmpty(t, resumed.IDs[SnapshotKindPortalApp]["test_app_1"])
+
+	accounts, err := writer.GetAllAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2, "the imported account and the owner's personal account")
+
+	_, err = ImportSnapshot(ctx, writer, snapshot, ImportOptions{Progress: &ImportProgress{Checksum: "other"}})
+	assert.ErrorIs(t, err, errSnapshotProgressMismatch)
+}
+
+func Test_ReadSnapshot(t *testing.T) {
+	archive := string(newTestSnapshot(t))
+	lines := strings.SplitAfter(archive, "\n")
+
+	tests := []struct {
+		name        string
+		archive     string
+		expectedErr error
+	}{
+		{
+			name:    "Should read a valid archive",
+			archive: archive,
+		},
+		{
+			name:        "Should fail on a tampered record",
+			archive:     strings.Replace(archive, "pokt_app_1", "pokt_app_9", 1),
+			expectedErr: errSnapshotChecksumMismatch,
+		},
+		{
+			name:        "Should fail on a removed record",
+			archive:     lines[0] + strings.Join(lines[2:], ""),
+			expectedErr: errSnapshotChecksumMismatch,
+		},
+		{
+			name:        "Should fail on a truncated archive",
+			archive:     strings.Join(lines[:len(lines)-2], ""),
+			expectedErr: errInvalidSnapshot,
+		},
+		{
+			name:        "Should fail on an unsupported version",
+			archive:     strings.Replace(archive, `"version":1`, `"version":2`, 1),
+			expectedErr: errUnsupportedSnapshot,
+		},
+		{
+			name:        "Should fail without a header",
+			archive:     strings.Join(lines[1:], ""),
+			expectedErr: errInvalidSnapshot,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadSnapshot(strings.NewReader(test.archive))
+			if test.expectedErr != nil {
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_ExportSnapshot_CreatedWhileListing(t *testing.T) {
+	ctx := context.Background()
+	reader := &insertingReader{FakeDBClient: newTestFakeDBClient()}
+
+	var archive bytes.Buffer
+	_, err := ExportSnapshot(ctx, reader, &archive)
+	assert.NoError(t, err)
+	assert.NotNil(t, reader.created)
+
+	snapshot, err := ReadSnapshot(&archive)
+	assert.NoError(t, err)
+
+	var exported *SnapshotRecord
+	for i, record := range snapshot.Records {
+		if record.Kind == SnapshotKindPortalApp && record.ID == string(reader.created.ID) {
+			exported = &snapshot.Records[i]
+		}
+	}
+	if assert.NotNil(t, exported, "a Portal App created between the listings is exported") {
+		assert.False(t, exported.Deleted, "a Portal App created between the listings is not marked deleted")
+	}
+}
@@ -0,0 +1,222 @@
+package dbclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	traceParentHeader = "traceparent"
+	traceStateHeader  = "tracestate"
+
+	// Span attribute keys, following the OpenTelemetry HTTP semantic conventions where one exists
+	AttributeOperation   = "phd.operation"
+	AttributeMethod      = "http.request.method"
+	AttributeURL         = "url.full"
+	AttributeStatusCode  = "http.response.status_code"
+	AttributeResendCount = "http.request.resend_count"
+)
+
+type (
+	// Tracer starts spans for PHD calls: one span per client operation, eg. `GetPortalAppByID`, with a child span
+	// per attempt. It is small enough to back with OpenTelemetry, or use the stdlib-only W3CTracer.
+	Tracer interface {
+		// Start starts a span as a child of the span in ctx, if any, and returns a context carrying the new span
+		Start(ctx context.Context, name string) (context.Context, Span)
+		// Inject writes the trace context of the span in ctx into the outgoing headers, ie. `traceparent` and `tracestate`
+		Inject(ctx context.Context, header http.Header)
+	}
+
+	// Span is a single traced unit of work started by a Tracer
+	Span interface {
+		SetAttribute(key string, value any)
+		RecordError(err error)
+		End()
+	}
+
+	// W3CTracer is a stdlib-only Tracer propagating W3C trace context. Spans continue the trace in the context,
+	// either a span started by the tracer or one set with ContextWithTraceParent, and are passed to OnEnd when they end.
+	W3CTracer struct {
+		// OnEnd is called with every ended span, eg. to export it
+		OnEnd func(span SpanData)
+	}
+
+	// SpanData is the record of a span ended by the W3CTracer
+	SpanData struct {
+		Name         string
+		TraceID      string
+		SpanID       string
+		ParentSpanID string
+		TraceState   string
+		Start, End   time.Time
+		Attributes   map[string]any
+		Err          error
+	}
+
+	// spanContext identifies a span within a trace, as carried by the `traceparent` and `tracestate` headers
+	spanContext struct {
+		traceID, spanID, flags, traceState string
+	}
+
+	// spanContextKey is the context key holding the current spanContext
+	spanContextKey struct{}
+
+	w3cSpan struct {
+		tracer *W3CTracer
+		mu     sync.Mutex
+		data   SpanData
+	}
+
+	noopSpan struct{}
+)
+
+var (
+	_ Tracer = &W3CTracer{}
+	_ Span   = &w3cSpan{}
+
+	traceParentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+	errInvalidTraceParent error = errors.New("invalid traceparent")
+)
+
+// ContextWithTraceParent returns a context continuing the trace of incoming `traceparent` and `tracestate` headers,
+// so that spans started by the W3CTracer become children of the caller's span
+func ContextWithTraceParent(ctx context.Context, traceParent, traceState string) (context.Context, error) {
+	match := traceParentPattern.FindStringSubmatch(traceParent)
+	if match == nil || match[1] == "00000000000000000000000000000000" || match[2] == "0000000000000000" {
+		return ctx, fmt.Errorf("%w: %s", errInvalidTraceParent, traceParent)
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, spanContext{
+		traceID:    match[1],
+		spanID:     match[2],
+		flags:      match[3],
+		traceState: traceState,
+	}), nil
+}
+
+// Start implements Tracer
+func (t *W3CTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, hasParent := ctx.Value(spanContextKey{}).(spanContext)
+
+	current := spanContext{traceID: randomHex(16), spanID: randomHex(8), flags: "01"}
+	if hasParent {
+		current.traceID, current.flags, current.traceState = parent.traceID, parent.flags, parent.traceState
+	}
+
+	span := &w3cSpan{tracer: t, data: SpanData{
+		Name:       name,
+		TraceID:    current.traceID,
+		SpanID:     current.spanID,
+		TraceState: current.traceState,
+		Start:      time.Now(),
+		Attributes: make(map[string]any),
+	}}
+	if hasParent {
+		span.data.ParentSpanID = parent.spanID
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, current), span
+}
+
+// Inject implements Tracer
+func (t *W3CTracer) Inject(ctx context.Context, header http.Header) {
+	current, ok := ctx.Value(spanContextKey{}).(spanContext)
+	if !ok {
+		return
+	}
+
+	header.Set(traceParentHeader, fmt.Sprintf("00-%s-%s-%s", current.traceID, current.spanID, current.flags))
+	if current.traceState != "" {
+		header.Set(traceStateHeader, current.traceState)
+	}
+}
+
+// SetAttribute implements Span
+func (s *w3cSpan) SetAttribute(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes[key] = value
+}
+
+// RecordError implements Span
+func (s *w3cSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Err = err
+}
+
+// End implements Span
+func (s *w3cSpan) End() {
+	s.mu.Lock()
+	s.data.End = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	if s.tracer.OnEnd != nil {
+		s.tracer.OnEnd(data)
+	}
+}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
+
+// startOperationSpan starts the span covering all attempts of a client call
+func (t *retryTransport) startOperationSpan(ctx context.Context, req *http.Request) (context.Context, Span) {
+	if t.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := t.tracer.Start(ctx, operationFromContext(ctx))
+	span.SetAttribute(AttributeOperation, operationFromContext(ctx))
+	span.SetAttribute(AttributeMethod, req.Method)
+	span.SetAttribute(AttributeURL, req.URL.String())
+	return ctx, span
+}
+
+// startAttemptSpan starts the span of a single attempt and returns a copy of the request carrying its trace context
+func (t *retryTransport) startAttemptSpan(ctx context.Context, req *http.Request, attempt int) (*http.Request, Span) {
+	if t.tracer == nil {
+		return req, noopSpan{}
+	}
+
+	ctx, span := t.tracer.Start(ctx, fmt.Sprintf("%s %s", req.Method, req.URL.Path))
+	span.SetAttribute(AttributeMethod, req.Method)
+	span.SetAttribute(AttributeURL, req.URL.String())
+	if attempt > 1 {
+		span.SetAttribute(AttributeResendCount, attempt-1)
+	}
+
+	attemptReq := req.Clone(ctx)
+	t.tracer.Inject(ctx, attemptReq.Header)
+	return attemptReq, span
+}
+
+// endSpan records the outcome of a request on the span and ends it. Responses with a 4xx or 5xx status are errors.
+func endSpan(span Span, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+	case resp != nil:
+		span.SetAttribute(AttributeStatusCode, resp.StatusCode)
+		if resp.StatusCode >= http.StatusBadRequest {
+			span.RecordError(fmt.Errorf("%w. %s", errResponseNotOK, resp.Status))
+		}
+	}
+	span.End()
+}
+
+// randomHex returns n random bytes hex encoded, used for trace and span IDs
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
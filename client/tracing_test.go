@@ -0,0 +1,144 @@
+package dbclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Tracing(t *testing.T) {
+	const (
+		incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		incomingSpanID  = "00f067aa0ba902b7"
+	)
+
+	var attempts atomic.Int32
+	var headersMu sync.Mutex
+	traceParents, traceStates := make([]string, 0), make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headersMu.Lock()
+		traceParents = append(traceParents, r.Header.Get("traceparent"))
+		traceStates = append(traceStates, r.Header.Get("tracestate"))
+		headersMu.Unlock()
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"0001"}`))
+	}))
+	defer server.Close()
+
+	var spansMu sync.Mutex
+	spans := make([]SpanData, 0)
+	client, err := NewDBClient(Config{
+		BaseURL:     server.URL,
+		APIKey:      "test_api_key_6789",
+		Timeout:     5 * time.Second,
+		RetryPolicy: fixedRetryPolicy{retries: 1},
+		Tracer: &W3CTracer{OnEnd: func(span SpanData) {
+			spansMu.Lock()
+			defer spansMu.Unlock()
+			spans = append(spans, span)
+		}},
+	})
+	assert.NoError(t, err)
+
+	ctx, err := ContextWithTraceParent(context.Background(), fmt.Sprintf("00-%s-%s-01", incomingTraceID, incomingSpanID), "vendor=value")
+	assert.NoError(t, err)
+
+	_, err = client.GetChainByID(ctx, "0001")
+	assert.NoError(t, err)
+
+	// Two attempt spans end before the operation span
+	assert.Len(t, spans, 3)
+	firstAttempt, secondAttempt, operation := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "GetChainByID", operation.Name)
+	assert.Equal(t, incomingSpanID, operation.ParentSpanID)
+	assert.Equal(t, http.StatusOK, operation.Attributes[AttributeStatusCode])
+	assert.NoError(t, operation.Err)
+
+	assert.Equal(t, "GET /v2/chain/0001", firstAttempt.Name)
+	assert.Equal(t, operation.SpanID, firstAttempt.ParentSpanID)
+	assert.Equal(t, http.StatusServiceUnavailable, firstAttempt.Attributes[AttributeStatusCode])
+	assert.ErrorIs(t, firstAttempt.Err, errResponseNotOK)
+	assert.NotContains(t, firstAttempt.Attributes, AttributeResendCount)
+
+	assert.Equal(t, operation.SpanID, secondAttempt.ParentSpanID)
+	assert.Equal(t, 1, secondAttempt.Attributes[AttributeResendCount])
+	assert.Equal(t, server.URL+"/v2/chain/0001", secondAttempt.Attributes[AttributeURL])
+
+	for _, span := range spans {
+		assert.Equal(t, incomingTraceID, span.TraceID)
+		assert.Equal(t, "vendor=value", span.TraceState)
+	}
+
+	// Every attempt carries its own span as the parent of the server side
+	assert.Equal(t, []string{
+		fmt.Sprintf("00-%s-%s-01", incomingTraceID, firstAttempt.SpanID),
+		fmt.Sprintf("00-%s-%s-01", incomingTraceID, secondAttempt.SpanID),
+	}, traceParents)
+	assert.Equal(t, []string{"vendor=value", "vendor=value"}, traceStates)
+}
+
+func Test_Tracing_Disabled(t *testing.T) {
+	var traceParent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent.Store(r.Header.Get("traceparent"))
+		_, _ = w.Write([]byte(`{"id":"0001"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewDBClient(Config{BaseURL: server.URL, APIKey: "test_api_key_6789", Timeout: 5 * time.Second})
+	assert.NoError(t, err)
+
+	ctx, err := ContextWithTraceParent(context.Background(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "")
+	assert.NoError(t, err)
+
+	_, err = client.GetChainByID(ctx, "0001")
+	assert.NoError(t, err)
+	assert.Equal(t, "", traceParent.Load())
+}
+
+func Test_ContextWithTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		expectedErr error
+	}{
+		{
+			name:        "Should accept a valid traceparent",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+		{
+			name:        "Should reject an unsupported version",
+			traceParent: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedErr: errInvalidTraceParent,
+		},
+		{
+			name:        "Should reject an all zero trace ID",
+			traceParent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectedErr: errInvalidTraceParent,
+		},
+		{
+			name:        "Should reject upper case hex",
+			traceParent: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			expectedErr: errInvalidTraceParent,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ContextWithTraceParent(context.Background(), test.traceParent, "")
+			assert.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
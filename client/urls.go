@@ -0,0 +1,44 @@
+package dbclient
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// validateBaseURL ensures a base URL is an absolute http(s) URL without a query or fragment,
+// so that endpoints can be built by appending escaped path segments to it
+func validateBaseURL(baseURL string) error {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("%w: %s", errInvalidBaseURL, baseURL)
+	}
+	return nil
+}
+
+// buildEndpoint joins the base URL, which must not end in a slash, and the escaped path segments
+func buildEndpoint(baseURL string, segments ...string) string {
+	var endpoint strings.Builder
+	endpoint.WriteString(baseURL)
+	for _, segment := range segments {
+		endpoint.WriteByte('/')
+		endpoint.WriteString(escapePathSegment(segment))
+	}
+	return endpoint.String()
+}
+
+// escapePathSegment escapes a single path segment, including `/` and dot segments, so IDs can't change the route
+func escapePathSegment(segment string) string {
+	if segment == "." || segment == ".." {
+		return strings.Repeat("%2E", len(segment))
+	}
+	return url.PathEscape(segment)
+}
+
+// withQuery appends the encoded query to the endpoint, if it has any values
+func withQuery(endpoint string, query url.Values) string {
+	if len(query) == 0 {
+		return endpoint
+	}
+	return endpoint + "?" + query.Encode()
+}
@@ -0,0 +1,509 @@
+package dbclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+const (
+	// BackpressureBlock waits for a full channel to be read, delaying the next poll
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureDrop drops events that don't fit in a full channel and counts them in WatcherStats.Dropped
+	BackpressureDrop
+)
+
+const (
+	defaultWatchInterval   = 30 * time.Second
+	defaultWatchBufferSize = 100
+)
+
+var errWatcherClosed error = errors.New("watcher closed")
+
+type (
+	// EventType is the kind of change an Event reports
+	EventType string
+
+	// BackpressurePolicy decides what a Watcher does when an event channel is full
+	BackpressurePolicy int
+
+	// Event is a change to a single entity found by a Watcher
+	Event[T any] struct {
+		Type EventType
+		Kind SnapshotKind
+		ID   string
+		// Old is nil for created entities and New is nil for deleted ones
+		Old, New *T
+	}
+
+	// WatcherConfig configures a Watcher
+	WatcherConfig struct {
+		// Interval between polls. Defaults to 30 seconds.
+		Interval time.Duration
+		// BufferSize is the capacity of each event channel. Defaults to 100.
+		BufferSize int
+		// Backpressure decides what happens when an event channel is full. Defaults to BackpressureBlock.
+		Backpressure BackpressurePolicy
+		// State resumes watching from a state saved by OnState, so changes made while stopped are emitted
+		State *WatcherState
+		// OnState is called with the new state after every poll, eg. to save it to a file
+		OnState func(state *WatcherState)
+		// OnError is called for every collection that fails to be polled
+		OnError func(err error)
+	}
+
+	// WatcherState is the last seen version of every watched entity, by kind and ID
+	WatcherState struct {
+		Entities map[SnapshotKind]map[string]json.RawMessage `json:"entities"`
+	}
+
+	// WatcherStats contains the counters of a Watcher
+	WatcherStats struct {
+		Polls, Events, Dropped uint64
+	}
+
+	// Watcher polls the collections of an IDBReader and emits an Event for every created, updated and deleted
+	// chain, Gigastake app, Portal App, Account and blocked contract, on channels or callbacks per entity kind.
+	// Subscribe before calling Run. The first poll without a saved state only records the current entities.
+	Watcher struct {
+		reader IDBReader
+		config WatcherConfig
+
+		chains           *watchedCollection[types.Chain]
+		gigastakeApps    *watchedCollection[types.GigastakeApp]
+		portalApps       *watchedCollection[types.PortalApp]
+		accounts         *watchedCollection[types.Account]
+		blockedContracts *watchedCollection[types.BlockedContract]
+		collections      []collectionWatcher
+
+		polls, events, dropped atomic.Uint64
+
+		// pollMu serializes polls, so Run can't close the channels while a Poll is delivering
+		pollMu sync.Mutex
+		closed bool
+	}
+
+	// collectionWatcher is the part of a watchedCollection that doesn't depend on its entity type
+	collectionWatcher interface {
+		watchedKind() SnapshotKind
+		poll(ctx context.Context, w *Watcher) error
+		state() map[string]json.RawMessage
+		restore(entities map[string]json.RawMessage) error
+		close()
+	}
+
+	// watchedCollection holds the last seen entities of one kind and its subscribers
+	watchedCollection[T any] struct {
+		kind      SnapshotKind
+		fetch     func(ctx context.Context, reader IDBReader) (map[string]*T, error)
+		updatedAt func(*T) time.Time
+
+		mu        sync.Mutex
+		previous  map[string]*T
+		channel   chan Event[T]
+		callbacks []func(Event[T])
+	}
+)
+
+// NewWatcher returns a Watcher polling the given reader, resuming from config.State if set
+func NewWatcher(reader IDBReader, config WatcherConfig) (*Watcher, error) {
+	if config.Interval <= 0 {
+		config.Interval = defaultWatchInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultWatchBufferSize
+	}
+
+	w := &Watcher{
+		reader: reader,
+		config: config,
+		chains: &watchedCollection[types.Chain]{
+			kind: SnapshotKindChain,
+			fetch: func(ctx context.Context, reader IDBReader) (map[string]*types.Chain, error) {
+				return fetchWatched(listChains(ctx, reader, ChainOptions{IncludeInactive: BoolPtr(true), ExcludeGigastakeApps: BoolPtr(true)}),
+					func(chain *types.Chain) string { return string(chain.ID) })
+			},
+			updatedAt: func(chain *types.Chain) time.Time { return chain.UpdatedAt },
+		},
+		gigastakeApps: &watchedCollection[types.GigastakeApp]{
+			kind: SnapshotKindGigastakeApp,
+			fetch: func(ctx context.Context, reader IDBReader) (map[string]*types.GigastakeApp, error) {
+				return fetchWatched(listGigastakeApps(ctx, reader, GigastakeAppOptions{}),
+					func(gigastakeApp *types.GigastakeApp) string { return string(gigastakeApp.ID) })
+			},
+			updatedAt: func(gigastakeApp *types.GigastakeApp) time.Time { return gigastakeApp.UpdatedAt },
+		},
+		portalApps: &watchedCollection[types.PortalApp]{
+			kind: SnapshotKindPortalApp,
+			fetch: func(ctx context.Context, reader IDBReader) (map[string]*types.PortalApp, error) {
+				return fetchWatched(listPortalApps(ctx, reader, PortalAppOptions{}),
+					func(portalApp *types.PortalApp) string { return string(portalApp.ID) })
+			},
+			updatedAt: func(portalApp *types.PortalApp) time.Time { return portalApp.UpdatedAt },
+		},
+		accounts: &watchedCollection[types.Account]{
+			kind: SnapshotKindAccount,
+			fetch: func(ctx context.Context, reader IDBReader) (map[string]*types.Account, error) {
+				return fetchWatched(listAccounts(ctx, reader, AccountOptions{}),
+					func(account *types.Account) string { return string(account.ID) })
+			},
+			updatedAt: func(account *types.Account) time.Time { return account.UpdatedAt },
+		},
+		blockedContracts: &watchedCollection[types.BlockedContract]{
+			kind: SnapshotKindBlockedContract,
+			fetch: func(ctx context.Context, reader IDBReader) (map[string]*types.BlockedContract, error) {
+				blockedContracts, err := reader.GetBlockedContracts(ctx)
+				if err != nil {
+					return nil, err
+				}
+				entities := make(map[string]*types.BlockedContract, len(blockedContracts.BlockedAddresses))
+				for address := range blockedContracts.BlockedAddresses {
+					entities[string(address)] = &types.BlockedContract{BlockedAddress: address, Active: true}
+				}
+				return entities, nil
+			},
+			updatedAt: func(*types.BlockedContract) time.Time { return time.Time{} },
+		},
+	}
+	w.collections = []collectionWatcher{w.chains, w.gigastakeApps, w.portalApps, w.accounts, w.blockedContracts}
+
+	if config.State != nil {
+		for _, collection := range w.collections {
+			entities, ok := config.State.Entities[collection.watchedKind()]
+			if !ok {
+				continue
+			}
+			if err := collection.restore(entities); err != nil {
+				return nil, fmt.Errorf("restoring watcher state: %w", err)
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// Chains returns the channel of chain events
+func (w *Watcher) Chains() <-chan Event[types.Chain] {
+	return w.chains.subscribe(w.config.BufferSize)
+}
+
+// GigastakeApps returns the channel of Gigastake app events
+func (w *Watcher) GigastakeApps() <-chan Event[types.GigastakeApp] {
+	return w.gigastakeApps.subscribe(w.config.BufferSize)
+}
+
+// PortalApps returns the channel of Portal App events
+func (w *Watcher) PortalApps() <-chan Event[types.PortalApp] {
+	return w.portalApps.subscribe(w.config.BufferSize)
+}
+
+// Accounts returns the channel of Account events
+func (w *Watcher) Accounts() <-chan Event[types.Account] {
+	return w.accounts.subscribe(w.config.BufferSize)
+}
+
+// BlockedContracts returns the channel of blocked contract events
+func (w *Watcher) BlockedContracts() <-chan Event[types.BlockedContract] {
+	return w.blockedContracts.subscribe(w.config.BufferSize)
+}
+
+// OnChain calls fn for every chain event
+func (w *Watcher) OnChain(fn func(Event[types.Chain])) {
+	w.chains.addCallback(fn)
+}
+
+// OnGigastakeApp calls fn for every Gigastake app event
+func (w *Watcher) OnGigastakeApp(fn func(Event[types.GigastakeApp])) {
+	w.gigastakeApps.addCallback(fn)
+}
+
+// OnPortalApp calls fn for every Portal App event
+func (w *Watcher) OnPortalApp(fn func(Event[types.PortalApp])) {
+	w.portalApps.addCallback(fn)
+}
+
+// OnAccount calls fn for every Account event
+func (w *Watcher) OnAccount(fn func(Event[types.Account])) {
+	w.accounts.addCallback(fn)
+}
+
+// OnBlockedContract calls fn for every blocked contract event
+func (w *Watcher) OnBlockedContract(fn func(Event[types.BlockedContract])) {
+	w.blockedContracts.addCallback(fn)
+}
+
+// Stats returns the current watcher counters
+func (w *Watcher) Stats() WatcherStats {
+	return WatcherStats{Polls: w.polls.Load(), Events: w.events.Load(), Dropped: w.dropped.Load()}
+}
+
+// State returns the last seen version of every watched entity
+func (w *Watcher) State() *WatcherState {
+	state := &WatcherState{Entities: make(map[SnapshotKind]map[string]json.RawMessage)}
+	for _, collection := range w.collections {
+		if entities := collection.state(); entities != nil {
+			state.Entities[collection.watchedKind()] = entities
+		}
+	}
+	return state
+}
+
+// Poll fetches every collection once and emits the changes since the previous poll. A collection that fails
+// to be fetched keeps its previous state, so its changes are emitted by the next poll. If an event can't be
+// delivered before the context is done, the events before it are not emitted again but it and the following ones
+// are. Polls are serialized, so Poll can be called while Run is running; it fails once Run has returned.
+func (w *Watcher) Poll(ctx context.Context) error {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	if w.closed {
+		return errWatcherClosed
+	}
+	w.polls.Add(1)
+
+	var errs []error
+	for _, collection := range w.collections {
+		if err := collection.poll(ctx, w); err != nil {
+			if w.config.OnError != nil && ctx.Err() == nil {
+				w.config.OnError(err)
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	if w.config.OnState != nil {
+		w.config.OnState(w.State())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run polls immediately and then on every interval until the context is cancelled, then closes the event channels
+func (w *Watcher) Run(ctx context.Context) {
+	defer w.close()
+
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		_ = w.Poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// close closes the event channels once no Poll is delivering
+func (w *Watcher) close() {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	w.closed = true
+	for _, collection := range w.collections {
+		collection.close()
+	}
+}
+
+func (c *watchedCollection[T]) watchedKind() SnapshotKind {
+	return c.kind
+}
+
+func (c *watchedCollection[T]) subscribe(bufferSize int) <-chan Event[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channel == nil {
+		c.channel = make(chan Event[T], bufferSize)
+	}
+	return c.channel
+}
+
+func (c *watchedCollection[T]) addCallback(fn func(Event[T])) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.callbacks = append(c.callbacks, fn)
+}
+
+func (c *watchedCollection[T]) poll(ctx context.Context, w *Watcher) error {
+	current, err := c.fetch(ctx, w.reader)
+	if err != nil {
+		return fmt.Errorf("polling %s: %w", c.kind, err)
+	}
+
+	c.mu.Lock()
+	previous, channel, callbacks := c.previous, c.channel, c.callbacks
+	c.mu.Unlock()
+
+	// Without a previous state there is nothing to compare against yet
+	if previous == nil {
+		c.setPrevious(current)
+		return nil
+	}
+
+	// The state advances with every delivered event, so a failed delivery doesn't emit the earlier ones again
+	delivered := make(map[string]*T, len(previous))
+	for id, entity := range previous {
+		delivered[id] = entity
+	}
+	for _, event := range c.diff(previous, current) {
+		if err := c.deliver(ctx, w, channel, callbacks, event); err != nil {
+			c.setPrevious(delivered)
+			return fmt.Errorf("delivering %s events: %w", c.kind, err)
+		}
+		if event.Type == EventDeleted {
+			delete(delivered, event.ID)
+		} else {
+			delivered[event.ID] = event.New
+		}
+	}
+
+	c.setPrevious(current)
+	return nil
+}
+
+// diff compares the current entities with the previous ones by the deep equality of their JSON encoding, which
+// is also what a restored state holds. An entity whose UpdatedAt went back in time was read from a lagging
+// replica, so its previous version is kept.
+func (c *watchedCollection[T]) diff(previous, current map[string]*T) []Event[T] {
+	events := make([]Event[T], 0)
+
+	for _, id := range sortedKeys(current) {
+		entity := current[id]
+		old, ok := previous[id]
+		switch {
+		case !ok:
+			events = append(events, Event[T]{Type: EventCreated, Kind: c.kind, ID: id, New: entity})
+		case c.updatedAt(entity).Before(c.updatedAt(old)):
+			current[id] = old
+		case !jsonEqual(old, entity):
+			events = append(events, Event[T]{Type: EventUpdated, Kind: c.kind, ID: id, Old: old, New: entity})
+		}
+	}
+
+	for _, id := range sortedKeys(previous) {
+		if _, ok := current[id]; !ok {
+			events = append(events, Event[T]{Type: EventDeleted, Kind: c.kind, ID: id, Old: previous[id]})
+		}
+	}
+
+	return events
+}
+
+// deliver sends the event to the channel and then to the callbacks, so an event the channel doesn't take before
+// the context is done reaches neither
+func (c *watchedCollection[T]) deliver(ctx context.Context, w *Watcher, channel chan Event[T], callbacks []func(Event[T]), event Event[T]) error {
+	if channel != nil {
+		if err := c.send(ctx, w, channel, event); err != nil {
+			return err
+		}
+	}
+
+	for _, callback := range callbacks {
+		callback(event)
+	}
+	w.events.Add(1)
+	return nil
+}
+
+func (c *watchedCollection[T]) send(ctx context.Context, w *Watcher, channel chan Event[T], event Event[T]) error {
+	if w.config.Backpressure == BackpressureDrop {
+		select {
+		case channel <- event:
+		default:
+			w.dropped.Add(1)
+		}
+		return nil
+	}
+
+	select {
+	case channel <- event:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *watchedCollection[T]) setPrevious(current map[string]*T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.previous = current
+}
+
+func (c *watchedCollection[T]) state() map[string]json.RawMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.previous == nil {
+		return nil
+	}
+
+	entities := make(map[string]json.RawMessage, len(c.previous))
+	for id, entity := range c.previous {
+		entityJSON, err := json.Marshal(entity)
+		if err != nil {
+			continue
+		}
+		entities[id] = entityJSON
+	}
+	return entities
+}
+
+func (c *watchedCollection[T]) restore(entities map[string]json.RawMessage) error {
+	previous := make(map[string]*T, len(entities))
+	for id, entityJSON := range entities {
+		var entity T
+		if err := json.Unmarshal(entityJSON, &entity); err != nil {
+			return fmt.Errorf("%s %s: %w", c.kind, id, err)
+		}
+		previous[id] = &entity
+	}
+
+	c.setPrevious(previous)
+	return nil
+}
+
+func (c *watchedCollection[T]) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channel != nil {
+		close(c.channel)
+		c.channel = nil
+	}
+}
+
+// fetchWatched reads every item of the iterator by ID
+func fetchWatched[T any](items *Iterator[T], id func(*T) string) (map[string]*T, error) {
+	defer items.Close()
+
+	entities := make(map[string]*T)
+	for items.Next() {
+		item := items.Value()
+		entities[id(item)] = item
+	}
+	return entities, items.Err()
+}
+
+func jsonEqual(a, b any) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
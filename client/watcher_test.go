@@ -0,0 +1,265 @@
+package dbclient
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type watchedEvent struct {
+	kind      SnapshotKind
+	eventType EventType
+	id        string
+}
+
+func Test_Watcher_Poll(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		change         func(t *testing.T, db *FakeDBClient)
+		expectedEvents []watchedEvent
+	}{
+		{
+			name:           "Should emit nothing without changes",
+			change:         func(t *testing.T, db *FakeDBClient) {},
+			expectedEvents: []watchedEvent{},
+		},
+		{
+			name: "Should emit an update for a deactivated chain",
+			change: func(t *testing.T, db *FakeDBClient) {
+				_, err := db.ActivateChain(ctx, "0001", false)
+				assert.NoError(t, err)
+			},
+			expectedEvents: []watchedEvent{{SnapshotKindChain, EventUpdated, "0001"}},
+		},
+		{
+			name: "Should emit a creation for a blocked contract",
+			change: func(t *testing.T, db *FakeDBClient) {
+				_, err := db.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xnew_blocked", Active: true})
+				assert.NoError(t, err)
+			},
+			expectedEvents: []watchedEvent{{SnapshotKindBlockedContract, EventCreated, "0xnew_blocked"}},
+		},
+		{
+			name: "Should emit a deletion for a deleted account",
+			change: func(t *testing.T, db *FakeDBClient) {
+				_, err := db.DeleteAccount(ctx, "account_1")
+				assert.NoError(t, err)
+			},
+			expectedEvents: []watchedEvent{{SnapshotKindAccount, EventDeleted, "account_1"}},
+		},
+		{
+			name: "Should ignore an older version read from a lagging replica",
+			change: func(t *testing.T, db *FakeDBClient) {
+				db.chains["0001"].Active = false
+				db.chains["0001"].UpdatedAt = db.chains["0001"].UpdatedAt.Add(-time.Hour)
+			},
+			expectedEvents: []watchedEvent{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestFakeDBClient()
+			db.chains["0001"].UpdatedAt = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+			watcher, err := NewWatcher(db, WatcherConfig{})
+			assert.NoError(t, err)
+
+			events := make([]watchedEvent, 0)
+			watcher.OnChain(func(event Event[types.Chain]) {
+				events = append(events, watchedEvent{event.Kind, event.Type, event.ID})
+			})
+			watcher.OnAccount(func(event Event[types.Account]) {
+				events = append(events, watchedEvent{event.Kind, event.Type, event.ID})
+			})
+			watcher.OnBlockedContract(func(event Event[types.BlockedContract]) {
+				events = append(events, watchedEvent{event.Kind, event.Type, event.ID})
+			})
+
+			assert.NoError(t, watcher.Poll(ctx))
+			assert.Empty(t, events, "the first poll only records the current state")
+
+			test.change(t, db)
+			assert.NoError(t, watcher.Poll(ctx))
+			assert.Equal(t, test.expectedEvents, events)
+		})
+	}
+}
+
+func Test_Watcher_PortalAppUpdate(t *testing.T) {
+	ctx := context.Background()
+	db := newTestFakeDBClient()
+
+	watcher, err := NewWatcher(db, WatcherConfig{})
+	assert.NoError(t, err)
+	portalApps := watcher.PortalApps()
+	assert.NoError(t, watcher.Poll(ctx))
+
+	_, err = db.UpdatePortalApp(ctx, types.UpdatePortalApp{AppID: "test_app_1", Name: "renamed_app"})
+	assert.NoError(t, err)
+	assert.NoError(t, watcher.Poll(ctx))
+
+	event := <-portalApps
+	assert.Equal(t, EventUpdated, event.Type)
+	assert.Equal(t, "pokt_app_1", event.Old.Name)
+	assert.Equal(t, "renamed_app", event.New.Name)
+	assert.Empty(t, portalApps)
+}
+
+func Test_Watcher_ResumesFromState(t *testing.T) {
+	ctx := context.Background()
+	db := newTestFakeDBClient()
+
+	var saved []byte
+	watcher, err := NewWatcher(db, WatcherConfig{OnState: func(state *WatcherState) {
+		saved, _ = json.Marshal(state)
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, watcher.Poll(ctx))
+
+	// Changes made while no watcher runs are emitted by the next one
+	_, err = db.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: "0xnew_blocked", Active: true})
+	assert.NoError(t, err)
+
+	var state WatcherState
+	assert.NoError(t, json.Unmarshal(saved, &state))
+	resumed, err := NewWatcher(db, WatcherConfig{State: &state})
+	assert.NoError(t, err)
+	blockedContracts, chains := resumed.BlockedContracts(), resumed.Chains()
+	assert.NoError(t, resumed.Poll(ctx))
+
+	event := <-blockedContracts
+	assert.Equal(t, EventCreated, event.Type)
+	assert.Equal(t, "0xnew_blocked", event.ID)
+	assert.Empty(t, chains, "restored entities compare equal to the fetched ones")
+}
+
+func Test_Watcher_Backpressure(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Should drop events that don't fit the channel", func(t *testing.T) {
+		db := newTestFakeDBClient()
+		watcher, err := NewWatcher(db, WatcherConfig{BufferSize: 1, Backpressure: BackpressureDrop})
+		assert.NoError(t, err)
+		blockedContracts := watcher.BlockedContracts()
+		assert.NoError(t, watcher.Poll(ctx))
+
+		for _, address := range []types.BlockedAddress{"0xnew_blocked_1", "0xnew_blocked_2"} {
+			_, err := db.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: address, Active: true})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, watcher.Poll(ctx))
+
+		assert.Equal(t, "0xnew_blocked_1", (<-blockedContracts).ID)
+		assert.Equal(t, WatcherStats{Polls: 2, Events: 2, Dropped: 1}, watcher.Stats())
+	})
+
+	t.Run("Should emit only the undelivered events again when blocked events can't be delivered", func(t *testing.T) {
+		db := newTestFakeDBClient()
+		watcher, err := NewWatcher(db, WatcherConfig{BufferSize: 1})
+		assert.NoError(t, err)
+		blockedContracts := watcher.BlockedContracts()
+		var called []string
+		watcher.OnBlockedContract(func(event Event[types.BlockedContract]) { called = append(called, event.ID) })
+		assert.NoError(t, watcher.Poll(ctx))
+
+		for _, address := range []types.BlockedAddress{"0xnew_blocked_1", "0xnew_blocked_2"} {
+			_, err := db.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: address, Active: true})
+			assert.NoError(t, err)
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, watcher.Poll(timeoutCtx), context.DeadlineExceeded)
+		assert.Equal(t, "0xnew_blocked_1", (<-blockedContracts).ID)
+		assert.Equal(t, []string{"0xnew_blocked_1"}, called, "an event the channel didn't take doesn't reach the callbacks")
+
+		assert.NoError(t, watcher.Poll(ctx))
+		assert.Equal(t, "0xnew_blocked_2", (<-blockedContracts).ID, "only the undelivered change is emitted again")
+		assert.Equal(t, []string{"0xnew_blocked_1", "0xnew_blocked_2"}, called)
+	})
+}
+
+func Test_Watcher_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := newTestFakeDBClient()
+
+	polled := make(chan struct{}, 1)
+	watcher, err := NewWatcher(db, WatcherConfig{Interval: 5 * time.Millisecond, OnState: func(*WatcherState) {
+		select {
+		case polled <- struct{}{}:
+		default:
+		}
+	}})
+	assert.NoError(t, err)
+	chains := watcher.Chains()
+
+	done := make(chan struct{})
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+
+	<-polled
+	_, err = db.ActivateChain(ctx, "0002", true)
+	assert.NoError(t, err)
+
+	event := <-chains
+	assert.Equal(t, EventUpdated, event.Type)
+	assert.Equal(t, "0002", event.ID)
+	assert.True(t, event.New.Active)
+
+	cancel()
+	<-done
+	_, open := <-chains
+	assert.False(t, open, "Run closes the channels when it returns")
+	assert.ErrorIs(t, watcher.Poll(context.Background()), errWatcherClosed)
+}
+
+func Test_Watcher_PollWhileRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := newTestFakeDBClient()
+
+	watcher, err := NewWatcher(db, WatcherConfig{Interval: time.Millisecond, BufferSize: 1})
+	assert.NoError(t, err)
+	chains := watcher.Chains()
+	// The first poll only records the current entities
+	assert.NoError(t, watcher.Poll(ctx))
+
+	done := make(chan struct{})
+	go func() {
+		watcher.Run(ctx)
+		close(done)
+	}()
+
+	var polls sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		polls.Add(1)
+		go func() {
+			defer polls.Done()
+			for watcher.Poll(context.Background()) == nil {
+			}
+		}()
+	}
+
+	var active bool
+	for i := 0; i < 20; i++ {
+		active = !active
+		_, err := db.ActivateChain(ctx, "0002", active)
+		assert.NoError(t, err)
+		<-chains
+	}
+
+	cancel()
+	for range chains {
+	}
+	<-done
+	polls.Wait()
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"github.com/pokt-foundation/portal-db/v2/types"
+)
+
+type (
+	// runFunc runs a command with its positional arguments and returns the result to output
+	runFunc func(ctx context.Context, a *app, args []string) (any, error)
+
+	// command is a single `phdctl <resource> <command>`. setup registers the command's flags and returns the
+	// runFunc reading them.
+	command struct {
+		args    []string
+		summary string
+		write   bool
+		setup   func(fs *flag.FlagSet) runFunc
+	}
+
+	// optionalBool is a boolean flag that is only set on the options if it is passed, so that eg. `--accepted=false`
+	// differs from leaving it out
+	optionalBool struct {
+		value **bool
+	}
+
+	// roleNames is a comma separated list of role names
+	roleNames struct {
+		value *[]types.RoleName
+	}
+)
+
+// resources maps every resource to its commands
+var resources = map[string]map[string]command{
+	"chain": {
+		"get": {args: []string{"chain-id"}, summary: "Get a chain by its relay chain ID", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetChainByID(ctx, types.RelayChainID(args[0]))
+			}
+		}},
+		"list": {summary: "List all chains", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.ChainOptions
+			fs.Var(optionalBool{&options.IncludeInactive}, "include-inactive", "include inactive chains")
+			fs.Var(optionalBool{&options.ExcludeGigastakeApps}, "exclude-gigastake-apps", "leave out the chains' gigastake apps")
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted chains")
+			fs.IntVar(&options.Limit, "page-size", 0, "items fetched per page")
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return collect(a.client.ListChains(ctx, options))
+			}
+		}},
+		"gigastake-apps": {args: []string{"chain-id"}, summary: "List the gigastake apps of a chain", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetAllGigastakeAppsByChain(ctx, types.RelayChainID(args[0]))
+			}
+		}},
+		"create": {summary: "Create a chain and its gigastake apps from a NewChainInput JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, input types.NewChainInput) (any, error) {
+				return a.client.CreateChainAndGigastakeApps(ctx, input)
+			})
+		}},
+		"update": {summary: "Update a chain from an UpdateChain JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, update types.UpdateChain) (any, error) {
+				return a.client.UpdateChain(ctx, update)
+			})
+		}},
+		"activate": {args: []string{"chain-id"}, summary: "Activate a chain, or deactivate it with --off", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			off := fs.Bool("off", false, "deactivate the chain")
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.ActivateChain(ctx, types.RelayChainID(args[0]), !*off)
+			}
+		}},
+	},
+	"gigastake-app": {
+		"get": {args: []string{"gigastake-app-id"}, summary: "Get a gigastake app by its ID", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetGigastakeAppByID(ctx, types.GigastakeAppID(args[0]))
+			}
+		}},
+		"list": {summary: "List all gigastake apps", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.GigastakeAppOptions
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted gigastake apps")
+			fs.IntVar(&options.Limit, "page-size", 0, "items fetched per page")
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return collect(a.client.ListGigastakeApps(ctx, options))
+			}
+		}},
+		"create": {summary: "Create a gigastake app from a GigastakeApp JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, gigastakeApp types.GigastakeApp) (any, error) {
+				return a.client.CreateGigastakeApp(ctx, gigastakeApp)
+			})
+		}},
+		"update": {args: []string{"gigastake-app-id"}, summary: "Update a gigastake app from an UpdateGigastakeApp JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, args []string, update types.UpdateGigastakeApp) (any, error) {
+				return a.client.UpdateGigastakeApp(ctx, types.GigastakeAppID(args[0]), update)
+			})
+		}},
+	},
+	"portal-app": {
+		"get": {args: []string{"portal-app-id"}, summary: "Get a portal app by its ID", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetPortalAppByID(ctx, types.PortalAppID(args[0]))
+			}
+		}},
+		"list": {summary: "List all portal apps", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.PortalAppOptions
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted portal apps")
+			fs.IntVar(&options.Limit, "page-size", 0, "items fetched per page")
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return collect(a.client.ListPortalApps(ctx, options))
+			}
+		}},
+		"middleware": {summary: "List the portal app lites used by the middleware", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return a.client.GetPortalAppsForMiddleware(ctx)
+			}
+		}},
+		"create": {summary: "Create a portal app from a PortalApp JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, portalApp types.PortalApp) (any, error) {
+				return a.client.CreatePortalApp(ctx, portalApp)
+			})
+		}},
+		"update": {summary: "Update a portal app from an UpdatePortalApp JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, update types.UpdatePortalApp) (any, error) {
+				return a.client.UpdatePortalApp(ctx, update)
+			})
+		}},
+		"delete": {args: []string{"portal-app-id"}, summary: "Delete a portal app", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.DeletePortalApp(ctx, types.PortalAppID(args[0]))
+			}
+		}},
+		"first-date-surpassed": {summary: "Set the first date surpassed of portal apps from an UpdateFirstDateSurpassed JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, update types.UpdateFirstDateSurpassed) (any, error) {
+				return a.client.UpdatePortalAppsFirstDateSurpassed(ctx, update)
+			})
+		}},
+	},
+	"account": {
+		"list": {summary: "List all accounts", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.AccountOptions
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted accounts")
+			fs.IntVar(&options.Limit, "page-size", 0, "items fetched per page")
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return collect(a.client.ListAccounts(ctx, options))
+			}
+		}},
+		"create": {args: []string{"owner-user-id"}, summary: "Create an account owned by the user from an Account JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, args []string, account types.Account) (any, error) {
+				return a.client.CreateAccount(ctx, types.UserID(args[0]), account, a.now())
+			})
+		}},
+		"update": {summary: "Update an account from an UpdateAccount JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, update types.UpdateAccount) (any, error) {
+				return a.client.UpdateAccount(ctx, update)
+			})
+		}},
+		"delete": {args: []string{"account-id"}, summary: "Delete an account", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.DeleteAccount(ctx, types.AccountID(args[0]))
+			}
+		}},
+		"create-integration": {args: []string{"account-id"}, summary: "Create the integrations of an account from an AccountIntegrations JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, args []string, integration types.AccountIntegrations) (any, error) {
+				return a.client.CreateAccountIntegration(ctx, types.AccountID(args[0]), integration)
+			})
+		}},
+		"update-integration": {args: []string{"account-id"}, summary: "Update the integrations of an account from an AccountIntegrations JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, args []string, integration types.AccountIntegrations) (any, error) {
+				return a.client.UpdateAccountIntegration(ctx, types.AccountID(args[0]), integration)
+			})
+		}},
+	},
+	"account-user": {
+		"add": {summary: "Add a user to an account from a CreateAccountUserAccess JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, createUser types.CreateAccountUserAccess) (any, error) {
+				return a.client.WriteAccountUser(ctx, createUser, a.now())
+			})
+		}},
+		"set-role": {summary: "Set the role of an account user from an UpdateAccountUserRole JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, updateUser types.UpdateAccountUserRole) (any, error) {
+				return a.client.SetAccountUserRole(ctx, updateUser, a.now())
+			})
+		}},
+		"accept": {summary: "Accept an account invite from an UpdateAcceptAccountUser JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, acceptUser types.UpdateAcceptAccountUser) (any, error) {
+				return a.client.UpdateAcceptAccountUser(ctx, acceptUser, a.now())
+			})
+		}},
+		"remove": {summary: "Remove a user from an account from an UpdateRemoveAccountUser JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, removeUser types.UpdateRemoveAccountUser) (any, error) {
+				return a.client.RemoveAccountUser(ctx, removeUser)
+			})
+		}},
+	},
+	"user": {
+		"get": {args: []string{"user-id"}, summary: "Get a user by portal or provider user ID", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetPortalUser(ctx, args[0])
+			}
+		}},
+		"id": {args: []string{"user-id"}, summary: "Get the portal user ID of a portal or provider user ID", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetPortalUserID(ctx, args[0])
+			}
+		}},
+		"portal-apps": {args: []string{"user-id"}, summary: "List the portal apps of a user", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.PortalAppOptions
+			fs.Var(roleNames{&options.RoleNameFilters}, "roles", "comma separated roles to filter by, eg. OWNER,ADMIN")
+			fs.Var(optionalBool{&options.Accepted}, "accepted", "filter by accepted invites")
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted portal apps")
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetPortalAppsByUser(ctx, types.UserID(args[0]), options)
+			}
+		}},
+		"accounts": {args: []string{"user-id"}, summary: "List the accounts of a user", setup: func(fs *flag.FlagSet) runFunc {
+			var options dbclient.AccountOptions
+			fs.Var(roleNames{&options.RoleNameFilters}, "roles", "comma separated roles to filter by, eg. OWNER,ADMIN")
+			fs.Var(optionalBool{&options.Accepted}, "accepted", "filter by accepted invites")
+			fs.Var(optionalBool{&options.IncludeDeleted}, "include-deleted", "include deleted accounts")
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetUserAccounts(ctx, types.UserID(args[0]), options)
+			}
+		}},
+		"account": {args: []string{"user-id", "account-id"}, summary: "Get an account of a user", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.GetUserAccount(ctx, types.AccountID(args[1]), types.UserID(args[0]))
+			}
+		}},
+		"create": {summary: "Create a user from a CreateUser JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, user types.CreateUser) (any, error) {
+				return a.client.CreateUser(ctx, user)
+			})
+		}},
+		"update": {summary: "Update a user from an UpdateUser JSON file", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return withBody(fs, func(ctx context.Context, a *app, _ []string, user types.UpdateUser) (any, error) {
+				return a.client.UpdateUser(ctx, user)
+			})
+		}},
+		"delete": {args: []string{"user-id"}, summary: "Delete a user", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.DeleteUser(ctx, types.UserID(args[0]))
+			}
+		}},
+	},
+	"plan": {
+		"list": {summary: "List all plans", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return a.client.GetAllPlans(ctx)
+			}
+		}},
+	},
+	"blocked-contract": {
+		"list": {summary: "List all blocked contracts", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, _ []string) (any, error) {
+				return a.client.GetBlockedContracts(ctx)
+			}
+		}},
+		"add": {args: []string{"address"}, summary: "Block a contract address, inactive with --off", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			off := fs.Bool("off", false, "add the blocked contract as inactive")
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.WriteBlockedContract(ctx, types.BlockedContract{BlockedAddress: types.BlockedAddress(args[0]), Active: !*off})
+			}
+		}},
+		"activate": {args: []string{"address"}, summary: "Activate a blocked contract, or deactivate it with --off", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			off := fs.Bool("off", false, "deactivate the blocked contract")
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.UpdateBlockedContractActive(ctx, types.BlockedAddress(args[0]), !*off)
+			}
+		}},
+		"remove": {args: []string{"address"}, summary: "Remove a blocked contract", write: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, a *app, args []string) (any, error) {
+				return a.client.RemoveBlockedContract(ctx, types.BlockedAddress(args[0]))
+			}
+		}},
+	},
+}
+
+// withBody registers the `--file` flag and returns a runFunc decoding the JSON file, or stdin for `-`, into T
+func withBody[T any](fs *flag.FlagSet, call func(ctx context.Context, a *app, args []string, body T) (any, error)) runFunc {
+	path := fs.String("file", "-", "JSON request body file, - for stdin")
+	fs.StringVar(path, "f", "-", "JSON request body file, - for stdin")
+
+	return func(ctx context.Context, a *app, args []string) (any, error) {
+		var reader io.Reader = a.stdin
+		if *path != "-" {
+			file, err := os.Open(*path)
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			reader = file
+		}
+
+		var body T
+		decoder := json.NewDecoder(reader)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&body); err != nil {
+			return nil, fmt.Errorf("invalid %T body: %w", body, err)
+		}
+
+		return call(ctx, a, args, body)
+	}
+}
+
+// collect reads every item of the iterator
+func collect[T any](it *dbclient.Iterator[T]) ([]*T, error) {
+	defer it.Close()
+
+	items := make([]*T, 0)
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+func (b optionalBool) String() string {
+	if b.value == nil || *b.value == nil {
+		return ""
+	}
+	return strconv.FormatBool(**b.value)
+}
+
+func (b optionalBool) Set(value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*b.value = &parsed
+	return nil
+}
+
+func (b optionalBool) IsBoolFlag() bool {
+	return true
+}
+
+func (r roleNames) String() string {
+	if r.value == nil {
+		return ""
+	}
+
+	names := make([]string, len(*r.value))
+	for i, roleName := range *r.value {
+		names[i] = string(roleName)
+	}
+	return strings.Join(names, ",")
+}
+
+func (r roleNames) Set(value string) error {
+	for _, roleName := range strings.Split(value, ",") {
+		*r.value = append(*r.value, types.RoleName(strings.TrimSpace(roleName)))
+	}
+	return nil
+}
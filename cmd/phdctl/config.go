@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultOutput  = outputJSON
+
+	envBaseURL = "PHD_BASE_URL"
+	envAPIKey  = "PHD_API_KEY"
+	envTimeout = "PHD_TIMEOUT"
+	envRetries = "PHD_RETRIES"
+	envConfig  = "PHDCTL_CONFIG"
+	envOutput  = "PHDCTL_OUTPUT"
+)
+
+type (
+	// globalFlags are accepted both before the resource and after the command
+	globalFlags struct {
+		baseURL, apiKey, configPath, output string
+		timeout                             time.Duration
+		retries                             int
+		dryRun                              bool
+		// set holds the names of the flags given on the command line, so a zero value still overrides the environment
+		set map[string]bool
+	}
+
+	// config is the merged configuration from flags, environment variables and the config file
+	config struct {
+		BaseURL string        `yaml:"base_url"`
+		APIKey  string        `yaml:"api_key"`
+		Timeout time.Duration `yaml:"timeout"`
+		Retries int           `yaml:"retries"`
+		Output  string        `yaml:"output"`
+		DryRun  bool          `yaml:"-"`
+	}
+
+	// dryRunTransport records the request instead of sending it
+	dryRunTransport struct {
+		req  *http.Request
+		body []byte
+	}
+)
+
+var (
+	errDryRun        error = errors.New("dry run")
+	errInvalidConfig error = errors.New("invalid config")
+)
+
+func (f *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.baseURL, "base-url", f.baseURL, "PHD base URL")
+	fs.StringVar(&f.apiKey, "api-key", f.apiKey, "PHD API key")
+	fs.DurationVar(&f.timeout, "timeout", f.timeout, "request timeout")
+	fs.IntVar(&f.retries, "retries", f.retries, "retries of failed requests")
+	fs.StringVar(&f.configPath, "config", f.configPath, "config file")
+	fs.StringVar(&f.output, "output", f.output, "output format")
+	fs.StringVar(&f.output, "o", f.output, "output format")
+	fs.BoolVar(&f.dryRun, "dry-run", f.dryRun, "print write requests instead of sending them")
+}
+
+// markSet records the flags set explicitly on fs
+func (f *globalFlags) markSet(fs *flag.FlagSet) {
+	if f.set == nil {
+		f.set = make(map[string]bool)
+	}
+	fs.Visit(func(fl *flag.Flag) { f.set[fl.Name] = true })
+}
+
+// loadConfig merges the flags over the environment variables over the config file
+func loadConfig(flags globalFlags, getenv func(string) string) (config, error) {
+	cfg := config{Timeout: defaultTimeout, Output: defaultOutput}
+
+	configPath := firstSet(flags.configPath, getenv(envConfig))
+	if configPath == "" {
+		configPath = defaultConfigPath()
+	}
+	if err := cfg.readFile(configPath, flags.configPath != "" || getenv(envConfig) != ""); err != nil {
+		return cfg, err
+	}
+
+	cfg.BaseURL = firstSet(flags.baseURL, getenv(envBaseURL), cfg.BaseURL)
+	cfg.APIKey = firstSet(flags.apiKey, getenv(envAPIKey), cfg.APIKey)
+	cfg.Output = firstSet(flags.output, getenv(envOutput), cfg.Output)
+	cfg.DryRun = flags.dryRun
+
+	switch {
+	case flags.set["timeout"]:
+		cfg.Timeout = flags.timeout
+	case getenv(envTimeout) != "":
+		timeout, err := time.ParseDuration(getenv(envTimeout))
+		if err != nil {
+			return cfg, fmt.Errorf("%w: %s: %s", errInvalidConfig, envTimeout, err)
+		}
+		cfg.Timeout = timeout
+	}
+
+	switch {
+	case flags.set["retries"]:
+		cfg.Retries = flags.retries
+	case getenv(envRetries) != "":
+		retries, err := strconv.Atoi(getenv(envRetries))
+		if err != nil {
+			return cfg, fmt.Errorf("%w: %s: %s", errInvalidConfig, envRetries, err)
+		}
+		cfg.Retries = retries
+	}
+
+	if !isOutputFormat(cfg.Output) {
+		return cfg, fmt.Errorf("%w: unknown output format %q", errInvalidConfig, cfg.Output)
+	}
+
+	return cfg, nil
+}
+
+// readFile reads the YAML or JSON config file. A missing file is only an error if it was asked for explicitly.
+func (c *config) readFile(path string, required bool) error {
+	if path == "" {
+		return nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidConfig, err)
+	}
+
+	if err := yaml.Unmarshal(contents, c); err != nil {
+		return fmt.Errorf("%w: %s: %s", errInvalidConfig, path, err)
+	}
+	return nil
+}
+
+func (c config) clientConfig() dbclient.Config {
+	return dbclient.Config{
+		BaseURL: c.BaseURL,
+		APIKey:  c.APIKey,
+		Timeout: c.Timeout,
+		Retries: c.Retries,
+	}
+}
+
+// defaultConfigPath returns `phdctl/config.yaml` in the user config dir, eg. `~/.config/phdctl/config.yaml`
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "phdctl", "config.yaml")
+}
+
+func firstSet(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// RoundTrip records the request and fails with errDryRun so that nothing is sent
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		t.body = body
+	}
+	return nil, errDryRun
+}
+
+// print writes the recorded request with the API key redacted
+func (t *dryRunTransport) print(w io.Writer) error {
+	fmt.Fprintf(w, "%s %s\n", t.req.Method, t.req.URL)
+
+	header := t.req.Header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", "[REDACTED]")
+	}
+	if err := header.Write(w); err != nil {
+		return err
+	}
+
+	if len(t.body) > 0 {
+		var indented bytes.Buffer
+		if json.Indent(&indented, t.body, "", "  ") == nil {
+			t.body = indented.Bytes()
+		}
+		fmt.Fprintf(w, "\n%s\n", t.body)
+	}
+	return nil
+}
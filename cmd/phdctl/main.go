@@ -0,0 +1,211 @@
+// Command phdctl inspects and fixes Portal HTTP DB data from the command line, exposing the
+// IDBReader and IDBWriter methods of the db client as subcommands.
+//
+//	phdctl [flags] <resource> <command> [flags] [args]
+//
+// For example `phdctl portal-app get <id>`, `phdctl chain activate <id> --off` or
+// `phdctl blocked-contract add <address> --dry-run`. Run `phdctl help` for the full list.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+)
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+type (
+	// dbClient is the client commands run against; the IDBClient returned by NewDBClient also implements IDBLister
+	dbClient interface {
+		dbclient.IDBClient
+		dbclient.IDBLister
+	}
+
+	// app holds what a command needs to run
+	app struct {
+		client dbClient
+		stdin  io.Reader
+		now    func() time.Time
+	}
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run executes phdctl with the given arguments and returns its exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	var flags globalFlags
+	root := flag.NewFlagSet("phdctl", flag.ContinueOnError)
+	root.SetOutput(io.Discard)
+	flags.register(root)
+
+	// Flags before the resource are parsed here, the rest along with the command's own flags
+	err := root.Parse(args)
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		return usageError(stderr, err)
+	}
+	flags.markSet(root)
+	rest := root.Args()
+	if err != nil || len(rest) == 0 || rest[0] == "help" {
+		printUsage(stdout)
+		return exitOK
+	}
+
+	resource, ok := resources[rest[0]]
+	if !ok {
+		return usageError(stderr, fmt.Errorf("unknown resource %q", rest[0]))
+	}
+	if len(rest) < 2 {
+		return usageError(stderr, fmt.Errorf("missing command for %s", rest[0]))
+	}
+	cmd, ok := resource[rest[1]]
+	if !ok {
+		return usageError(stderr, fmt.Errorf("unknown command %q for %s", rest[1], rest[0]))
+	}
+
+	fs := flag.NewFlagSet(rest[0]+" "+rest[1], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	flags.register(fs)
+	var runCmd runFunc
+	if cmd.setup != nil {
+		runCmd = cmd.setup(fs)
+	}
+
+	cmdArgs, err := parseInterspersed(fs, rest[2:])
+	if err != nil {
+		return usageError(stderr, err)
+	}
+	flags.markSet(fs)
+	if len(cmdArgs) != len(cmd.args) {
+		return usageError(stderr, fmt.Errorf("usage: phdctl %s %s %s", rest[0], rest[1], formatArgs(cmd.args)))
+	}
+
+	config, err := loadConfig(flags, getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "phdctl: %s\n", err)
+		return exitError
+	}
+
+	var recorder *dryRunTransport
+	clientConfig := config.clientConfig()
+	if config.DryRun && cmd.write {
+		recorder = &dryRunTransport{}
+		clientConfig.Transport = recorder
+		clientConfig.Retries, clientConfig.RetryPolicy = 0, nil
+	}
+
+	client, err := dbclient.NewDBClient(clientConfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "phdctl: %s\n", err)
+		return exitError
+	}
+
+	result, err := runCmd(ctx, &app{client: client.(dbClient), stdin: stdin, now: time.Now}, cmdArgs)
+	if recorder != nil && errors.Is(err, errDryRun) {
+		if err := recorder.print(stdout); err != nil {
+			fmt.Fprintf(stderr, "phdctl: %s\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "phdctl: %s\n", err)
+		return exitError
+	}
+
+	if err := writeOutput(stdout, config.Output, result); err != nil {
+		fmt.Fprintf(stderr, "phdctl: %s\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// parseInterspersed parses the flags in args wherever they appear, returning the positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usageError(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "phdctl: %s\nRun 'phdctl help' for usage.\n", err)
+	return exitUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `phdctl inspects and fixes Portal HTTP DB data.
+
+Usage:
+  phdctl [flags] <resource> <command> [flags] [args]
+
+Flags:
+  --base-url string   PHD base URL (env PHD_BASE_URL)
+  --api-key string    PHD API key (env PHD_API_KEY)
+  --timeout duration  request timeout (env PHD_TIMEOUT, default 10s)
+  --retries int       retries of failed requests (env PHD_RETRIES)
+  --config string     YAML or JSON config file (env PHDCTL_CONFIG)
+  -o, --output string json, yaml or table (env PHDCTL_OUTPUT, default json)
+  --dry-run           print the request a write would send instead of sending it
+
+Flags override environment variables, which override the config file.
+
+Commands:
+`)
+
+	resourceNames := make([]string, 0, len(resources))
+	for name := range resources {
+		resourceNames = append(resourceNames, name)
+	}
+	sort.Strings(resourceNames)
+
+	for _, resourceName := range resourceNames {
+		commandNames := make([]string, 0, len(resources[resourceName]))
+		for name := range resources[resourceName] {
+			commandNames = append(commandNames, name)
+		}
+		sort.Strings(commandNames)
+
+		for _, commandName := range commandNames {
+			cmd := resources[resourceName][commandName]
+			usage := strings.TrimSpace(fmt.Sprintf("%s %s %s", resourceName, commandName, formatArgs(cmd.args)))
+			fmt.Fprintf(w, "  %-40s %s\n", usage, cmd.summary)
+		}
+	}
+}
+
+func formatArgs(args []string) string {
+	formatted := make([]string, len(args))
+	for i, arg := range args {
+		formatted[i] = "<" + arg + ">"
+	}
+	return strings.Join(formatted, " ")
+}
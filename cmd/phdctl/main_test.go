@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dbclient "github.com/pokt-foundation/db-client/v2/client"
+	"github.com/pokt-foundation/db-client/v2/client/phdtest"
+	"github.com/pokt-foundation/portal-db/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *phdtest.Server {
+	server, err := phdtest.NewServer(phdtest.Config{
+		Seed: &dbclient.FakeDBClientSeed{
+			Chains: map[types.RelayChainID]*types.Chain{
+				"0001": {ID: "0001", Active: true},
+				"0002": {ID: "0002", Active: false},
+			},
+			PortalApps: map[types.PortalAppID]*types.PortalApp{
+				"test_app_1": {ID: "test_app_1", AccountID: "account_1", Name: "pokt_app_1"},
+			},
+			Accounts: map[types.AccountID]*types.Account{
+				"account_1": {ID: "account_1", PlanType: "basic_plan"},
+			},
+			BlockedContracts: types.GlobalBlockedContracts{
+				BlockedAddresses: map[types.BlockedAddress]struct{}{"0xtest_blocked": {}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_Run(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		env              map[string]string
+		configFile       string
+		stdin            string
+		expectedCode     int
+		expectedStdout   []string
+		unexpectedStdout []string
+		expectedStderr   string
+		check            func(t *testing.T, db *dbclient.FakeDBClient)
+	}{
+		{
+			name:           "Should get a portal app as JSON",
+			args:           []string{"portal-app", "get", "test_app_1"},
+			expectedStdout: []string{`"id": "test_app_1"`, `"name": "pokt_app_1"`},
+		},
+		{
+			name:           "Should list chains as a table with flags after the command",
+			args:           []string{"chain", "list", "--include-inactive", "-o", "table"},
+			expectedStdout: []string{"ID ", "\n0001 ", "\n0002 "},
+		},
+		{
+			name:           "Should write YAML output",
+			args:           []string{"--output", "yaml", "chain", "get", "0001"},
+			expectedStdout: []string{"id: \"0001\"", "active: true"},
+		},
+		{
+			name:           "Should deactivate a chain with a flag after the argument",
+			args:           []string{"chain", "activate", "0001", "--off"},
+			expectedStdout: []string{"false"},
+			check: func(t *testing.T, db *dbclient.FakeDBClient) {
+				chain, err := db.GetChainByID(context.Background(), "0001")
+				assert.NoError(t, err)
+				assert.False(t, chain.Active)
+			},
+		},
+		{
+			name:             "Should print the request of a write without sending it on a dry run",
+			args:             []string{"blocked-contract", "add", "0xnew_blocked", "--dry-run"},
+			expectedStdout:   []string{"POST http://", "/v2/blocked_contract\n", "Authorization: [REDACTED]", `"blockedAddress": "0xnew_blocked"`},
+			unexpectedStdout: []string{phdtest.DefaultAPIKey},
+			check: func(t *testing.T, db *dbclient.FakeDBClient) {
+				blockedContracts, err := db.GetBlockedContracts(context.Background())
+				assert.NoError(t, err)
+				assert.NotContains(t, blockedContracts.BlockedAddresses, types.BlockedAddress("0xnew_blocked"))
+			},
+		},
+		{
+			name:           "Should read a write body from stdin",
+			args:           []string{"portal-app", "create"},
+			stdin:          `{"id": "test_app_2", "accountID": "account_1", "name": "pokt_app_2"}`,
+			expectedStdout: []string{`"name": "pokt_app_2"`},
+			check: func(t *testing.T, db *dbclient.FakeDBClient) {
+				portalApps, err := db.GetAllPortalApps(context.Background())
+				assert.NoError(t, err)
+				assert.Len(t, portalApps, 2)
+			},
+		},
+		{
+			name:           "Should reject a write body with unknown fields",
+			args:           []string{"portal-app", "create", "-f", "-"},
+			stdin:          `{"nmae": "pokt_app_2"}`,
+			expectedCode:   exitError,
+			expectedStderr: "invalid types.PortalApp body",
+		},
+		{
+			name:             "Should read the connection from the config file and the output from the environment",
+			args:             []string{"chain", "get", "0001"},
+			env:              map[string]string{envOutput: "table"},
+			configFile:       "base_url: {{url}}\napi_key: " + phdtest.DefaultAPIKey + "\noutput: yaml\n",
+			expectedStdout:   []string{"FIELD", "VALUE"},
+			unexpectedStdout: []string{"active: true"},
+		},
+		{
+			name:           "Should prefer flags over the environment",
+			args:           []string{"chain", "get", "0001", "--api-key", "wrong_api_key"},
+			expectedCode:   exitError,
+			expectedStderr: "401",
+		},
+		{
+			name:           "Should fail with a usage error for an unknown resource",
+			args:           []string{"relay", "get", "0001"},
+			expectedCode:   exitUsage,
+			expectedStderr: `unknown resource "relay"`,
+		},
+		{
+			name:           "Should fail with a usage error for missing arguments",
+			args:           []string{"user", "account", "user_1"},
+			expectedCode:   exitUsage,
+			expectedStderr: "usage: phdctl user account <user-id> <account-id>",
+		},
+		{
+			name:           "Should print usage",
+			args:           []string{"help"},
+			expectedStdout: []string{"chain activate <chain-id>", "blocked-contract add <address>"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newTestServer(t)
+
+			env := map[string]string{envBaseURL: server.URL, envAPIKey: phdtest.DefaultAPIKey}
+			if test.configFile != "" {
+				configPath := filepath.Join(t.TempDir(), "config.yaml")
+				assert.NoError(t, os.WriteFile(configPath, []byte(strings.ReplaceAll(test.configFile, "{{url}}", server.URL)), 0o600))
+				env = map[string]string{envConfig: configPath}
+			}
+			for key, value := range test.env {
+				env[key] = value
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), test.args, strings.NewReader(test.stdin), &stdout, &stderr, func(key string) string { return env[key] })
+
+			assert.Equal(t, test.expectedCode, code, stderr.String())
+			for _, expected := range test.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+			for _, unexpected := range test.unexpectedStdout {
+				assert.NotContains(t, stdout.String(), unexpected)
+			}
+			assert.Contains(t, stderr.String(), test.expectedStderr)
+			if test.check != nil {
+				test.check(t, server.DB)
+			}
+		})
+	}
+}
+
+func Test_LoadConfig(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		env             map[string]string
+		configFile      string
+		expectedTimeout time.Duration
+		expectedRetries int
+	}{
+		{
+			name:            "Should read the timeout and retries from the config file",
+			configFile:      "timeout: 5s\nretries: 3\n",
+			expectedTimeout: 5 * time.Second,
+			expectedRetries: 3,
+		},
+		{
+			name:            "Should prefer the environment over the config file",
+			env:             map[string]string{envTimeout: "7s", envRetries: "2"},
+			configFile:      "timeout: 5s\nretries: 3\n",
+			expectedTimeout: 7 * time.Second,
+			expectedRetries: 2,
+		},
+		{
+			name:            "Should prefer flags set to zero over the environment",
+			args:            []string{"--retries", "0", "--timeout", "0s"},
+			env:             map[string]string{envTimeout: "7s", envRetries: "2"},
+			expectedTimeout: 0,
+			expectedRetries: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			assert.NoError(t, os.WriteFile(configPath, []byte(test.configFile), 0o600))
+			env := map[string]string{envConfig: configPath}
+			for key, value := range test.env {
+				env[key] = value
+			}
+
+			var flags globalFlags
+			fs := flag.NewFlagSet("phdctl", flag.ContinueOnError)
+			flags.register(fs)
+			assert.NoError(t, fs.Parse(test.args))
+			flags.markSet(fs)
+
+			config, err := loadConfig(flags, func(key string) string { return env[key] })
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedTimeout, config.Timeout)
+			assert.Equal(t, test.expectedRetries, config.Retries)
+		})
+	}
+}
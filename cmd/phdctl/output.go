@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+func isOutputFormat(format string) bool {
+	return format == outputJSON || format == outputYAML || format == outputTable
+}
+
+// writeOutput writes the result of a command in the given format. YAML and table output are built from the
+// JSON encoding, so that both use the same field names as PHD.
+func writeOutput(w io.Writer, format string, result any) error {
+	if format == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	generic, err := toGeneric(result)
+	if err != nil {
+		return err
+	}
+
+	if format == outputYAML {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(generic)
+	}
+
+	return writeTable(w, generic)
+}
+
+// toGeneric converts the result into the maps, slices and scalars of its JSON encoding
+func toGeneric(result any) (any, error) {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	if err := json.Unmarshal(resultJSON, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// writeTable writes a list of objects as one row per object, with a column per scalar field,
+// and a single object as one row per field
+func writeTable(w io.Writer, generic any) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	switch value := generic.(type) {
+	case []any:
+		columns := tableColumns(value)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, row := range value {
+			object, _ := row.(map[string]any)
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				cells[i] = formatCell(object[column])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case map[string]any:
+		fmt.Fprintln(tw, "FIELD\tVALUE")
+		for _, key := range sortedKeys(value) {
+			fmt.Fprintf(tw, "%s\t%s\n", key, formatCell(value[key]))
+		}
+	default:
+		fmt.Fprintln(tw, formatCell(value))
+	}
+
+	return tw.Flush()
+}
+
+// tableColumns returns the scalar fields of the rows, with the ID first
+func tableColumns(rows []any) []string {
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		object, _ := row.(map[string]any)
+		for key, value := range object {
+			switch value.(type) {
+			case map[string]any, []any:
+				continue
+			}
+			seen[key] = true
+		}
+	}
+
+	columns := sortedKeys(seen)
+	sort.SliceStable(columns, func(i, j int) bool { return columns[i] == "id" && columns[j] != "id" })
+	return columns
+}
+
+// formatCell formats a scalar as is and anything else as compact JSON
+func formatCell(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case map[string]any, []any:
+		valueJSON, _ := json.Marshal(value)
+		return string(valueJSON)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
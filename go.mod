@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/pokt-foundation/portal-db/v2 v2.13.1
 	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -12,5 +13,4 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/pokt-foundation/portal-db v1.11.0 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )